@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	assertCmd.Flags().StringVarP(&flags.AssertSLOFile, constant.KeywordFlagSLO, "", constant.DefaultAssertSLOFile,
+		"Specify path of YAML file with service level objectives")
+	assertCmd.Flags().StringVarP(&flags.AssertJUnitFile, constant.KeywordFlagJUnit, "", "",
+		"Specify path of JUnit XML report (disabled when empty)")
+}
+
+var assertCmd = &cobra.Command{
+	Use:   "assert RESULTS...",
+	Short: "Assert evaluates service level objectives against benchmark results.",
+	Long: `Assert evaluates service level objectives against benchmark results.
+
+RESULTS are files with output of "go test -bench" (plain or -json), e.g. of the broker stress benchmark,
+or of "benchmark" command in gobench or json format, or results saved with "benchmark --save".
+Use "-" to read results from stdin. Output of commands run with --loop is not accepted, as loop summaries
+are only logged.
+
+SLO file lists limits per operation and optionally profile, operation and profile are derived
+from benchmark names, e.g. BenchmarkHashData_profile_Default_Sequential:
+
+  slos:
+    - operation: HashData
+      profile: Default
+      max_mean_latency: 2.5ms  # mean of time per operation samples
+      min_throughput: 400      # operations per second
+      max_error_rate: 0.01     # ratio of failed requests
+      max_allocs: 176          # allocations per operation
+
+Benchmarks that failed to run are skipped, command fails if any SLO is violated or has no results.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		assertCommand, err := command.NewAssert(os.Stdout)
+		if err != nil {
+			slog.Error("Failed to initialize assert command", "error", err)
+			panic(err)
+		}
+
+		err = assertCommand.Run(flags.AssertSLOFile, args, flags.AssertJUnitFile)
+		switch {
+		case errors.Is(err, command.ErrSLOViolated):
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%v\n", err)
+			os.Exit(constant.ExitCodeSLOViolated)
+		case err != nil:
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "failed to assert benchmark results: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/audit"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	auditVerifyCmd.Flags().StringVarP(&flags.AuditVerifyHead, constant.KeywordFlagHead, "", "",
+		"Specify hash of entry the log must contain, e.g. head printed by previous verification, to detect truncated log")
+	auditVerifyCmd.Flags().StringVarP(&flags.AuditVerifyAnchor, constant.KeywordFlagAnchor, "", "",
+		"Specify hash of the last entry of rotated log this log continues (the log must start the chain when empty)")
+	auditCmd.AddCommand(auditVerifyCmd)
+}
+
+// openAuditLog opens audit log configured by AUDIT_LOG environment variable, nil log disables auditing.
+func openAuditLog() (*audit.Log, error) {
+	headPath := os.Getenv(env.AUDIT_HEAD_FILE)
+	if headPath == "" {
+		headPath = audit.DefaultHeadPath()
+	}
+
+	return audit.Open(os.Getenv(env.AUDIT_LOG), headPath)
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit groups commands working with audit log of certificate signing operations.",
+	Args:  cobra.NoArgs,
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify [FILE]",
+	Short: "Verify checks hash chain of audit log of certificate signing operations.",
+	Long: fmt.Sprintf(`Verify checks hash chain of audit log of certificate signing operations.
+
+FILE is audit log written by "sign" command when %s environment variable is set, it defaults to its value.
+Entries sent to syslog can be verified after exporting them to file, syslog header of every line is ignored.
+Command fails if any entry was modified, removed, inserted or reordered. The first entry must start the chain,
+unless hash of the entry it continues, e.g. head of rotated log, is passed with --anchor. Removal of the last
+entries is detected only if head printed by previous verification is passed with --head, entries appended
+since then are accepted.`, env.AUDIT_LOG),
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := os.Getenv(env.AUDIT_LOG)
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		if path == "" || path == audit.TargetSyslog {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "audit log file is required, pass it as argument or set %s\n", env.AUDIT_LOG)
+			os.Exit(1)
+		}
+
+		auditVerifyCommand, err := command.NewAuditVerify(os.Stdout)
+		if err != nil {
+			slog.Error("Failed to initialize audit verify command", "error", err)
+			panic(err)
+		}
+
+		err = auditVerifyCommand.Run(path, flags.AuditVerifyAnchor, flags.AuditVerifyHead)
+		switch {
+		case errors.Is(err, command.ErrAuditChainBroken):
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%v\n", err)
+			os.Exit(constant.ExitCodeAuditChainBroken)
+		case err != nil:
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "failed to verify audit log: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
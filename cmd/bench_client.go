@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/clog"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	benchClientCmd.Flags().StringSliceVarP(&flags.BenchClientOperations, constant.KeywordFlagOperations, "",
+		[]string{constant.BenchOperationHash, constant.BenchOperationSign},
+		fmt.Sprintf("Specify benchmarked operations (%s, %s)", constant.BenchOperationHash, constant.BenchOperationSign))
+	benchClientCmd.Flags().StringSliceVarP(&flags.BenchClientProfiles, constant.KeywordFlagProfiles, "", []string{"Default"},
+		"Specify crypto profiles")
+	benchClientCmd.Flags().StringSliceVarP(&flags.BenchClientSizes, constant.KeywordFlagSizes, "",
+		[]string{"16B", "1KiB", "64KiB", "1MiB"},
+		"Specify hash input sizes in bytes with optional B, KiB, MiB or GiB unit (requests above 4MiB exceed default gRPC message size)")
+	benchClientCmd.Flags().StringSliceVarP(&flags.BenchClientOutputFormats, constant.KeywordFlagOutputFormats, "", []string{"hex"},
+		"Specify hash output formats (hex, raw)")
+	benchClientCmd.Flags().StringSliceVarP(&flags.BenchClientKeyTypes, constant.KeywordFlagKeyTypes, "",
+		[]string{constant.KeyTypeP256, constant.KeyTypeP384, constant.KeyTypeRSA2048},
+		fmt.Sprintf("Specify key types of generated CA and CSR (%s, %s, %s, %s, %s, %s, %s)",
+			constant.KeyTypeP256, constant.KeyTypeP384, constant.KeyTypeP521,
+			constant.KeyTypeRSA2048, constant.KeyTypeRSA3072, constant.KeyTypeRSA4096, constant.KeyTypeEd25519))
+	benchClientCmd.Flags().IntSliceVarP(&flags.BenchClientConcurrency, constant.KeywordFlagConcurrency, "", []int{1, 8},
+		"Specify numbers of concurrent requests")
+	benchClientCmd.Flags().IntVarP(&flags.BenchClientRequests, constant.KeywordFlagRequests, "", constant.DefaultBenchClientRequests,
+		"Specify number of requests per combination")
+	benchClientCmd.Flags().StringVarP(&flags.BenchmarkFormat, constant.KeywordFlagFormat, "", constant.BenchmarkFormatTable,
+		fmt.Sprintf("Specify output format of results (%s, %s, %s or %s, the latter is accepted by benchstat and assert)",
+			constant.BenchmarkFormatTable, constant.BenchmarkFormatJSON, constant.BenchmarkFormatCSV, constant.BenchmarkFormatGoBench))
+	addPoolFlags(benchClientCmd)
+}
+
+var benchClientCmd = &cobra.Command{
+	Use:   "client",
+	Short: "Client runs client-side benchmarks of hash data and sign certificate operations.",
+	Long: `Client runs client-side benchmarks of hash data and sign certificate operations.
+
+Every combination of profile, input size, output format and concurrency level is benchmarked for hash data,
+and every combination of profile, key type and concurrency level for sign certificate.
+CA and CSR of every key type are generated locally before the first request.
+Throughput and latency percentiles of every combination are written to stdout. Requests are not retried by the CLI,
+but the client library retries UNAVAILABLE, RESOURCE_EXHAUSTED and ABORTED responses with backoff before they are
+counted as failed, so measured latency of such requests includes the retries.
+Concurrent requests are distributed across --pool-size library connections.`,
+	Args: cobra.NoArgs,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if err := flags.ValidateFlagPool(flags.PoolSize, flags.PoolHealthInterval, flags.PoolFailureThreshold); err != nil {
+			slog.Error("Invalid library pool flag value", "error", err)
+			panic(err)
+		}
+
+		if err := flags.ValidateFlagBenchmarkFormat(flags.BenchmarkFormat); err != nil {
+			slog.Error("Invalid format flag value", "error", err)
+			panic(err)
+		}
+
+		if err := flags.ValidateFlagBenchClient(flags.BenchClientOperations, flags.BenchClientOutputFormats,
+			flags.BenchClientKeyTypes, flags.BenchClientConcurrency, flags.BenchClientRequests); err != nil {
+			slog.Error("Invalid benchmark client flag value", "error", err)
+			panic(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		sizes, err := flags.ParseFlagSizes(flags.BenchClientSizes)
+		if err != nil {
+			slog.Error("Invalid sizes flag value", "error", err)
+			panic(err)
+		}
+
+		// stdout is reserved for benchmark results
+		clog.SetConsoleOutput(os.Stderr)
+		otel.SetConsoleOutput(os.Stderr)
+
+		logger, tracerProvider, shutdownTelemetry := setupTelemetry(ctx)
+		defer shutdownTelemetry()
+
+		libraries, err := pool.New(ctx, logger, poolConfig())
+		if err != nil {
+			logger.Error("Failed to initialize library pool", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+
+		benchClientCommand, err := command.NewBenchClient(ctx, libraries, logger, tracerProvider)
+		if err != nil {
+			logger.Error("Failed to initialize benchmark client command", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+
+		matrix := command.BenchClientMatrix{
+			Operations:    flags.BenchClientOperations,
+			Profiles:      flags.BenchClientProfiles,
+			Sizes:         sizes,
+			OutputFormats: flags.BenchClientOutputFormats,
+			KeyTypes:      flags.BenchClientKeyTypes,
+			Concurrency:   flags.BenchClientConcurrency,
+			Requests:      flags.BenchClientRequests,
+		}
+		if err := benchClientCommand.Run(ctx, os.Stdout, matrix, flags.BenchmarkFormat); err != nil {
+			logger.Error("Failed to run benchmark client command", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+	},
+}
@@ -1,11 +1,10 @@
 package cmd
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"log/slog"
-	"time"
+	"os"
 
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/clog"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
@@ -19,57 +18,118 @@ import (
 func init() {
 	benchmarkCmd.Flags().IntVarP(&flags.Loop, constant.KeywordFlagLoop, "", constant.NoLoopFlagValue,
 		fmt.Sprintf("Specify delay for loop in milliseconds (%d-%d)", constant.MinLoopFlagValue, constant.MaxLoopFlagValue))
+	benchmarkCmd.Flags().StringVarP(&flags.BenchmarkFormat, constant.KeywordFlagFormat, "", constant.BenchmarkFormatTable,
+		fmt.Sprintf("Specify output format of results (%s, %s, %s or %s, the latter is accepted by benchstat)",
+			constant.BenchmarkFormatTable, constant.BenchmarkFormatJSON, constant.BenchmarkFormatCSV, constant.BenchmarkFormatGoBench))
+	benchmarkCmd.Flags().IntVarP(&flags.BenchmarkCount, constant.KeywordFlagCount, "", 1,
+		"Specify number of benchmark runs, ignored with loop flag")
+	benchmarkCmd.Flags().StringVarP(&flags.BenchmarkSave, constant.KeywordFlagSave, "", "",
+		"Save results with metadata under given name to results directory (CRYPTO_BROKER_BENCHMARK_DIR, .benchmarks by default)")
+
+	benchmarkCompareCmd.Flags().Float64VarP(&flags.BenchmarkThreshold, constant.KeywordFlagThreshold, "", constant.DefaultBenchmarkCompareThreshold,
+		"Specify regression threshold in percent, command fails if any significant slowdown exceeds it")
+	benchmarkCompareCmd.Flags().Float64VarP(&flags.BenchmarkAlpha, constant.KeywordFlagAlpha, "", constant.DefaultBenchmarkCompareAlpha,
+		"Specify significance level, deltas with higher p-value are considered noise")
+	benchmarkCmd.AddCommand(benchmarkCompareCmd)
+	benchmarkCmd.AddCommand(benchClientCmd)
 }
 
 var benchmarkCmd = &cobra.Command{
-	Use:   "benchmark",
-	Short: "Benchmark runs server-side cryptographic benchmarks.",
-	Args:  cobra.NoArgs,
+	Use:     "benchmark",
+	Aliases: []string{"bench"},
+	Short:   "Benchmark runs server-side cryptographic benchmarks.",
+	Args:    cobra.NoArgs,
 	PreRun: func(cmd *cobra.Command, args []string) {
 		if err := flags.ValidateFlagLoop(flags.Loop); err != nil {
 			slog.Error("Invalid loop flag value", "error", err)
 			panic(err)
 		}
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		ctx := cmd.Context()
-		logger := clog.SetupGlobalLogger(ctx)
 
-		tracerProvider, err := otel.NewTracerProvider(ctx, logger)
-		if err != nil {
-			logger.Error("Failed to initialize tracer provider", "error", err)
+		if err := flags.ValidateFlagBenchmarkFormat(flags.BenchmarkFormat); err != nil {
+			slog.Error("Invalid format flag value", "error", err)
+			panic(err)
+		}
+
+		if err := flags.ValidateFlagPositiveInt(constant.KeywordFlagCount, flags.BenchmarkCount); err != nil {
+			slog.Error("Invalid count flag value", "error", err)
 			panic(err)
 		}
 
-		// Shutdown function that ensures proper cleanup
-		shutdownTracer := func() {
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
-				logger.Warn("Failed to shutdown tracer provider", "error", err)
-			}
+		if err := flags.ValidateFlagBenchmarkSave(flags.BenchmarkSave); err != nil {
+			slog.Error("Invalid save flag value", "error", err)
+			panic(err)
 		}
-		defer shutdownTracer()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		// stdout is reserved for benchmark results
+		clog.SetConsoleOutput(os.Stderr)
+		otel.SetConsoleOutput(os.Stderr)
+
+		logger, tracerProvider, shutdownTelemetry := setupTelemetry(ctx)
+		defer shutdownTelemetry()
 
 		lib, err := cryptobroker.NewLibrary(ctx)
 		if err != nil {
-			shutdownTracer()
 			logger.Error("Failed to initialize library", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 
-		benchmarkCommand, err := command.NewBenchmark(ctx, lib, logger, tracerProvider)
+		version, err := versionPayload()
+		if err != nil {
+			logger.Error("Failed to compute version payload", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+
+		benchmarkCommand, err := command.NewBenchmark(ctx, lib, logger, tracerProvider, retryPolicy, version)
 		if err != nil {
-			shutdownTracer()
 			logger.Error("Failed to initialize benchmark command", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 
-		err = benchmarkCommand.Run(ctx, flags.Loop)
+		err = benchmarkCommand.Run(ctx, os.Stdout, flags.Loop, flags.BenchmarkCount, flags.BenchmarkFormat, flags.BenchmarkSave)
 		if err != nil && !errors.Is(err, cryptobroker.ErrCircuitOpen) {
-			shutdownTracer()
 			logger.Error("Failed to run benchmark command", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+	},
+}
+
+var benchmarkCompareCmd = &cobra.Command{
+	Use:   "compare BASE NEW",
+	Short: "Compare computes per benchmark deltas between two saved benchmark results.",
+	Long: `Compare computes per benchmark deltas between two saved benchmark results.
+
+BASE and NEW are names results were saved under with "benchmark --save NAME", or paths of saved results files.
+Medians of all runs are compared and deltas are tested for statistical significance with Mann-Whitney U test,
+therefore results of at least 4 runs each ("benchmark --count 10") are recommended.
+Command fails if any significant slowdown exceeds the regression threshold.`,
+	Args: cobra.ExactArgs(2),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if err := flags.ValidateFlagBenchmarkCompare(flags.BenchmarkThreshold, flags.BenchmarkAlpha); err != nil {
+			slog.Error("Invalid benchmark compare flag value", "error", err)
 			panic(err)
 		}
 	},
+	Run: func(cmd *cobra.Command, args []string) {
+		benchmarkCompareCommand, err := command.NewBenchmarkCompare(os.Stdout)
+		if err != nil {
+			slog.Error("Failed to initialize benchmark compare command", "error", err)
+			panic(err)
+		}
+
+		err = benchmarkCompareCommand.Run(command.BenchmarkDir(), args[0], args[1], flags.BenchmarkThreshold, flags.BenchmarkAlpha)
+		switch {
+		case errors.Is(err, command.ErrBenchmarkRegression):
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%v\n", err)
+			os.Exit(constant.ExitCodeBenchmarkRegression)
+		case err != nil:
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "failed to compare benchmark results: %v\n", err)
+			os.Exit(1)
+		}
+	},
 }
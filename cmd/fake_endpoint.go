@@ -1,16 +1,14 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"log/slog"
 	"time"
 
-	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/clog"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/chaos"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
-	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
 	cryptobroker "github.com/open-crypto-broker/crypto-broker-client-go"
 	"github.com/spf13/cobra"
 )
@@ -19,54 +17,93 @@ func init() {
 	fakeEndpointCmd.Flags().IntVarP(&flags.Loop, constant.KeywordFlagLoop, "",
 		constant.NoLoopFlagValue,
 		fmt.Sprintf("Specify delay for loop in milliseconds (%d-%d)", constant.MinLoopFlagValue, constant.MaxLoopFlagValue))
+	fakeEndpointCmd.Flags().Float64VarP(&flags.DropRate, constant.KeywordFlagDropRate, "", 0,
+		"Specify percentage of requests dropped before being sent (0-100)")
+	fakeEndpointCmd.Flags().DurationVarP(&flags.Latency, constant.KeywordFlagLatency, "", 0,
+		"Specify latency added before each request is sent")
+	fakeEndpointCmd.Flags().DurationVarP(&flags.LatencyJitter, constant.KeywordFlagLatencyJitter, "", 0,
+		"Specify spread of the latency distribution")
+	fakeEndpointCmd.Flags().StringVarP(&flags.LatencyDistribution, constant.KeywordFlagLatencyDistribution, "", chaos.DistributionFixed,
+		fmt.Sprintf("Specify latency distribution (%s, %s, %s, %s)", chaos.DistributionFixed, chaos.DistributionUniform, chaos.DistributionNormal, chaos.DistributionExponential))
+	fakeEndpointCmd.Flags().Float64VarP(&flags.CancelRate, constant.KeywordFlagCancelRate, "", 0,
+		"Specify percentage of requests cancelled mid-flight (0-100)")
+	fakeEndpointCmd.Flags().DurationVarP(&flags.CancelAfter, constant.KeywordFlagCancelAfter, "", time.Millisecond,
+		"Specify time after which a request selected for cancellation is cancelled")
+	fakeEndpointCmd.Flags().Float64VarP(&flags.CorruptRate, constant.KeywordFlagCorruptRate, "", 0,
+		"Specify percentage of requests sent with corrupted payload size (0-100)")
+	fakeEndpointCmd.Flags().IntVarP(&flags.CorruptSize, constant.KeywordFlagCorruptSize, "", 64*1024,
+		"Specify size in bytes of the corrupted payload field")
+	fakeEndpointCmd.Flags().Uint64VarP(&flags.ChaosSeed, constant.KeywordFlagChaosSeed, "", 0,
+		"Specify seed of fault injection random source, 0 means random seed")
 }
 
 var fakeEndpointCmd = &cobra.Command{
 	Use:   "fake-endpoint",
 	Short: "Fake endpoint sends fake endpoint request to crypto broker.",
-	Args:  cobra.NoArgs,
+	Long: `Fake endpoint sends fake endpoint request to crypto broker.
+
+Fault injection flags add a client-side chaos layer in front of the library call: requests can be dropped,
+delayed according to a latency distribution, cancelled mid-flight or sent with a corrupted payload size.
+Faults are drawn for every attempt of the --retries policy, dropped attempts fail with UNAVAILABLE code
+without reaching the library. Combined with --loop, this exercises the retry and circuit breaker paths
+and logs every observed circuit breaker state transition.`,
+	Args: cobra.NoArgs,
 	PreRun: func(cmd *cobra.Command, args []string) {
 		if err := flags.ValidateFlagLoop(flags.Loop); err != nil {
 			slog.Error("Invalid loop flag value", "error", err)
 			panic(err)
 		}
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		ctx := cmd.Context()
-		logger := clog.SetupGlobalLogger(ctx)
 
-		tracerProvider, err := otel.NewTracerProvider(ctx, logger)
-		if err != nil {
-			logger.Error("Failed to initialize tracer provider", "error", err)
-			panic(err)
+		for name, rate := range map[string]float64{
+			constant.KeywordFlagDropRate:    flags.DropRate,
+			constant.KeywordFlagCancelRate:  flags.CancelRate,
+			constant.KeywordFlagCorruptRate: flags.CorruptRate,
+		} {
+			if err := flags.ValidateFlagRate(name, rate); err != nil {
+				slog.Error("Invalid rate flag value", "error", err)
+				panic(err)
+			}
 		}
 
-		shutdownTracer := func() {
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
-				logger.Warn("Failed to shutdown tracer provider", "error", err)
-			}
+		if err := flags.ValidateFlagLatencyDistribution(flags.LatencyDistribution); err != nil {
+			slog.Error("Invalid latency distribution flag value", "error", err)
+			panic(err)
 		}
-		defer shutdownTracer()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		logger, tracerProvider, shutdownTelemetry := setupTelemetry(ctx)
+		defer shutdownTelemetry()
 
 		lib, err := cryptobroker.NewLibrary(ctx)
 		if err != nil {
-			shutdownTracer()
 			logger.Error("Failed to initialize library", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 
-		fakeEndpointCommand, err := command.NewFakeEndpoint(ctx, lib, logger, tracerProvider)
+		fakeEndpointCommand, err := command.NewFakeEndpoint(ctx, lib, logger, tracerProvider, retryPolicy)
 		if err != nil {
-			shutdownTracer()
 			logger.Error("Failed to initialize fake endpoint command", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 
-		if err := fakeEndpointCommand.Run(ctx, flags.Loop); err != nil {
-			shutdownTracer()
+		chaosConfig := chaos.Config{
+			DropRate:            flags.DropRate,
+			Latency:             flags.Latency,
+			LatencyJitter:       flags.LatencyJitter,
+			LatencyDistribution: flags.LatencyDistribution,
+			CancelRate:          flags.CancelRate,
+			CancelAfter:         flags.CancelAfter,
+			CorruptRate:         flags.CorruptRate,
+			CorruptSize:         flags.CorruptSize,
+			Seed:                flags.ChaosSeed,
+		}
+
+		if err := fakeEndpointCommand.Run(ctx, flags.Loop, chaosConfig); err != nil {
 			logger.Error("Failed to run fake endpoint command", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 	},
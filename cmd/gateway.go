@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	gatewayCmd.Flags().StringVarP(&flags.GatewayListen, constant.KeywordFlagListen, "", constant.DefaultGatewayListen,
+		"Specify address the HTTP gateway listens on")
+	gatewayCmd.Flags().StringVarP(&flags.GatewayMaxRequestSize, constant.KeywordFlagMaxRequestSize, "", constant.DefaultGatewayMaxRequestSize,
+		"Specify maximum size of request body with optional B, KiB, MiB or GiB unit")
+	gatewayCmd.Flags().IntVarP(&flags.GatewayMaxBenchmarkRequests, constant.KeywordFlagMaxBenchmarkRequests, "", constant.DefaultGatewayMaxBenchmarkRequests,
+		"Specify maximum number of requests of a single benchmark")
+	addPoolFlags(gatewayCmd)
+
+	gatewayCmd.AddCommand(gatewayOpenAPICmd)
+}
+
+var gatewayCmd = &cobra.Command{
+	Use:   "gateway",
+	Short: "Gateway exposes broker operations as JSON endpoints over HTTP.",
+	Long: `Gateway exposes broker operations as JSON endpoints over HTTP, for services that cannot use client library.
+
+  POST /v1/hash               hashes input
+  POST /v1/sign-certificate   signs CSR with CA certificate and private key
+  GET  /v1/health             checks broker health
+  POST /v1/benchmark          runs client-side benchmark
+  GET  /openapi.json          returns OpenAPI spec of the endpoints
+
+When CRYPTO_BROKER_GATEWAY_TOKEN is set, endpoints other than health and OpenAPI spec require it as bearer token,
+otherwise warning is logged. Gateway listens on loopback interface only, unless --listen specifies another address.
+Every request is traced in a server span that joins the trace of incoming traceparent header, and the trace
+context is returned in traceparent response header. Request bodies larger than --max-request-size are refused.
+Signing requests are recorded in audit log configured by CRYPTO_BROKER_AUDIT_LOG.
+
+Requests are distributed across --pool-size library connections, every request is sent through the connection
+with fewest requests in flight. Connections failing --pool-failure-threshold consecutive health checks are replaced.`,
+	Args: cobra.NoArgs,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if err := flags.ValidateFlagPool(flags.PoolSize, flags.PoolHealthInterval, flags.PoolFailureThreshold); err != nil {
+			slog.Error("Invalid library pool flag value", "error", err)
+			panic(err)
+		}
+
+		if err := flags.ValidateFlagPositiveInt(constant.KeywordFlagMaxBenchmarkRequests, flags.GatewayMaxBenchmarkRequests); err != nil {
+			slog.Error("Invalid max benchmark requests flag value", "error", err)
+			panic(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		maxRequestSize, err := flags.ParseFlagSizes([]string{flags.GatewayMaxRequestSize})
+		if err != nil {
+			err = fmt.Errorf("'%s' flag value is invalid: %w", constant.KeywordFlagMaxRequestSize, err)
+			slog.Error("Invalid max request size flag value", "error", err)
+			panic(err)
+		}
+
+		logger, tracerProvider, shutdownTelemetry := setupTelemetry(ctx)
+		defer shutdownTelemetry()
+
+		version, err := versionPayload()
+		if err != nil {
+			logger.Error("Failed to compute version payload", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+
+		auditLog, err := openAuditLog()
+		if err != nil {
+			logger.Error("Failed to open audit log", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+		defer func() { _ = auditLog.Close() }()
+
+		libraries, err := pool.New(ctx, logger, poolConfig())
+		if err != nil {
+			logger.Error("Failed to initialize library pool", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+
+		gatewayCommand, err := command.NewGateway(ctx, libraries, logger, tracerProvider, retryPolicy, auditLog,
+			os.Getenv(env.GATEWAY_TOKEN), version.CLI.Version)
+		if err != nil {
+			_ = libraries.Shutdown(context.Background())
+			logger.Error("Failed to initialize gateway command", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+
+		if err := gatewayCommand.Run(ctx, flags.GatewayListen, maxRequestSize[0], flags.GatewayMaxBenchmarkRequests); err != nil {
+			logger.Error("Failed to run gateway command", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+	},
+}
+
+var gatewayOpenAPICmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "OpenAPI prints OpenAPI spec of gateway endpoints.",
+	Long: `OpenAPI prints OpenAPI spec of gateway endpoints in JSON, the same spec is served by gateway on /openapi.json.
+It does not connect to the broker.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := versionPayload()
+		if err != nil {
+			slog.Error("Failed to compute version payload", "error", err)
+			panic(err)
+		}
+
+		spec, err := command.GatewayOpenAPI(version.CLI.Version)
+		if err != nil {
+			slog.Error("Failed to generate OpenAPI spec", "error", err)
+			panic(err)
+		}
+
+		fmt.Println(string(spec))
+	},
+}
@@ -1,17 +1,15 @@
 package cmd
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"log/slog"
-	"time"
+	"os"
 
-	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/clog"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
-	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
 	cryptobroker "github.com/open-crypto-broker/crypto-broker-client-go"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +19,8 @@ func init() {
 	hashDataCmd.Flags().StringVarP(&flags.OutputFormat, constant.KeywordFlagOutputFormat, "", "hex", "Specify hash output format: hex or raw")
 	hashDataCmd.Flags().IntVarP(&flags.Loop, constant.KeywordFlagLoop, "", constant.NoLoopFlagValue,
 		fmt.Sprintf("Specify delay for loop in milliseconds (%d-%d)", constant.MinLoopFlagValue, constant.MaxLoopFlagValue))
+	hashDataCmd.Flags().BoolVarP(&flags.FailOnCircuitOpen, constant.KeywordFlagFailOnCircuitOpen, "", false,
+		"Stop looping at the first request short-circuited by the circuit breaker")
 }
 
 var hashDataCmd = &cobra.Command{
@@ -35,42 +35,33 @@ var hashDataCmd = &cobra.Command{
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := cmd.Context()
-		logger := clog.SetupGlobalLogger(ctx)
+		logger, tracerProvider, shutdownTelemetry := setupTelemetry(ctx)
+		defer shutdownTelemetry()
 
-		tracerProvider, err := otel.NewTracerProvider(ctx, logger)
+		libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 		if err != nil {
-			logger.Error("Failed to initialize tracer provider", "error", err)
-			panic(err)
-		}
-
-		// Shutdown function that ensures proper cleanup
-		shutdownTracer := func() {
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
-				logger.Warn("Failed to shutdown tracer provider", "error", err)
-			}
-		}
-		defer shutdownTracer()
-
-		lib, err := cryptobroker.NewLibrary(ctx)
-		if err != nil {
-			shutdownTracer()
 			logger.Error("Failed to initialize library", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 
-		hashCommand, err := command.NewHashData(ctx, lib, logger, tracerProvider)
+		hashCommand, err := command.NewHashData(ctx, libraries, logger, tracerProvider, retryPolicy)
 		if err != nil {
-			shutdownTracer()
 			logger.Error("Failed to initialize hash command", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 
-		err = hashCommand.Run(ctx, []byte(args[0]), flags.OutputFormat, flags.Profile, flags.Loop)
-		if err != nil && !errors.Is(err, cryptobroker.ErrCircuitOpen) {
-			shutdownTracer()
+		err = hashCommand.Run(ctx, []byte(args[0]), flags.OutputFormat, flags.Profile, flags.Loop, flags.FailOnCircuitOpen)
+		if errors.Is(err, cryptobroker.ErrCircuitOpen) {
+			logger.Warn("Final hash request was short-circuited by circuit breaker", "exit_code", constant.ExitCodeCircuitOpen)
+			shutdownTelemetry()
+			os.Exit(constant.ExitCodeCircuitOpen)
+		}
+
+		if err != nil {
 			logger.Error("Failed to run hash command", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 	},
@@ -5,72 +5,153 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"time"
+	"os"
 
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/clog"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
-	cryptobroker "github.com/open-crypto-broker/crypto-broker-client-go"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	healthCmd.Flags().IntVarP(&flags.Loop, constant.KeywordFlagLoop, "", constant.NoLoopFlagValue,
 		fmt.Sprintf("Specify delay for loop in milliseconds (%d-%d)", constant.MinLoopFlagValue, constant.MaxLoopFlagValue))
+	healthCmd.Flags().BoolVarP(&flags.Wait, constant.KeywordFlagWait, "", false,
+		"Wait until the broker reports SERVING status or the timeout elapses")
+	healthCmd.Flags().DurationVarP(&flags.Timeout, constant.KeywordFlagTimeout, "", constant.DefaultHealthWaitTimeout,
+		fmt.Sprintf("Specify how long to wait for SERVING status, used with --wait and --%s %s", constant.KeywordFlagFormat, constant.HealthFormatNagios))
+	healthCmd.Flags().DurationVarP(&flags.Interval, constant.KeywordFlagInterval, "", constant.DefaultHealthWaitInterval,
+		"Specify delay between health checks, used with --wait")
+	healthCmd.Flags().StringVarP(&flags.HealthFormat, constant.KeywordFlagFormat, "", constant.HealthFormatText,
+		fmt.Sprintf("Specify output format: %s or %s (monitoring plugin line)", constant.HealthFormatText, constant.HealthFormatNagios))
+	healthCmd.Flags().DurationVarP(&flags.HealthWarning, constant.KeywordFlagWarning, "", 0,
+		fmt.Sprintf("Specify response time above which the check is WARNING, used with --%s %s", constant.KeywordFlagFormat, constant.HealthFormatNagios))
+	healthCmd.Flags().DurationVarP(&flags.HealthCritical, constant.KeywordFlagCritical, "", 0,
+		fmt.Sprintf("Specify response time above which the check is CRITICAL, used with --%s %s", constant.KeywordFlagFormat, constant.HealthFormatNagios))
+	healthCmd.MarkFlagsMutuallyExclusive(constant.KeywordFlagWait, constant.KeywordFlagLoop)
 }
 
 var healthCmd = &cobra.Command{
 	Use:   "health",
 	Short: "Health checks the broker server status.",
-	Args:  cobra.NoArgs,
+	Long: fmt.Sprintf(`Health checks the broker server status.
+
+Exit code reflects the status of the final check: %d for SERVING, %d for NOT_SERVING
+and %d for UNKNOWN or an unreachable broker. Health check short-circuited by an open circuit breaker
+is reported as UNKNOWN, as library does not tell it apart from other failures. With --wait the command
+polls until the broker is SERVING, which makes it usable in container entrypoints and init containers.
+
+With --format nagios the command performs a single check and prints a monitoring plugin line
+"CRYPTO_BROKER STATUS - message | perfdata" with response time as perfdata, logs are written to stderr.
+Exit code is %d (OK) for SERVING, %d (WARNING) or %d (CRITICAL) when response time exceeds --warning
+or --critical, %d (CRITICAL) for NOT_SERVING and %d (UNKNOWN) for UNKNOWN or an unreachable broker.`,
+		0, constant.ExitCodeHealthNotServing, constant.ExitCodeHealthUnknown,
+		constant.ExitCodeNagiosOK, constant.ExitCodeNagiosWarning, constant.ExitCodeNagiosCritical,
+		constant.ExitCodeNagiosCritical, constant.ExitCodeNagiosUnknown),
+	Args: cobra.NoArgs,
 	PreRun: func(cmd *cobra.Command, args []string) {
 		if err := flags.ValidateFlagLoop(flags.Loop); err != nil {
 			slog.Error("Invalid loop flag value", "error", err)
 			panic(err)
 		}
+
+		if err := flags.ValidateFlagPositiveDuration(constant.KeywordFlagTimeout, flags.Timeout); err != nil {
+			slog.Error("Invalid timeout flag value", "error", err)
+			panic(err)
+		}
+
+		if err := flags.ValidateFlagPositiveDuration(constant.KeywordFlagInterval, flags.Interval); err != nil {
+			slog.Error("Invalid interval flag value", "error", err)
+			panic(err)
+		}
+
+		if err := flags.ValidateFlagHealthFormat(flags.HealthFormat); err != nil {
+			slog.Error("Invalid format flag value", "error", err)
+			panic(err)
+		}
+
+		if err := flags.ValidateFlagLatencyThresholds(flags.HealthWarning, flags.HealthCritical); err != nil {
+			slog.Error("Invalid latency threshold flag value", "error", err)
+			panic(err)
+		}
+
+		if flags.HealthFormat == constant.HealthFormatNagios && (flags.Wait || flags.Loop != constant.NoLoopFlagValue) {
+			err := fmt.Errorf("'%s' flag value %s cannot be used with '%s' or '%s' flags",
+				constant.KeywordFlagFormat, constant.HealthFormatNagios, constant.KeywordFlagWait, constant.KeywordFlagLoop)
+			slog.Error("Invalid format flag value", "error", err)
+			panic(err)
+		}
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := cmd.Context()
-		logger := clog.SetupGlobalLogger(ctx)
-
-		// Initialize tracing
-		tracerProvider, err := otel.NewTracerProvider(ctx, logger)
-		if err != nil {
-			logger.Error("Failed to initialize tracer provider", "error", err)
-			panic(err)
+		nagios := flags.HealthFormat == constant.HealthFormatNagios
+		if nagios {
+			// stdout is reserved for the monitoring plugin line
+			clog.SetConsoleOutput(os.Stderr)
+			otel.SetConsoleOutput(os.Stderr)
 		}
 
-		// Shutdown function that ensures proper cleanup
-		shutdownTracer := func() {
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		logger, tracerProvider, shutdownTelemetry := setupTelemetry(ctx)
+		defer shutdownTelemetry()
+
+		// The wait deadline also bounds establishing the library connection,
+		// as the broker socket may not exist yet.
+		if flags.Wait || nagios {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, flags.Timeout)
 			defer cancel()
-			if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
-				logger.Warn("Failed to shutdown tracer provider", "error", err)
-			}
 		}
-		defer shutdownTracer()
 
-		lib, err := cryptobroker.NewLibrary(ctx)
+		libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
+		if err != nil && flags.Wait {
+			logger.Error("Broker did not become reachable", "timeout", flags.Timeout.String(), "error", err)
+			shutdownTelemetry()
+			os.Exit(constant.ExitCodeHealthUnknown)
+		}
+
+		if err != nil && nagios {
+			logger.Error("Broker is not reachable", "timeout", flags.Timeout.String(), "error", err)
+			fmt.Println(command.NagiosUnknown(fmt.Errorf("broker is not reachable: %w", err)))
+			shutdownTelemetry()
+			os.Exit(constant.ExitCodeNagiosUnknown)
+		}
+
 		if err != nil {
-			shutdownTracer()
-			logger.Error("Failed to initialize library", "error", err)
-			panic(err)
+			logger.Error("Broker is not reachable", "error", err, "exit_code", constant.ExitCodeHealthUnknown)
+			shutdownTelemetry()
+			os.Exit(constant.ExitCodeHealthUnknown)
 		}
 
-		healthCommand, err := command.NewHealth(ctx, lib, logger, tracerProvider)
+		healthCommand, err := command.NewHealth(ctx, libraries, logger, tracerProvider, retryPolicy)
 		if err != nil {
-			shutdownTracer()
 			logger.Error("Failed to initialize health command", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 
-		err = healthCommand.Run(ctx, flags.Loop)
-		if err != nil && !errors.Is(err, cryptobroker.ErrCircuitOpen) {
-			shutdownTracer()
-			logger.Error("Failed to run health command", "error", err)
-			panic(err)
+		if nagios {
+			exitCode, err := healthCommand.RunNagios(ctx, os.Stdout, flags.HealthWarning, flags.HealthCritical)
+			if err != nil {
+				logger.Error("Failed to report health check result", "error", err)
+			}
+
+			shutdownTelemetry()
+			os.Exit(exitCode)
+		}
+
+		err = healthCommand.Run(ctx, flags.Loop, flags.Wait, flags.Interval)
+		switch {
+		case errors.Is(err, command.ErrHealthNotServing):
+			logger.Warn("Broker is not serving", "error", err, "exit_code", constant.ExitCodeHealthNotServing)
+			shutdownTelemetry()
+			os.Exit(constant.ExitCodeHealthNotServing)
+		case errors.Is(err, command.ErrHealthStatusUnknown):
+			logger.Warn("Broker health status is unknown", "error", err, "exit_code", constant.ExitCodeHealthUnknown)
+			shutdownTelemetry()
+			os.Exit(constant.ExitCodeHealthUnknown)
 		}
 	},
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	healthServeCmd.Flags().StringVarP(&flags.HealthServeListen, constant.KeywordFlagListen, "", constant.DefaultHealthServeListen,
+		"Specify address the HTTP probe server listens on")
+	healthServeCmd.Flags().DurationVarP(&flags.HealthServeInterval, constant.KeywordFlagInterval, "", constant.DefaultHealthServeInterval,
+		"Specify delay between periodic health checks")
+	healthServeCmd.Flags().DurationVarP(&flags.HealthServeCacheTTL, constant.KeywordFlagCacheTTL, "", constant.DefaultHealthServeCacheTTL,
+		"Specify how long a health check result is served before it is considered stale")
+	healthServeCmd.Flags().IntVarP(&flags.HealthServeFailureThreshold, constant.KeywordFlagFailureThreshold, "", constant.DefaultHealthServeFailureThreshold,
+		"Specify number of consecutive failed checks after which readiness fails")
+
+	healthCmd.AddCommand(healthServeCmd)
+}
+
+var healthServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve exposes broker health as HTTP probe endpoints.",
+	Long: `Serve exposes broker health as HTTP probe endpoints /livez, /readyz and /healthz.
+
+Broker health is checked periodically and cached results are served. Readiness fails after
+--failure-threshold consecutive checks without SERVING status, liveness fails when no check
+completed within --cache-ttl, which must not be shorter than --interval. /healthz always returns
+JSON detail, the other endpoints return it when the verbose query parameter is present.`,
+	Args: cobra.NoArgs,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if err := flags.ValidateFlagHealthServeIntervals(flags.HealthServeInterval, flags.HealthServeCacheTTL); err != nil {
+			slog.Error("Invalid interval or cache TTL flag value", "error", err)
+			panic(err)
+		}
+
+		if err := flags.ValidateFlagPositiveInt(constant.KeywordFlagFailureThreshold, flags.HealthServeFailureThreshold); err != nil {
+			slog.Error("Invalid failure threshold flag value", "error", err)
+			panic(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		logger, tracerProvider, shutdownTelemetry := setupTelemetry(ctx)
+		defer shutdownTelemetry()
+
+		libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
+		if err != nil {
+			logger.Error("Failed to initialize library", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+
+		healthServeCommand, err := command.NewHealthServe(ctx, libraries, logger, tracerProvider, retryPolicy)
+		if err != nil {
+			logger.Error("Failed to initialize health serve command", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+
+		err = healthServeCommand.Run(ctx, flags.HealthServeListen, flags.HealthServeInterval, flags.HealthServeCacheTTL, flags.HealthServeFailureThreshold)
+		if err != nil {
+			logger.Error("Failed to run health serve command", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+	},
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/spf13/cobra"
+)
+
+// addPoolFlags adds library pool flags to command issuing concurrent requests.
+func addPoolFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&flags.PoolSize, constant.KeywordFlagPoolSize, "", constant.DefaultPoolSize,
+		fmt.Sprintf("Specify number of library connections requests are distributed across (1-%d)", pool.MaxSize))
+	cmd.Flags().DurationVarP(&flags.PoolHealthInterval, constant.KeywordFlagPoolHealthInterval, "", constant.DefaultPoolHealthInterval,
+		"Specify delay between health checks of library connections (0 disables health checks)")
+	cmd.Flags().IntVarP(&flags.PoolFailureThreshold, constant.KeywordFlagPoolFailureThreshold, "", constant.DefaultPoolFailureThreshold,
+		"Specify number of consecutive failed health checks after which library connection is replaced")
+}
+
+// poolConfig returns library pool configuration of pool flags. Single health check is bounded
+// by health interval, but not longer than 5 seconds.
+func poolConfig() pool.Config {
+	return pool.Config{
+		Size:             flags.PoolSize,
+		HealthInterval:   flags.PoolHealthInterval,
+		HealthTimeout:    min(flags.PoolHealthInterval, 5*time.Second),
+		FailureThreshold: flags.PoolFailureThreshold,
+	}
+}
@@ -1,21 +1,135 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/clog"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/correlation"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
 	"github.com/spf13/cobra"
 )
 
+// retryPolicy is built from persistent retry flags before any command runs.
+var retryPolicy retry.Policy
+
 func init() {
+	rootCmd.PersistentFlags().IntVarP(&flags.Retries, constant.KeywordFlagRetries, "", 0,
+		fmt.Sprintf("Specify number of retries of failed broker requests (0-%d)", retry.MaxRetries))
+	rootCmd.PersistentFlags().DurationVarP(&flags.RetryBackoff, constant.KeywordFlagRetryBackoff, "", 100*time.Millisecond,
+		"Specify initial retry backoff, doubled with every retry and jittered")
+	rootCmd.PersistentFlags().DurationVarP(&flags.RetryMaxDelay, constant.KeywordFlagRetryMaxDelay, "", 5*time.Second,
+		"Specify maximum delay between retries")
+	rootCmd.PersistentFlags().StringSliceVarP(&flags.RetryOn, constant.KeywordFlagRetryOn, "",
+		[]string{"UNAVAILABLE", "RESOURCE_EXHAUSTED", "ABORTED"},
+		"Specify gRPC status codes (names or numbers) that are retried. The library already retries "+
+			"UNAVAILABLE, RESOURCE_EXHAUSTED and ABORTED up to 5 attempts, so every CLI attempt for those codes can make up to 5 calls")
+	rootCmd.PersistentFlags().StringVarP(&flags.MetricsListen, constant.KeywordFlagMetricsListen, "", "",
+		"Specify address to expose Prometheus metrics on /metrics, e.g. :9090 (disabled when empty)")
+	rootCmd.PersistentFlags().StringVarP(&flags.TraceParent, constant.KeywordFlagTraceParent, "", "",
+		"Specify W3C traceparent of the caller to join its trace, overrides TRACEPARENT environment variable")
+	rootCmd.PersistentFlags().StringVarP(&flags.CorrelationId, constant.KeywordFlagCorrelationId, "", "",
+		fmt.Sprintf("Specify correlation id sent with every request, overrides %s environment variable (generated when empty)", env.CORRELATION_ID))
+
 	rootCmd.AddCommand(hashDataCmd)
 	rootCmd.AddCommand(signCertificateCmd)
 	rootCmd.AddCommand(healthCmd)
 	rootCmd.AddCommand(benchmarkCmd)
 	rootCmd.AddCommand(fakeEndpointCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(traceCmd)
+	rootCmd.AddCommand(assertCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(shellCmd)
+	rootCmd.AddCommand(gatewayCmd)
 }
 
 var rootCmd = &cobra.Command{
 	Use:   "go-client-cli",
 	Short: "CLI for working with Crypto Broker",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		policy, err := retry.NewPolicy(flags.Retries, flags.RetryBackoff, flags.RetryMaxDelay, flags.RetryOn)
+		if err != nil {
+			slog.Error("Invalid retry flag value", "error", err)
+			panic(err)
+		}
+
+		retryPolicy = policy
+
+		ctx, err := otel.ContextWithRemoteParent(cmd.Context(), slog.Default(), flags.TraceParent)
+		if err != nil {
+			slog.Error("Invalid traceparent flag value", "error", err)
+			panic(err)
+		}
+
+		correlationId := flags.CorrelationId
+		if correlationId == "" {
+			correlationId = os.Getenv(env.CORRELATION_ID)
+		}
+
+		if correlationId == "" {
+			correlationId = correlation.NewID()
+		}
+
+		cmd.SetContext(correlation.ContextWithID(ctx, correlationId))
+	},
+}
+
+// setupTelemetry initializes global logger and OpenTelemetry tracer and meter providers of a command.
+// The returned function shuts them down, flushing pending telemetry. It runs only once, so it can be both
+// deferred and called before os.Exit or panic.
+func setupTelemetry(ctx context.Context) (*slog.Logger, *otel.TracerProvider, func()) {
+	logger, loggerProvider := clog.SetupGlobalLogger(ctx)
+
+	tracerProvider, err := otel.NewTracerProvider(ctx, logger)
+	if err != nil {
+		logger.Error("Failed to initialize tracer provider", "error", err)
+		shutdownLoggerProvider(loggerProvider)
+		panic(err)
+	}
+
+	meterProvider, err := otel.NewMeterProvider(ctx, logger, flags.MetricsListen)
+	if err != nil {
+		_ = tracerProvider.Shutdown(context.Background())
+		logger.Error("Failed to initialize meter provider", "error", err)
+		shutdownLoggerProvider(loggerProvider)
+		panic(err)
+	}
+
+	shutdownTelemetry := sync.OnceFunc(func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Failed to shutdown tracer provider", "error", err)
+		}
+
+		if err := meterProvider.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Failed to shutdown meter provider", "error", err)
+		}
+
+		// Logger provider is shut down last, so that warnings above are still exported
+		shutdownLoggerProvider(loggerProvider)
+	})
+
+	return logger, tracerProvider, shutdownTelemetry
+}
+
+// shutdownLoggerProvider flushes pending log records of OTLP and file log exporters.
+// Failure is reported on stderr, since the logger may not be able to export it anymore.
+func shutdownLoggerProvider(loggerProvider *clog.LoggerProvider) {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := loggerProvider.Shutdown(shutdownCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to shutdown logger provider, pending log records may be lost: %v\n", err)
+	}
 }
 
 func Execute() {
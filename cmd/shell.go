@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/clog"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	shellCmd.Flags().StringSliceVarP(&flags.ShellProfiles, constant.KeywordFlagProfiles, "", []string{"Default"},
+		"Specify profile names offered by tab completion of --profile, profiles used in the session are added")
+	shellCmd.Flags().StringVarP(&flags.ShellHistoryFile, constant.KeywordFlagHistoryFile, "", "",
+		"Specify file lines entered in the shell, except hash lines, are persisted in (defaults to shell_history in user configuration directory)")
+}
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Shell starts interactive session sending commands through one broker connection.",
+	Long: `Shell starts interactive session sending commands through one broker connection.
+
+The library connection is established once, so that commands entered in the session are not slowed down
+by connecting to the broker. Available commands are:
+
+  hash TEXT [--profile PROFILE]
+  sign --csr FILE --caCert FILE --caKey SOURCE [--profile PROFILE] [--subject SUBJECT] [--allow-insecure-key]
+  health
+  history
+  help [COMMAND]
+  exit
+
+Words are split like in POSIX shell, so text containing spaces must be quoted. Result of every command
+is displayed together with its duration. On terminal, Up and Down keys recall lines of history, persisted
+across sessions except hash lines, whose input may be secret, and Tab completes command names, flags,
+profile names after --profile and file paths. Commands can be piped to the shell as well, e.g. from
+a script file, one per line.
+
+Console logs are written at WARN level unless CRYPTO_BROKER_LOG_LEVEL is set.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		// console logs and telemetry are written through the terminal, so that they do not corrupt the prompt
+		output := command.NewShellOutput(os.Stdout)
+		clog.SetDefaultLevel(slog.LevelWarn)
+		clog.SetConsoleOutput(output)
+		otel.SetConsoleOutput(output)
+
+		logger, tracerProvider, shutdownTelemetry := setupTelemetry(ctx)
+		defer shutdownTelemetry()
+
+		auditLog, err := openAuditLog()
+		if err != nil {
+			logger.Error("Failed to open audit log", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+		defer func() { _ = auditLog.Close() }()
+
+		libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
+		if err != nil {
+			logger.Error("Failed to initialize library", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+
+		historyPath := flags.ShellHistoryFile
+		if historyPath == "" {
+			historyPath = command.DefaultShellHistoryPath()
+		}
+
+		shellCommand, err := command.NewShell(ctx, libraries, logger, tracerProvider, retryPolicy, auditLog, output, historyPath, flags.ShellProfiles)
+		if err != nil {
+			_ = libraries.Shutdown(context.Background())
+			logger.Error("Failed to initialize shell command", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+
+		if err := shellCommand.Run(ctx, os.Stdin); err != nil {
+			logger.Error("Failed to run shell command", "error", err)
+			shutdownTelemetry()
+			panic(err)
+		}
+	},
+}
@@ -1,17 +1,17 @@
 package cmd
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"log/slog"
-	"time"
+	"os"
 
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/clog"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
 	cryptobroker "github.com/open-crypto-broker/crypto-broker-client-go"
 	"github.com/spf13/cobra"
 )
@@ -20,12 +20,17 @@ func init() {
 	signCertificateCmd.Flags().StringVarP(&flags.Profile, constant.KeywordFlagProfile, "", "Default", "Specify profile to be used")
 	signCertificateCmd.Flags().IntVarP(&flags.Loop, constant.KeywordFlagLoop, "", constant.NoLoopFlagValue,
 		fmt.Sprintf("Specify delay for loop in milliseconds (%d-%d)", constant.MinLoopFlagValue, constant.MaxLoopFlagValue))
+	signCertificateCmd.Flags().BoolVarP(&flags.FailOnCircuitOpen, constant.KeywordFlagFailOnCircuitOpen, "", false,
+		"Stop looping at the first request short-circuited by the circuit breaker")
 	signCertificateCmd.Flags().StringVarP(&flags.Encoding, constant.KeywordFlagEncoding, "", constant.EncodingPEM,
 		fmt.Sprintf("Specify encoding to be used (%s, %s)", constant.EncodingPEM, constant.EncodingDER))
 	signCertificateCmd.Flags().StringVarP(&flags.Subject, constant.KeywordFlagSubject, "", "", "Specify custom subject to be used for certificate generation")
 	signCertificateCmd.Flags().StringVarP(&flags.FilePathCSR, constant.KeywordFlagFilePathCSR, "", "", "Specify relative path to CSR file")
 	signCertificateCmd.Flags().StringVarP(&flags.FilePathCACert, constant.KeywordFlagFilePathCACert, "", "", "Specify relative path to CA certificate file")
-	signCertificateCmd.Flags().StringVarP(&flags.FilePathSigningKey, constant.KeywordFlagFilePathSigningKey, "", "", "Specify relative path to signing key file")
+	signCertificateCmd.Flags().StringVarP(&flags.FilePathSigningKey, constant.KeywordFlagFilePathSigningKey, "", "",
+		`Specify relative path to signing key file, "-" to read it from stdin, "fd:N" from inherited file descriptor or "env:NAME" from environment variable`)
+	signCertificateCmd.Flags().BoolVarP(&flags.AllowInsecureKey, constant.KeywordFlagAllowInsecureKey, "", false,
+		"Allow signing key file accessible by group or others, only warning is logged")
 
 	var err error
 	err = errors.Join(err, signCertificateCmd.MarkFlagRequired(constant.KeywordFlagFilePathCSR))
@@ -54,42 +59,45 @@ var signCertificateCmd = &cobra.Command{
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := cmd.Context()
-		logger := clog.SetupGlobalLogger(ctx)
+		// stdout is reserved for signed certificates
+		clog.SetConsoleOutput(os.Stderr)
+		otel.SetConsoleOutput(os.Stderr)
 
-		tracerProvider, err := otel.NewTracerProvider(ctx, logger)
+		logger, tracerProvider, shutdownTelemetry := setupTelemetry(ctx)
+		defer shutdownTelemetry()
+
+		auditLog, err := openAuditLog()
 		if err != nil {
-			logger.Error("Failed to initialize tracer provider", "error", err)
+			logger.Error("Failed to open audit log", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
+		defer func() { _ = auditLog.Close() }()
 
-		// Shutdown function that ensures proper cleanup
-		shutdownTracer := func() {
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
-				logger.Warn("Failed to shutdown tracer provider", "error", err)
-			}
-		}
-		defer shutdownTracer()
-
-		lib, err := cryptobroker.NewLibrary(ctx)
+		libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 		if err != nil {
-			shutdownTracer()
 			logger.Error("Failed to initialize library", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 
-		signCertificateCommand, err := command.NewSignCertificate(ctx, lib, logger, tracerProvider)
+		signCertificateCommand, err := command.NewSignCertificate(ctx, libraries, logger, tracerProvider, retryPolicy, auditLog)
 		if err != nil {
-			shutdownTracer()
 			logger.Error("Failed to initialize sign certificate command", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 
-		err = signCertificateCommand.Run(ctx, flags.FilePathCSR, flags.FilePathCACert, flags.FilePathSigningKey, flags.Profile, flags.Encoding, flags.Subject, flags.Loop)
-		if err != nil && !errors.Is(err, cryptobroker.ErrCircuitOpen) {
-			shutdownTracer()
+		err = signCertificateCommand.Run(ctx, flags.FilePathCSR, flags.FilePathCACert, flags.FilePathSigningKey, flags.Profile, flags.Encoding, flags.Subject, flags.Loop, flags.FailOnCircuitOpen, flags.AllowInsecureKey)
+		if errors.Is(err, cryptobroker.ErrCircuitOpen) {
+			logger.Warn("Final sign certificate request was short-circuited by circuit breaker", "exit_code", constant.ExitCodeCircuitOpen)
+			shutdownTelemetry()
+			os.Exit(constant.ExitCodeCircuitOpen)
+		}
+
+		if err != nil {
 			logger.Error("Failed to run sign certificate command", "error", err)
+			shutdownTelemetry()
 			panic(err)
 		}
 	},
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/command"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	traceViewCmd.Flags().IntVarP(&flags.TraceViewWidth, constant.KeywordFlagWidth, "", constant.DefaultTraceViewWidth,
+		"Specify width of the timeline in characters")
+	traceViewCmd.Flags().BoolVarP(&flags.TraceViewNoColor, constant.KeywordFlagNoColor, "", false,
+		"Disable colors, which are used only when writing to a terminal and NO_COLOR is not set")
+	traceCmd.AddCommand(traceViewCmd)
+}
+
+var traceCmd = &cobra.Command{
+	Use:   "trace",
+	Short: "Trace inspects spans exported by the CLI.",
+	Args:  cobra.NoArgs,
+}
+
+var traceViewCmd = &cobra.Command{
+	Use:   "view FILE",
+	Short: "View renders exported spans as a waterfall per trace.",
+	Long: `View renders exported spans as a waterfall per trace.
+
+FILE contains spans written by the console exporter (OTEL_TRACES_EXPORTER=console)
+or OTLP-JSON written by the file exporter (OTEL_TRACES_EXPORTER=file) or the collector file exporter.
+Use "-" to read spans from stdin. Every span is displayed with its duration, position within the trace
+and attributes, spans with error status are marked with ERROR.`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if err := flags.ValidateFlagPositiveInt(constant.KeywordFlagWidth, flags.TraceViewWidth); err != nil {
+			slog.Error("Invalid width flag value", "error", err)
+			panic(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		traceViewCommand, err := command.NewTraceView(os.Stdout)
+		if err != nil {
+			slog.Error("Failed to initialize trace view command", "error", err)
+			panic(err)
+		}
+
+		color := !flags.TraceViewNoColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+		if err := traceViewCommand.Run(args[0], flags.TraceViewWidth, color); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "failed to view traces: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// isTerminal reports whether file is a character device, e.g. an interactive terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -19,14 +19,7 @@ var versionCmd = &cobra.Command{
 	Short: "Displays the version of the CLI and its Go client library.",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, _ []string) {
-		versionCmd, err := command.NewVersion()
-		if err != nil {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "failed to initialize version command: %v\n", err)
-
-			return
-		}
-
-		out, err := versionCmd.Run(gitTag, gitSHA)
+		out, err := versionPayload()
 		if err != nil {
 			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "failed to compute version payload: %v\n", err)
 
@@ -43,3 +36,13 @@ var versionCmd = &cobra.Command{
 		fmt.Println(string(b))
 	},
 }
+
+// versionPayload builds the version payload of the CLI and its Go client library.
+func versionPayload() (command.VersionPayload, error) {
+	versionCommand, err := command.NewVersion()
+	if err != nil {
+		return command.VersionPayload{}, fmt.Errorf("failed to initialize version command: %w", err)
+	}
+
+	return versionCommand.Run(gitTag, gitSHA)
+}
@@ -0,0 +1,240 @@
+// Package audit records certificate signing operations in append-only, hash-chained audit log.
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Target of audit log that sends entries to local syslog instead of file
+const TargetSyslog = "syslog"
+
+// GenesisHash is previous hash of the first entry of every chain
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// errSyslogUnsupported is returned when syslog target is used on platform without local syslog
+var errSyslogUnsupported = errors.New("syslog audit target is not supported on this platform")
+
+// maxEntrySize limits size of single entry read from the end of chain, entries are far smaller in practice
+const maxEntrySize = 64 * 1024
+
+// Entry represents single audited certificate signing request. Hash covers all other fields including hash
+// of the previous entry, so that modification, removal or reordering of entries breaks the chain.
+type Entry struct {
+	Seq                     int64     `json:"seq"`
+	Timestamp               time.Time `json:"timestamp"`
+	User                    string    `json:"user"`
+	Profile                 string    `json:"profile"`
+	CSRSubject              string    `json:"csr_subject"`
+	CSRPublicKeyFingerprint string    `json:"csr_public_key_fingerprint"`
+	CACertFingerprint       string    `json:"ca_cert_fingerprint"`
+	SubjectOverride         string    `json:"subject_override,omitempty"`
+	CertificateSerial       string    `json:"certificate_serial,omitempty"`
+	CertificateFingerprint  string    `json:"certificate_fingerprint,omitempty"`
+	MetadataId              string    `json:"metadata_id"`
+	TraceId                 string    `json:"trace_id"`
+	CorrelationId           string    `json:"correlation_id,omitempty"`
+	Outcome                 string    `json:"outcome"`
+	Error                   string    `json:"error,omitempty"`
+	PrevHash                string    `json:"prev_hash"`
+	Hash                    string    `json:"hash"`
+}
+
+// ComputeHash returns hex encoded SHA-256 of JSON encoding of entry without its hash.
+func (entry Entry) ComputeHash() (string, error) {
+	entry.Hash = ""
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit entry, err: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Log represents audit log appending entries to JSONL file or local syslog.
+// Nil log discards entries, so that auditing can be disabled.
+type Log struct {
+	mu sync.Mutex
+	// head is file whose last line is the last entry of the chain, i.e. the log itself
+	// or head file tracking chain of entries sent to syslog
+	head   *os.File
+	syslog syslogWriter
+}
+
+// syslogWriter sends entries to local syslog
+type syslogWriter interface {
+	Notice(msg string) error
+	Close() error
+}
+
+// Open opens audit log of given target, which is path of JSONL file or TargetSyslog.
+// Chain of syslog entries is tracked in headPath. Empty target disables auditing and returns nil log.
+func Open(target string, headPath string) (*Log, error) {
+	if target == "" {
+		return nil, nil
+	}
+
+	if target != TargetSyslog {
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log, err: %w", err)
+		}
+
+		return &Log{head: file}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(headPath), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit head directory, err: %w", err)
+	}
+
+	head, err := os.OpenFile(headPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit head file, err: %w", err)
+	}
+
+	writer, err := newSyslogWriter()
+	if err != nil {
+		_ = head.Close()
+		return nil, fmt.Errorf("failed to connect to syslog, err: %w", err)
+	}
+
+	return &Log{head: head, syslog: writer}, nil
+}
+
+// DefaultHeadPath returns path of file tracking chain of syslog entries in user configuration directory.
+func DefaultHeadPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, "crypto-broker-cli", "audit.head")
+}
+
+// Write chains entry to the last entry of the log and appends it. Sequence number, previous hash and hash
+// of entry are set. The log is locked exclusively while appending, so that concurrent CLI processes keep the chain.
+func (log *Log) Write(entry *Entry) error {
+	if log == nil {
+		return nil
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	if err := lockFile(log.head); err != nil {
+		return fmt.Errorf("failed to lock audit log, err: %w", err)
+	}
+	defer func() { _ = unlockFile(log.head) }()
+
+	last, err := lastEntry(log.head)
+	if err != nil {
+		return err
+	}
+
+	entry.Seq, entry.PrevHash = 1, GenesisHash
+	if last != nil {
+		entry.Seq, entry.PrevHash = last.Seq+1, last.Hash
+	}
+
+	entry.Timestamp = entry.Timestamp.UTC()
+	if entry.Hash, err = entry.ComputeHash(); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry, err: %w", err)
+	}
+
+	if log.syslog == nil {
+		if _, err := log.head.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write audit entry, err: %w", err)
+		}
+
+		return log.head.Sync()
+	}
+
+	if err := log.syslog.Notice(string(line)); err != nil {
+		return fmt.Errorf("failed to send audit entry to syslog, err: %w", err)
+	}
+
+	if err := log.head.Truncate(0); err != nil {
+		return fmt.Errorf("failed to update audit head file, err: %w", err)
+	}
+
+	if _, err := log.head.WriteAt(append(line, '\n'), 0); err != nil {
+		return fmt.Errorf("failed to update audit head file, err: %w", err)
+	}
+
+	return log.head.Sync()
+}
+
+// Close closes audit log.
+func (log *Log) Close() error {
+	if log == nil {
+		return nil
+	}
+
+	var errs []error
+	if log.syslog != nil {
+		errs = append(errs, log.syslog.Close())
+	}
+
+	errs = append(errs, log.head.Close())
+	return errors.Join(errs...)
+}
+
+// lastEntry returns the last entry of file, or nil if file is empty.
+func lastEntry(file *os.File) (*Entry, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log, err: %w", err)
+	}
+
+	offset := max(info.Size()-maxEntrySize, 0)
+	data := make([]byte, info.Size()-offset)
+	if _, err := file.ReadAt(data, offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read audit log, err: %w", err)
+	}
+
+	data = bytes.TrimRight(data, "\n")
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	line := data[bytes.LastIndexByte(data, '\n')+1:]
+	entry, err := ParseEntry(line)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last audit entry, err: %w", err)
+	}
+
+	return entry, nil
+}
+
+// ParseEntry decodes entry from JSON line. Prefix preceding JSON object, e.g. syslog header, is ignored.
+func ParseEntry(line []byte) (*Entry, error) {
+	start := bytes.IndexByte(line, '{')
+	if start < 0 {
+		return nil, errors.New("audit entry not found")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(line[start:]))
+	decoder.DisallowUnknownFields()
+	var entry Entry
+	if err := decoder.Decode(&entry); err != nil {
+		return nil, fmt.Errorf("invalid audit entry, err: %w", err)
+	}
+
+	return &entry, nil
+}
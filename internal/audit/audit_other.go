@@ -0,0 +1,21 @@
+//go:build !unix && !windows
+
+package audit
+
+import "os"
+
+// newSyslogWriter fails, as the platform has no local syslog.
+func newSyslogWriter() (syslogWriter, error) {
+	return nil, errSyslogUnsupported
+}
+
+// lockFile does nothing, as the platform has no file locks. Entries are still serialized within
+// the process, but concurrent CLI processes appending to the same log may break its chain.
+func lockFile(*os.File) error {
+	return nil
+}
+
+// unlockFile does nothing, see lockFile.
+func unlockFile(*os.File) error {
+	return nil
+}
@@ -0,0 +1,162 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogVerify(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	for _, profile := range []string{"Default", "PCI-DSS", "Default"} {
+		// every entry is written by separate process in practice
+		log, err := Open(path, "")
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if err := log.Write(&Entry{Timestamp: time.Now(), Profile: profile, Outcome: OutcomeIssued}); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if err := log.Close(); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	result, err := Verify(bytes.NewReader(data), "", "")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	last, _ := ParseEntry([]byte(lines[2]))
+	if result.Entries != 3 || result.First != 1 || result.Head != last.Hash || last.Seq != 3 {
+		t.Fatalf("expected 3 chained entries, got %+v, last entry %+v", result, last)
+	}
+
+	first, _ := ParseEntry([]byte(lines[0]))
+	second, _ := ParseEntry([]byte(lines[1]))
+	tests := map[string]struct {
+		lines  []string
+		anchor string
+		head   string
+		reason string
+	}{
+		"modified": {
+			lines:  []string{lines[0], strings.Replace(lines[1], "PCI-DSS", "Default", 1), lines[2]},
+			reason: "line 2: hash of entry 2 does not match its content",
+		},
+		"removed": {
+			lines:  []string{lines[0], lines[2]},
+			reason: "line 2: entry 3 does not chain to entry 1",
+		},
+		"reordered": {
+			lines:  []string{lines[1], lines[0], lines[2]},
+			reason: "line 1: first entry does not start the chain, pass hash of the entry it continues as anchor",
+		},
+		"syslog_header": {
+			lines: []string{"Oct 18 16:40:01 host crypto-broker-cli[42]: " + lines[0], lines[1], lines[2]},
+		},
+		"continued_without_anchor": {
+			lines:  []string{lines[1], lines[2]},
+			reason: "line 1: first entry does not start the chain, pass hash of the entry it continues as anchor",
+		},
+		"continued_with_anchor": {
+			lines:  []string{lines[1], lines[2]},
+			anchor: first.Hash,
+		},
+		"continued_with_wrong_anchor": {
+			lines:  []string{lines[2]},
+			anchor: first.Hash,
+			reason: "line 1: entry 3 does not chain to anchor " + first.Hash,
+		},
+		"head_followed_by_new_entries": {
+			lines: []string{lines[0], lines[1], lines[2]},
+			head:  second.Hash,
+		},
+		"head_is_anchor": {
+			lines:  []string{lines[1], lines[2]},
+			anchor: first.Hash,
+			head:   first.Hash,
+		},
+		"truncated_before_head": {
+			lines:  []string{lines[0], lines[1]},
+			head:   last.Hash,
+			reason: "line 2: entry with expected head hash " + last.Hash + " is missing, log was truncated or rewritten",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Verify(strings.NewReader(strings.Join(test.lines, "\n")), test.anchor, test.head)
+			if test.reason == "" {
+				if err != nil {
+					t.Fatalf("expected nil error, got %v", err)
+				}
+
+				return
+			}
+
+			if _, ok := errors.AsType[*ChainError](err); !ok || !strings.HasSuffix(err.Error(), test.reason) {
+				t.Fatalf("expected chain error %q, got %v", test.reason, err)
+			}
+		})
+	}
+}
+
+func TestNewSignCertificateEntry(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "service.example.com"}}, key)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	template := &x509.Certificate{SerialNumber: big.NewInt(0xc0ffee), Subject: pkix.Name{CommonName: "CA"}, NotAfter: time.Now().Add(time.Hour)}
+	caCert, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	subject := "CN=override"
+	entry := NewSignCertificateEntry("Default", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}), caCert, &subject)
+	entry.SetCertificate(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert}))
+
+	spki, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if entry.CSRSubject != "CN=service.example.com" || entry.CSRPublicKeyFingerprint != Fingerprint(spki) ||
+		entry.CACertFingerprint != Fingerprint(caCert) || entry.SubjectOverride != subject {
+		t.Fatalf("expected request details in entry, got %+v", entry)
+	}
+
+	if entry.CertificateSerial != "c0ffee" || entry.CertificateFingerprint != Fingerprint(caCert) {
+		t.Fatalf("expected certificate details in entry, got %+v", entry)
+	}
+}
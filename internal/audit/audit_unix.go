@@ -0,0 +1,29 @@
+//go:build unix
+
+package audit
+
+import (
+	"log/syslog"
+	"os"
+	"syscall"
+)
+
+// newSyslogWriter connects to local syslog, entries are sent with authpriv facility.
+func newSyslogWriter() (syslogWriter, error) {
+	writer, err := syslog.New(syslog.LOG_AUTHPRIV|syslog.LOG_NOTICE, "crypto-broker-cli")
+	if err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// lockFile locks file exclusively, waiting for lock held by another process.
+func lockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+// unlockFile releases lock of file.
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package audit
+
+import (
+	"math"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// newSyslogWriter fails, as Windows has no local syslog.
+func newSyslogWriter() (syslogWriter, error) {
+	return nil, errSyslogUnsupported
+}
+
+// lockFile locks whole file exclusively, waiting for lock held by another process.
+func lockFile(file *os.File) error {
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, math.MaxUint32, math.MaxUint32, new(windows.Overlapped))
+}
+
+// unlockFile releases lock of file.
+func unlockFile(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, math.MaxUint32, math.MaxUint32, new(windows.Overlapped))
+}
@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"os/user"
+	"time"
+)
+
+// outcomes of audited certificate signing requests
+const (
+	OutcomeIssued         = "issued"
+	OutcomeFailed         = "failed"
+	OutcomeShortCircuited = "short_circuited"
+)
+
+// NewSignCertificateEntry returns entry of signing request with CSR and CA certificate, both PEM or DER encoded.
+// Fingerprints of content that cannot be parsed are left empty, so that invalid requests are audited as well.
+func NewSignCertificateEntry(profile string, csr []byte, caCert []byte, subjectOverride *string) *Entry {
+	entry := &Entry{
+		Timestamp: time.Now(),
+		User:      currentUser(),
+		Profile:   profile,
+	}
+
+	if subjectOverride != nil {
+		entry.SubjectOverride = *subjectOverride
+	}
+
+	if request, err := x509.ParseCertificateRequest(decodePEM(csr)); err == nil {
+		entry.CSRSubject = request.Subject.String()
+		entry.CSRPublicKeyFingerprint = Fingerprint(request.RawSubjectPublicKeyInfo)
+	}
+
+	if certificate, err := x509.ParseCertificate(decodePEM(caCert)); err == nil {
+		entry.CACertFingerprint = Fingerprint(certificate.Raw)
+	}
+
+	return entry
+}
+
+// SetCertificate records serial number and fingerprint of signed certificate, PEM or DER encoded.
+func (entry *Entry) SetCertificate(certificate []byte) {
+	der := decodePEM(certificate)
+	entry.CertificateFingerprint = Fingerprint(der)
+	if parsed, err := x509.ParseCertificate(der); err == nil {
+		entry.CertificateSerial = parsed.SerialNumber.Text(16)
+	}
+}
+
+// Fingerprint returns hex encoded SHA-256 fingerprint of DER encoded data.
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// decodePEM returns content of the first PEM block of data, or data itself if it is not PEM encoded.
+func decodePEM(data []byte) []byte {
+	if block, _ := pem.Decode(data); block != nil {
+		return block.Bytes
+	}
+
+	return data
+}
+
+// currentUser returns name of OS user running the CLI.
+func currentUser() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+
+	return os.Getenv("USER")
+}
@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+)
+
+// VerifyResult represents verified audit chain.
+type VerifyResult struct {
+	Entries int
+	// First is sequence number of the first entry, greater than 1 if log continues chain of rotated log
+	First int64
+	// Head is hash of the last entry, it should be kept outside of the log to detect truncation
+	Head string
+}
+
+// ChainError represents broken audit chain.
+type ChainError struct {
+	Line   int
+	Reason string
+}
+
+func (err *ChainError) Error() string {
+	return fmt.Sprintf("audit chain broken at line %d: %s", err.Line, err.Reason)
+}
+
+// Verify reads entries of audit log from r and checks that hash of every entry matches its content, that it chains
+// to the previous entry and that sequence numbers are consecutive. Blank lines are skipped, lines of syslog export
+// may contain header before the entry. The first entry must start the chain, unless anchor is not empty, in which
+// case it must chain to entry with hash anchor, e.g. the last entry of rotated log this log continues.
+// Non-empty head is hash of entry that must be part of the chain, e.g. head printed by previous verification,
+// so that removal of entries appended after it is detected. Returned error is *ChainError if chain is broken.
+func Verify(r io.Reader, anchor string, head string) (*VerifyResult, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, maxEntrySize), maxEntrySize)
+
+	result := &VerifyResult{Head: cmp.Or(anchor, GenesisHash)}
+	headFound := head == "" || head == result.Head
+	var prev *Entry
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		entry, err := ParseEntry(scanner.Bytes())
+		if err != nil {
+			return nil, &ChainError{Line: line, Reason: err.Error()}
+		}
+
+		hash, err := entry.ComputeHash()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case hash != entry.Hash:
+			return nil, &ChainError{Line: line, Reason: fmt.Sprintf("hash of entry %d does not match its content", entry.Seq)}
+		case prev == nil && anchor == "" && (entry.Seq != 1 || entry.PrevHash != GenesisHash):
+			return nil, &ChainError{Line: line, Reason: "first entry does not start the chain, pass hash of the entry it continues as anchor"}
+		case prev == nil && entry.Seq < 1:
+			return nil, &ChainError{Line: line, Reason: fmt.Sprintf("invalid sequence number %d", entry.Seq)}
+		case prev == nil && entry.PrevHash != anchor && anchor != "":
+			return nil, &ChainError{Line: line, Reason: fmt.Sprintf("entry %d does not chain to anchor %s", entry.Seq, anchor)}
+		case prev != nil && entry.PrevHash != prev.Hash:
+			return nil, &ChainError{Line: line, Reason: fmt.Sprintf("entry %d does not chain to entry %d", entry.Seq, prev.Seq)}
+		case prev != nil && entry.Seq != prev.Seq+1:
+			return nil, &ChainError{Line: line, Reason: fmt.Sprintf("entry %d follows entry %d", entry.Seq, prev.Seq)}
+		}
+
+		if prev == nil {
+			result.First = entry.Seq
+		}
+
+		prev = entry
+		result.Entries++
+		result.Head = entry.Hash
+		headFound = headFound || entry.Hash == head
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log, err: %w", err)
+	}
+
+	if !headFound {
+		return nil, &ChainError{Line: line, Reason: fmt.Sprintf("entry with expected head hash %s is missing, log was truncated or rewritten", head)}
+	}
+
+	return result, nil
+}
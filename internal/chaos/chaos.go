@@ -0,0 +1,197 @@
+// Package chaos contains client-side fault and latency injection.
+// It is used to exercise the resilience paths (retries, circuit breaker) of the crypto broker client library from the CLI.
+package chaos
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// constants that represents supported latency distributions.
+const (
+	DistributionFixed       = "fixed"
+	DistributionUniform     = "uniform"
+	DistributionNormal      = "normal"
+	DistributionExponential = "exponential"
+)
+
+// ErrDropped is returned for requests dropped by the injector.
+// It carries codes.Unavailable so it is indistinguishable from a broker that went away.
+var ErrDropped = status.Error(codes.Unavailable, "request dropped by fault injection")
+
+// Config defines which faults are injected and how often.
+// Rates are percentages in range 0-100.
+type Config struct {
+	// DropRate percentage of requests that are never sent and fail with ErrDropped
+	DropRate float64
+
+	// Latency base latency added before each request is sent
+	Latency time.Duration
+
+	// LatencyJitter spread of the latency distribution (ignored by fixed and exponential distributions)
+	LatencyJitter time.Duration
+
+	// LatencyDistribution one of fixed, uniform, normal or exponential
+	LatencyDistribution string
+
+	// CancelRate percentage of requests cancelled mid-flight
+	CancelRate float64
+
+	// CancelAfter time after which a request selected for cancellation is cancelled
+	CancelAfter time.Duration
+
+	// CorruptRate percentage of requests whose payload size is corrupted
+	CorruptRate float64
+
+	// CorruptSize size in bytes of the corrupted payload field
+	CorruptSize int
+
+	// Seed seeds the random source, 0 means random seed
+	Seed uint64
+}
+
+// Enabled reports whether any fault is configured.
+func (config Config) Enabled() bool {
+	return config.DropRate > 0 || config.Latency > 0 || config.LatencyJitter > 0 || config.CancelRate > 0 || config.CorruptRate > 0
+}
+
+// Validate checks that configuration values are in supported ranges.
+func (config Config) Validate() error {
+	rates := map[string]float64{"drop": config.DropRate, "cancel": config.CancelRate, "corrupt": config.CorruptRate}
+	for name, rate := range rates {
+		if err := ValidateRate(rate); err != nil {
+			return fmt.Errorf("%s %w", name, err)
+		}
+	}
+
+	if config.Latency < 0 || config.LatencyJitter < 0 || config.CancelAfter < 0 {
+		return fmt.Errorf("durations must not be negative")
+	}
+
+	if config.CorruptSize < 0 {
+		return fmt.Errorf("corrupt size must not be negative, got %d", config.CorruptSize)
+	}
+
+	return ValidateDistribution(config.LatencyDistribution)
+}
+
+// ValidateRate checks that rate is percentage in range 0-100.
+func ValidateRate(rate float64) error {
+	if math.IsNaN(rate) || rate < 0 || rate > 100 {
+		return fmt.Errorf("rate must be between 0 and 100, got %v", rate)
+	}
+
+	return nil
+}
+
+// ValidateDistribution checks that latency distribution is supported, empty distribution means fixed latency.
+func ValidateDistribution(distribution string) error {
+	switch strings.ToLower(distribution) {
+	case "", DistributionFixed, DistributionUniform, DistributionNormal, DistributionExponential:
+		return nil
+	}
+
+	return fmt.Errorf("latency distribution must be %s, %s, %s or %s, got %q",
+		DistributionFixed, DistributionUniform, DistributionNormal, DistributionExponential, distribution)
+}
+
+// Fault describes faults selected for a single request.
+type Fault struct {
+	Drop        bool
+	Delay       time.Duration
+	CancelAfter time.Duration
+	CorruptSize int
+}
+
+// Injected reports whether the request is affected by any fault.
+func (fault Fault) Injected() bool {
+	return fault.Drop || fault.Delay > 0 || fault.CancelAfter > 0 || fault.CorruptSize > 0
+}
+
+// Injector draws faults for consecutive requests. It is safe for concurrent use.
+type Injector struct {
+	config Config
+	mu     sync.Mutex
+	rand   *rand.Rand
+}
+
+// NewInjector initializes injector with given configuration.
+func NewInjector(config Config) (*Injector, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	seed := config.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+
+	return &Injector{
+		config: config,
+		rand:   rand.New(rand.NewPCG(seed, seed)),
+	}, nil
+}
+
+// Next selects faults for the next request.
+func (injector *Injector) Next() Fault {
+	injector.mu.Lock()
+	defer injector.mu.Unlock()
+
+	var fault Fault
+	if injector.hit(injector.config.DropRate) {
+		fault.Drop = true
+		return fault
+	}
+
+	fault.Delay = injector.delay()
+	if injector.hit(injector.config.CancelRate) {
+		fault.CancelAfter = max(injector.config.CancelAfter, time.Nanosecond)
+	}
+
+	if injector.hit(injector.config.CorruptRate) {
+		fault.CorruptSize = injector.config.CorruptSize
+	}
+
+	return fault
+}
+
+// hit reports whether an event with given percentage happens.
+func (injector *Injector) hit(rate float64) bool {
+	return rate > 0 && injector.rand.Float64()*100 < rate
+}
+
+// delay draws latency from the configured distribution. It never returns negative values.
+func (injector *Injector) delay() time.Duration {
+	latency := float64(injector.config.Latency)
+	jitter := float64(injector.config.LatencyJitter)
+
+	var delay float64
+	switch strings.ToLower(injector.config.LatencyDistribution) {
+	case DistributionUniform:
+		delay = latency + (injector.rand.Float64()*2-1)*jitter
+	case DistributionNormal:
+		delay = latency + injector.rand.NormFloat64()*jitter
+	case DistributionExponential:
+		delay = injector.rand.ExpFloat64() * latency
+	default:
+		delay = latency
+	}
+
+	return time.Duration(max(delay, 0))
+}
+
+// CorruptID returns identifier padded or truncated to given size.
+func CorruptID(id string, size int) string {
+	if size <= len(id) {
+		return id[:size]
+	}
+
+	return id + strings.Repeat("x", size-len(id))
+}
@@ -0,0 +1,135 @@
+package chaos
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("zero_value_is_valid_and_disabled", func(t *testing.T) {
+		t.Parallel()
+		config := Config{}
+		if err := config.Validate(); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if config.Enabled() {
+			t.Fatalf("expected zero value config to be disabled")
+		}
+	})
+
+	t.Run("rate_out_of_range", func(t *testing.T) {
+		t.Parallel()
+		if err := (Config{DropRate: 101}).Validate(); err == nil {
+			t.Fatalf("expected error for drop rate above 100")
+		}
+
+		if err := (Config{CancelRate: -1}).Validate(); err == nil {
+			t.Fatalf("expected error for negative cancel rate")
+		}
+
+		if err := (Config{CorruptRate: math.NaN()}).Validate(); err == nil {
+			t.Fatalf("expected error for NaN corrupt rate")
+		}
+	})
+
+	t.Run("jitter_alone_is_enabled", func(t *testing.T) {
+		t.Parallel()
+		if !(Config{LatencyJitter: time.Millisecond, LatencyDistribution: DistributionUniform}).Enabled() {
+			t.Fatalf("expected config with latency jitter to be enabled")
+		}
+	})
+
+	t.Run("unknown_distribution", func(t *testing.T) {
+		t.Parallel()
+		if err := (Config{LatencyDistribution: "pareto"}).Validate(); err == nil {
+			t.Fatalf("expected error for unknown distribution")
+		}
+	})
+}
+
+func TestInjector_Next(t *testing.T) {
+	t.Parallel()
+
+	t.Run("always_drop", func(t *testing.T) {
+		t.Parallel()
+		injector, err := NewInjector(Config{DropRate: 100, Latency: time.Second, Seed: 1})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		fault := injector.Next()
+		if !fault.Drop || fault.Delay != 0 {
+			t.Fatalf("expected dropped request without delay, got %#v", fault)
+		}
+	})
+
+	t.Run("fixed_latency_cancel_and_corrupt", func(t *testing.T) {
+		t.Parallel()
+		injector, err := NewInjector(Config{
+			Latency:             5 * time.Millisecond,
+			LatencyDistribution: DistributionFixed,
+			CancelRate:          100,
+			CancelAfter:         time.Millisecond,
+			CorruptRate:         100,
+			CorruptSize:         128,
+			Seed:                1,
+		})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		fault := injector.Next()
+		if fault.Drop || fault.Delay != 5*time.Millisecond || fault.CancelAfter != time.Millisecond || fault.CorruptSize != 128 {
+			t.Fatalf("unexpected fault: %#v", fault)
+		}
+	})
+
+	t.Run("distributions_never_negative", func(t *testing.T) {
+		t.Parallel()
+		for _, distribution := range []string{DistributionUniform, DistributionNormal, DistributionExponential} {
+			injector, err := NewInjector(Config{
+				Latency:             time.Millisecond,
+				LatencyJitter:       10 * time.Millisecond,
+				LatencyDistribution: distribution,
+				Seed:                42,
+			})
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+
+			for range 1000 {
+				if fault := injector.Next(); fault.Delay < 0 {
+					t.Fatalf("%s: negative delay %v", distribution, fault.Delay)
+				}
+			}
+		}
+	})
+
+	t.Run("same_seed_same_faults", func(t *testing.T) {
+		t.Parallel()
+		config := Config{DropRate: 50, Latency: time.Millisecond, LatencyJitter: time.Millisecond, LatencyDistribution: DistributionNormal, Seed: 7}
+		first, _ := NewInjector(config)
+		second, _ := NewInjector(config)
+		for range 100 {
+			if a, b := first.Next(), second.Next(); a != b {
+				t.Fatalf("expected identical faults, got %#v and %#v", a, b)
+			}
+		}
+	})
+}
+
+func TestCorruptID(t *testing.T) {
+	t.Parallel()
+
+	if got := CorruptID("abcdef", 3); got != "abc" {
+		t.Fatalf("expected truncated id, got %q", got)
+	}
+
+	if got := CorruptID("abc", 6); got != "abcxxx" {
+		t.Fatalf("expected padded id, got %q", got)
+	}
+}
@@ -4,35 +4,39 @@ package clog
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"strings"
 
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/correlation"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otlpconfig"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otlpfile"
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/sdk/log"
+	"google.golang.org/grpc/credentials"
 )
 
 var (
 	logsExporter = ""
 	logLevel     = slog.LevelInfo
 	logOutput    = os.Stdout
+	logFormat    = logFormatJSON
 	logHandler   slog.Handler
 	serviceName  = defaultServiceName
-	otlpEndpoint = ""
-	apiToken     = ""
 )
 
+var redactPolicy = redactPolicyFull
+
 func init() {
 	if customServiceName := os.Getenv(env.OTEL_SERVICE_NAME); customServiceName != "" {
 		serviceName = customServiceName
 	}
 
-	apiToken = os.Getenv(env.OTEL_EXPORTER_OTLP_HEADERS_AUTHORIZATION)
-	otlpEndpoint = os.Getenv(env.OTEL_EXPORTER_OTLP_ENDPOINT)
 	logsExporter = strings.ToLower(strings.TrimSpace(os.Getenv(env.OTEL_LOGS_EXPORTER)))
 	if userProvidedLogLevel := strings.ToLower(os.Getenv(env.LOG_LEVEL)); userProvidedLogLevel != "" {
 		switch userProvidedLogLevel {
@@ -62,32 +66,84 @@ func init() {
 		}
 	}
 
-	logHandler = slog.NewJSONHandler(logOutput, &slog.HandlerOptions{Level: logLevel}) // default
 	userProvidedLogFormat := strings.ToLower(os.Getenv(env.LOG_FORMAT))
 	if userProvidedLogFormat != "" {
 		switch userProvidedLogFormat {
 		case strings.ToLower(logFormatJSON):
-			logHandler = slog.NewJSONHandler(logOutput, &slog.HandlerOptions{Level: logLevel})
+			logFormat = logFormatJSON
 		case strings.ToLower(logFormatText):
-			logHandler = slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: logLevel})
+			logFormat = logFormatText
 		default:
 			panic(fmt.Sprintf("invalid log format provided: %s, available formats: %s, %s",
 				userProvidedLogFormat, logFormatJSON, logFormatText))
 		}
 	}
+
+	if userProvidedRedactPolicy := strings.ToLower(os.Getenv(env.LOG_REDACT)); userProvidedRedactPolicy != "" {
+		switch userProvidedRedactPolicy {
+		case redactPolicyFull, redactPolicyDigest, redactPolicyLength:
+			redactPolicy = userProvidedRedactPolicy
+		default:
+			panic(fmt.Sprintf("invalid log redaction policy provided: %s, available policies: %s, %s, %s",
+				userProvidedRedactPolicy, redactPolicyFull, redactPolicyDigest, redactPolicyLength))
+		}
+	}
+
+	logHandler = newConsoleHandler(logOutput)
+}
+
+// LoggerProvider holds the OpenTelemetry logger provider of OTLP and file log exporters, if any
+type LoggerProvider struct {
+	lp *log.LoggerProvider
+}
+
+// Shutdown flushes pending log records and shuts down the logger provider.
+// Records logged afterwards are written only to console, if console logging is enabled.
+func (lp *LoggerProvider) Shutdown(ctx context.Context) error {
+	if lp != nil && lp.lp != nil {
+		return lp.lp.Shutdown(ctx)
+	}
+
+	return nil
+}
+
+// newConsoleHandler creates console handler of configured log format and level writing to output.
+func newConsoleHandler(output io.Writer) slog.Handler {
+	if logFormat == logFormatText {
+		return slog.NewTextHandler(output, &slog.HandlerOptions{Level: logLevel})
+	}
+
+	return slog.NewJSONHandler(output, &slog.HandlerOptions{Level: logLevel})
+}
+
+// SetConsoleOutput overrides output of console logging configured by LOG_OUTPUT.
+// It must be called before SetupGlobalLogger, e.g. to keep stdout free for command output.
+func SetConsoleOutput(output io.Writer) {
+	logHandler = newConsoleHandler(output)
+}
+
+// SetDefaultLevel overrides default level of console logging unless level is configured by LOG_LEVEL,
+// e.g. to keep interactive output readable. It must be called before SetConsoleOutput and SetupGlobalLogger.
+func SetDefaultLevel(level slog.Level) {
+	if os.Getenv(env.LOG_LEVEL) == "" {
+		logLevel = level
+		logHandler = newConsoleHandler(logOutput)
+	}
 }
 
 // SetupGlobalLogger initializes the crypto broker logger.
+// Values of sensitive attributes are redacted according to LOG_REDACT policy, see Sensitive.
 // It predefines defaults for logger. If user provides custom values that are not supported by the logger, it panics.
 // It sets the logger to the default global logger.
-// Supports OTEL_LOGS_EXPORTER with values: "console", "otlp", "otlphttp", "otlpgrpc", or comma-separated combinations
-func SetupGlobalLogger(ctx context.Context) *slog.Logger {
+// Supports OTEL_LOGS_EXPORTER with values: "console", "otlp", "otlphttp", "otlpgrpc", "file", or comma-separated combinations.
+// Returned logger provider must be shut down before exit to flush pending records of OTLP and file exporters.
+func SetupGlobalLogger(ctx context.Context) (*slog.Logger, *LoggerProvider) {
 	exporters := strings.Split(logsExporter, ",")
 	for i, exporter := range exporters {
 		exporters[i] = strings.TrimSpace(exporter)
 	}
 
-	var useOTLPHTTP, useOTLPGRPC, useOTLPAuto, useConsole bool
+	var useOTLPHTTP, useOTLPGRPC, useOTLPAuto, useFile, useConsole bool
 	for _, exporter := range exporters {
 		switch exporter {
 		case keywordExporterOTLPHTTP:
@@ -96,6 +152,8 @@ func SetupGlobalLogger(ctx context.Context) *slog.Logger {
 			useOTLPGRPC = true
 		case keywordExporterOTLP:
 			useOTLPAuto = true
+		case keywordExporterFile:
+			useFile = true
 		case keywordExporterConsole:
 			useConsole = true
 		case "":
@@ -106,32 +164,60 @@ func SetupGlobalLogger(ctx context.Context) *slog.Logger {
 		}
 	}
 
-	if !useOTLPHTTP && !useOTLPGRPC && !useOTLPAuto && !useConsole {
-		useConsole = true
+	var logExporters []log.Exporter
+	if useOTLPHTTP || useOTLPGRPC || useOTLPAuto {
+		protocol := ""
+		if useOTLPHTTP {
+			protocol = otlpconfig.ProtocolHTTPProtobuf
+		} else if useOTLPGRPC {
+			protocol = otlpconfig.ProtocolGRPC
+		}
+
+		otlpExporter, err := newOTLPExporter(ctx, protocol)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create OTLP log exporter, falling back to console logging: %v\n", err)
+			useConsole = true
+		} else {
+			logExporters = append(logExporters, otlpExporter)
+		}
 	}
 
-	var otlpLogger *slog.Logger
-	if useOTLPHTTP {
-		otlpLogger = setupOTLPLoggerWithProtocol(ctx, "http")
-	} else if useOTLPGRPC {
-		otlpLogger = setupOTLPLoggerWithProtocol(ctx, "grpc")
-	} else if useOTLPAuto {
-		otlpLogger = setupOTLPLogger(ctx)
+	if useFile {
+		fileExporter, err := otlpfile.NewLogExporter()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create file log exporter, falling back to console logging: %v\n", err)
+			useConsole = true
+		} else {
+			logExporters = append(logExporters, fileExporter)
+		}
 	}
 
-	if (useOTLPHTTP || useOTLPGRPC || useOTLPAuto) && useConsole {
-		return setupMultiLoggerWithOTLP(otlpLogger)
-	} else if useOTLPHTTP || useOTLPGRPC || useOTLPAuto {
-		return otlpLogger
+	var logger *slog.Logger
+	loggerProvider := &LoggerProvider{}
+	if len(logExporters) > 0 {
+		var providerLogger *slog.Logger
+		providerLogger, loggerProvider.lp = setupProviderLogger(logExporters)
+		logger = providerLogger
+		if useConsole {
+			logger = setupMultiLoggerWithOTLP(providerLogger)
+		}
 	} else {
-		return setupConsoleLogger()
+		logger = setupConsoleLogger()
+	}
+
+	// Correlation id is added to every log record, including records of the library logged through the default logger
+	if correlationId := correlation.IDFromContext(ctx); correlationId != "" {
+		logger = logger.With(slog.String(logKeyCorrelationId, correlationId))
+		slog.SetDefault(logger)
 	}
+
+	return logger, loggerProvider
 }
 
-// setupConsoleLogger sets up the traditional console-based logging
+// setupConsoleLogger sets up the traditional console-based logging with trace context of records
 // this function may panic if the log level or log output is invalid
 func setupConsoleLogger() *slog.Logger {
-	logger := slog.New(logHandler)
+	logger := slog.New(newRedactHandler(newTraceHandler(logHandler)))
 	fixedLogger := logger.With(slog.String("service", serviceName))
 	slog.SetDefault(fixedLogger)
 
@@ -204,133 +290,89 @@ func (h *multiHandler) WithGroup(name string) slog.Handler {
 	return &multiHandler{handlers: newHandlers}
 }
 
-// setupOTLPLogger sets up OpenTelemetry OTLP logging
-// Automatically detects protocol: HTTP for full URLs, gRPC for host:port
-func setupOTLPLogger(ctx context.Context) *slog.Logger {
-	isHTTP := strings.HasPrefix(otlpEndpoint, "http://") || strings.HasPrefix(otlpEndpoint, "https://")
-	if isHTTP {
-		return setupOTLPLoggerHTTP(ctx, otlpEndpoint)
+// newOTLPExporter creates OpenTelemetry OTLP log exporter with protocol requested by exporter name.
+// Empty protocol is resolved from OTEL_EXPORTER_OTLP_PROTOCOL, or detected from the endpoint:
+// HTTP for full URLs, gRPC for host:port
+func newOTLPExporter(ctx context.Context, protocol string) (log.Exporter, error) {
+	config, err := otlpconfig.Load(otlpconfig.SignalLogs, protocol)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP log exporter configuration: %w", err)
 	}
 
-	return setupOTLPLoggerGRPC(ctx, otlpEndpoint)
-}
-
-// setupOTLPLoggerWithProtocol sets up OpenTelemetry OTLP logging with explicit protocol
-func setupOTLPLoggerWithProtocol(ctx context.Context, protocol string) *slog.Logger {
-	switch protocol {
-	case "http":
-		return setupOTLPLoggerHTTP(ctx, otlpEndpoint)
-	case "grpc":
-		return setupOTLPLoggerGRPC(ctx, otlpEndpoint)
-	default:
-		return setupOTLPLogger(ctx)
+	if config.Protocol == otlpconfig.ProtocolHTTPProtobuf {
+		return newOTLPExporterHTTP(ctx, config)
 	}
-}
 
-// setupOTLPLoggerHTTP sets up OTLP logging via HTTP
-func setupOTLPLoggerHTTP(ctx context.Context, otlpEndpoint string) *slog.Logger {
-	var endpointHost string
-	var urlPath string
-	useSecure := true
+	return newOTLPExporterGRPC(ctx, config)
+}
 
-	if strings.HasPrefix(otlpEndpoint, "http://") {
-		otlpEndpoint = strings.TrimPrefix(otlpEndpoint, "http://")
-		useSecure = false
-	} else if strings.HasPrefix(otlpEndpoint, "https://") {
-		otlpEndpoint = strings.TrimPrefix(otlpEndpoint, "https://")
-		useSecure = true
+// setupProviderLogger sets up OpenTelemetry logger provider with batch processor per exporter
+func setupProviderLogger(logExporters []log.Exporter) (*slog.Logger, *log.LoggerProvider) {
+	var options []log.LoggerProviderOption
+	for _, logExporter := range logExporters {
+		options = append(options, log.WithProcessor(log.NewBatchProcessor(logExporter)))
 	}
 
-	// Split endpoint and path
-	parts := strings.SplitN(otlpEndpoint, "/", 2)
-	endpointHost = parts[0]
-	if len(parts) > 1 {
-		urlPath = "/" + parts[1]
-	}
+	loggerProvider := log.NewLoggerProvider(options...)
+	global.SetLoggerProvider(loggerProvider)
+	handler := otelslog.NewHandler(serviceName, otelslog.WithLoggerProvider(loggerProvider))
+	logger := slog.New(newRedactHandler(handler))
+	slog.SetDefault(logger)
 
-	// Remove /v1/logs suffix if present in the path, as otlploghttp will add it automatically
-	urlPath = strings.TrimSuffix(urlPath, "/v1/logs")
-	headers := make(map[string]string)
-	if apiToken != "" {
-		headers["Authorization"] = apiToken
-	}
+	return logger, loggerProvider
+}
 
+// newOTLPExporterHTTP creates OTLP log exporter via HTTP
+func newOTLPExporterHTTP(ctx context.Context, config *otlpconfig.Config) (log.Exporter, error) {
 	opts := []otlploghttp.Option{
-		otlploghttp.WithEndpoint(endpointHost),
-		otlploghttp.WithHeaders(headers),
+		otlploghttp.WithEndpoint(config.Host),
+		otlploghttp.WithHeaders(config.Headers),
 	}
 
-	// Use insecure (HTTP) only if explicitly specified with http:// scheme
-	if !useSecure {
+	// Use insecure (HTTP) only if explicitly specified with http:// scheme or OTEL_EXPORTER_OTLP_INSECURE
+	if config.Insecure {
 		opts = append(opts, otlploghttp.WithInsecure())
+	} else {
+		opts = append(opts, otlploghttp.WithTLSClientConfig(config.TLSConfig))
 	}
 
 	// Add custom URL path if present
-	if urlPath != "" {
-		opts = append(opts, otlploghttp.WithURLPath(urlPath+"/v1/logs"))
+	if config.URLPath != "" {
+		opts = append(opts, otlploghttp.WithURLPath(config.URLPath))
 	}
 
-	logExporter, err := otlploghttp.New(ctx, opts...)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to create OTLP HTTP log exporter, falling back to console logging: %v\n", err)
-		return setupConsoleLogger()
+	if config.Compression == otlpconfig.CompressionGzip {
+		opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
 	}
 
-	logProcessor := log.NewBatchProcessor(logExporter)
-	loggerProvider := log.NewLoggerProvider(
-		log.WithProcessor(logProcessor),
-	)
-	global.SetLoggerProvider(loggerProvider)
-	handler := otelslog.NewHandler(serviceName, otelslog.WithLoggerProvider(loggerProvider))
-	logger := slog.New(handler)
-	slog.SetDefault(logger)
+	if config.Timeout > 0 {
+		opts = append(opts, otlploghttp.WithTimeout(config.Timeout))
+	}
 
-	return logger
+	return otlploghttp.New(ctx, opts...)
 }
 
-// setupOTLPLoggerGRPC sets up OTLP logging via gRPC
-func setupOTLPLoggerGRPC(ctx context.Context, otlpEndpoint string) *slog.Logger {
-	var endpointHost string
-	useSecure := true
-
-	if strings.HasPrefix(otlpEndpoint, "http://") {
-		otlpEndpoint = strings.TrimPrefix(otlpEndpoint, "http://")
-		useSecure = false
-	} else if strings.HasPrefix(otlpEndpoint, "https://") {
-		otlpEndpoint = strings.TrimPrefix(otlpEndpoint, "https://")
-		useSecure = true
+// newOTLPExporterGRPC creates OTLP log exporter via gRPC
+func newOTLPExporterGRPC(ctx context.Context, config *otlpconfig.Config) (log.Exporter, error) {
+	opts := []otlploggrpc.Option{
+		otlploggrpc.WithEndpoint(config.Host),
+		otlploggrpc.WithHeaders(config.Headers),
 	}
 
-	// Split endpoint and path - for gRPC we only need the host:port part
-	parts := strings.SplitN(otlpEndpoint, "/", 2)
-	endpointHost = parts[0]
-	headers := make(map[string]string)
-	if apiToken != "" {
-		headers["authorization"] = apiToken
+	// Use insecure (HTTP/2 without TLS) for http:// scheme, OTEL_EXPORTER_OTLP_INSECURE or host:port without certificates
+	if config.Insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(config.TLSConfig)))
 	}
 
-	opts := []otlploggrpc.Option{
-		otlploggrpc.WithEndpoint(endpointHost),
-		otlploggrpc.WithHeaders(headers),
+	if config.Compression == otlpconfig.CompressionGzip {
+		opts = append(opts, otlploggrpc.WithCompressor(otlpconfig.CompressionGzip))
 	}
 
-	// Use insecure (HTTP/2 without TLS) only if explicitly specified with http:// scheme
-	if !useSecure {
-		opts = append(opts, otlploggrpc.WithInsecure())
-	}
-	logExporter, err := otlploggrpc.New(ctx, opts...)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to create OTLP gRPC log exporter, falling back to console logging: %v\n", err)
-		return setupConsoleLogger()
+	if config.Timeout > 0 {
+		opts = append(opts, otlploggrpc.WithTimeout(config.Timeout))
 	}
-	logProcessor := log.NewBatchProcessor(logExporter)
-	loggerProvider := log.NewLoggerProvider(
-		log.WithProcessor(logProcessor),
-	)
-	global.SetLoggerProvider(loggerProvider)
-	handler := otelslog.NewHandler(serviceName, otelslog.WithLoggerProvider(loggerProvider))
-	logger := slog.New(handler)
-	slog.SetDefault(logger)
 
-	return logger
+	return otlploggrpc.New(ctx, opts...)
 }
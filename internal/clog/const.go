@@ -20,12 +20,28 @@ const (
 	logOutputStderr = "stderr"
 )
 
+// predefined keywords representing redaction policy of sensitive log attributes
+const (
+	redactPolicyFull   = "full"
+	redactPolicyDigest = "digest"
+	redactPolicyLength = "length"
+)
+
 // predefined keywords representing log exporters
 const (
 	keywordExporterConsole  = "console"
 	keywordExporterOTLPHTTP = "otlphttp"
 	keywordExporterOTLPGRPC = "otlpgrpc"
 	keywordExporterOTLP     = "otlp"
+	keywordExporterFile     = "file"
+)
+
+// predefined keys of log record attributes
+const (
+	logKeyCorrelationId = "correlation_id"
+	logKeyTraceId       = "trace_id"
+	logKeySpanId        = "span_id"
+	logKeyTraceFlags    = "trace_flags"
 )
 
 // predefined service name
@@ -0,0 +1,115 @@
+package clog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// sensitiveKeys are keys of log record attributes whose values are redacted regardless of their type,
+// so that sensitive data logged by mistake, e.g. by the library, never reaches log backends.
+var sensitiveKeys = []string{
+	"input", "csr", "ca_cert", "ca_key", "ca_private_key", "private_key", "signing_key", "certificate", "password", "token",
+}
+
+// Sensitive represents sensitive data, e.g. hash input or CA private key, that is logged according to redaction policy
+// configured by CRYPTO_BROKER_LOG_REDACT: "full" (default), "digest" or "length". It is never logged verbatim.
+type Sensitive []byte
+
+// LogValue implements slog.LogValuer.
+func (s Sensitive) LogValue() slog.Value {
+	return redactedValue(s)
+}
+
+// SensitiveString represents sensitive text, see Sensitive.
+type SensitiveString string
+
+// LogValue implements slog.LogValuer.
+func (s SensitiveString) LogValue() slog.Value {
+	return redactedValue([]byte(s))
+}
+
+// redactedValue returns value replacing data according to configured redaction policy.
+func redactedValue(data []byte) slog.Value {
+	switch redactPolicy {
+	case redactPolicyDigest:
+		digest := sha256.Sum256(data)
+		return slog.StringValue("sha256:" + hex.EncodeToString(digest[:]))
+	case redactPolicyLength:
+		return slog.StringValue(fmt.Sprintf("[REDACTED %d bytes]", len(data)))
+	default:
+		return slog.StringValue("[REDACTED]")
+	}
+}
+
+// redactHandler wraps slog.Handler and redacts values of attributes with sensitive keys, including nested groups.
+// Values implementing slog.LogValuer, e.g. Sensitive, are resolved before their key is checked.
+type redactHandler struct {
+	slog.Handler
+}
+
+// newRedactHandler wraps handler with redaction of sensitive attributes.
+func newRedactHandler(handler slog.Handler) *redactHandler {
+	return &redactHandler{Handler: handler}
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(attr))
+		return true
+	})
+
+	return h.Handler.Handle(ctx, redacted)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = redactAttr(attr)
+	}
+
+	return newRedactHandler(h.Handler.WithAttrs(redacted))
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return newRedactHandler(h.Handler.WithGroup(name))
+}
+
+// redactAttr returns attribute with redacted value if its key is sensitive, groups are redacted recursively.
+func redactAttr(attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() == slog.KindLogValuer {
+		switch attr.Value.Any().(type) {
+		case Sensitive, SensitiveString:
+			// already redacted according to policy
+			return slog.Attr{Key: attr.Key, Value: attr.Value.Resolve()}
+		}
+	}
+
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, groupAttr := range group {
+			redacted[i] = redactAttr(groupAttr)
+		}
+
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+	}
+
+	if !slices.Contains(sensitiveKeys, strings.ToLower(attr.Key)) {
+		return attr
+	}
+
+	if attr.Value.Kind() == slog.KindAny {
+		if data, ok := attr.Value.Any().([]byte); ok {
+			return slog.Attr{Key: attr.Key, Value: redactedValue(data)}
+		}
+	}
+
+	return slog.Attr{Key: attr.Key, Value: redactedValue([]byte(attr.Value.String()))}
+}
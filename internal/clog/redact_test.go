@@ -0,0 +1,71 @@
+package clog
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// loggedPayload mimics payload type implementing slog.LogValuer
+type loggedPayload struct {
+	input []byte
+	key   []byte
+}
+
+func (payload loggedPayload) LogValue() slog.Value {
+	return slog.GroupValue(slog.Any("input", Sensitive(payload.input)), slog.Any("ca_private_key", payload.key), slog.String("profile", "Default"))
+}
+
+func TestRedactHandler(t *testing.T) {
+	const secret = "correct horse battery staple"
+
+	tests := []struct {
+		policy   string
+		redacted string
+	}{
+		{policy: redactPolicyFull, redacted: "[REDACTED]"},
+		{policy: redactPolicyDigest, redacted: "sha256:c4bbcb1fbec99d65bf59d85c8cb62ee2db963f0fe106f483d9afa73bd4e39a8a"},
+		{policy: redactPolicyLength, redacted: "[REDACTED 28 bytes]"},
+	}
+
+	defaultPolicy := redactPolicy
+	defer func() { redactPolicy = defaultPolicy }()
+
+	for _, test := range tests {
+		t.Run(test.policy, func(t *testing.T) {
+			redactPolicy = test.policy
+
+			var buf bytes.Buffer
+			logger := slog.New(newRedactHandler(slog.NewTextHandler(&buf, nil))).With("token", secret)
+			logger.Info("Hashing input", "input", secret, "request", loggedPayload{input: []byte(secret), key: []byte(secret)}, "profile", "Default")
+
+			output := buf.String()
+			if strings.Contains(output, secret) {
+				t.Fatalf("expected secret to be redacted, got %s", output)
+			}
+
+			for _, want := range []string{
+				"token=" + quoted(test.redacted),
+				"input=" + quoted(test.redacted),
+				"request.input=" + quoted(test.redacted),
+				"request.ca_private_key=" + quoted(test.redacted),
+				"request.profile=Default",
+				"profile=Default",
+			} {
+				if !strings.Contains(output, want) {
+					t.Fatalf("expected record to contain %s, got %s", want, output)
+				}
+			}
+		})
+	}
+}
+
+// quoted returns value as quoted by text handler if it contains spaces
+func quoted(value string) string {
+	if strings.Contains(value, " ") {
+		return `"` + value + `"`
+	}
+
+	return value
+}
@@ -0,0 +1,40 @@
+package clog
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceHandler wraps slog.Handler and adds trace id, span id and trace flags of the span
+// found in the context of each record, so console logs can be correlated with traces.
+type traceHandler struct {
+	slog.Handler
+}
+
+// newTraceHandler wraps handler with trace context injection.
+func newTraceHandler(handler slog.Handler) *traceHandler {
+	return &traceHandler{Handler: handler}
+}
+
+func (h *traceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		r = r.Clone()
+		r.AddAttrs(
+			slog.String(logKeyTraceId, spanContext.TraceID().String()),
+			slog.String(logKeySpanId, spanContext.SpanID().String()),
+			slog.String(logKeyTraceFlags, spanContext.TraceFlags().String()),
+		)
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newTraceHandler(h.Handler.WithAttrs(attrs))
+}
+
+func (h *traceHandler) WithGroup(name string) slog.Handler {
+	return newTraceHandler(h.Handler.WithGroup(name))
+}
@@ -0,0 +1,48 @@
+package clog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(newTraceHandler(slog.NewJSONHandler(&buf, nil))).With("service", "test")
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	logger.InfoContext(trace.ContextWithSpanContext(context.Background(), spanContext), "with span")
+	logger.InfoContext(context.Background(), "without span")
+
+	decoder := json.NewDecoder(&buf)
+	var withSpan, withoutSpan map[string]any
+	if err := decoder.Decode(&withSpan); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if err := decoder.Decode(&withoutSpan); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if withSpan[logKeyTraceId] != "4bf92f3577b34da6a3ce929d0e0e4736" || withSpan[logKeySpanId] != "00f067aa0ba902b7" || withSpan[logKeyTraceFlags] != "01" {
+		t.Fatalf("expected trace context in record, got %v", withSpan)
+	}
+
+	if withSpan["service"] != "test" {
+		t.Fatalf("expected attributes of wrapped handler to be kept, got %v", withSpan)
+	}
+
+	if _, ok := withoutSpan[logKeyTraceId]; ok {
+		t.Fatalf("expected no trace context in record without span, got %v", withoutSpan)
+	}
+}
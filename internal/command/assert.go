@@ -0,0 +1,104 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/slo"
+)
+
+// ErrSLOViolated is returned when benchmark results do not meet service level objectives.
+var ErrSLOViolated = errors.New("service level objectives violated")
+
+// Assert represents command that evaluates service level objectives against benchmark results
+type Assert struct {
+	output io.Writer
+}
+
+// NewAssert initializes assert command writing evaluation to output
+func NewAssert(output io.Writer) (*Assert, error) {
+	return &Assert{output: output}, nil
+}
+
+// Run evaluates SLOs of file at sloPath against results read from resultPaths, "-" stands for stdin.
+// With junitPath, JUnit XML report is written to that path as well.
+// Returned error wraps ErrSLOViolated if any SLO is not met, benchmarks that failed to run are skipped.
+func (command *Assert) Run(sloPath string, resultPaths []string, junitPath string) error {
+	sloFile, err := slo.Load(sloPath)
+	if err != nil {
+		return err
+	}
+
+	results := slo.NewResults()
+	for _, path := range resultPaths {
+		if err := parseResultsFile(results, path); err != nil {
+			return err
+		}
+	}
+
+	cases := slo.Evaluate(sloFile.SLOs, results.List())
+	failed := 0
+	for _, c := range cases {
+		status, details := "PASS", strings.Join(c.Measured, " ")
+		switch {
+		case c.Skipped != "":
+			status, details = "SKIP", c.Skipped
+		case len(c.Failures) > 0:
+			failed++
+			status, details = "FAIL", strings.Join(c.Failures, "; ")
+		}
+
+		if _, err := fmt.Fprintf(command.output, "%s %s [%s]: %s\n", status, c.Name(), c.SLO.Name(), details); err != nil {
+			return err
+		}
+	}
+
+	if junitPath != "" {
+		if err := writeJUnitFile(junitPath, cases); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d checks failed", ErrSLOViolated, failed, len(cases))
+	}
+
+	return nil
+}
+
+// parseResultsFile adds results read from file at path, or from stdin if path is "-".
+func parseResultsFile(results *slo.Results, path string) error {
+	if path == "-" {
+		return results.Parse(os.Stdin)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open benchmark results, err: %w", err)
+	}
+	defer file.Close()
+
+	if err := results.Parse(file); err != nil {
+		return fmt.Errorf("failed to parse benchmark results %s, err: %w", path, err)
+	}
+
+	return nil
+}
+
+// writeJUnitFile writes JUnit XML report of cases to file at path.
+func writeJUnitFile(path string, cases []slo.Case) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JUnit report, err: %w", err)
+	}
+
+	if err := slo.WriteJUnit(file, cases); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write JUnit report, err: %w", err)
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/audit"
+)
+
+// ErrAuditChainBroken is returned when audit log was tampered with.
+var ErrAuditChainBroken = errors.New("audit log verification failed")
+
+// AuditVerify represents command that verifies hash chain of audit log
+type AuditVerify struct {
+	output io.Writer
+}
+
+// NewAuditVerify initializes audit verify command writing verification summary to output
+func NewAuditVerify(output io.Writer) (*AuditVerify, error) {
+	return &AuditVerify{output: output}, nil
+}
+
+// Run verifies hash chain of audit log at path. The log must start the chain, unless it continues entry
+// with hash flagAnchor. With non-empty flagHead, the chain must contain entry with that hash.
+// Returned error wraps ErrAuditChainBroken if chain is broken or does not contain expected head.
+func (command *AuditVerify) Run(path string, flagAnchor string, flagHead string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log, err: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	result, err := audit.Verify(file, flagAnchor, flagHead)
+	if chainErr, ok := errors.AsType[*audit.ChainError](err); ok {
+		return fmt.Errorf("%w: %w", ErrAuditChainBroken, chainErr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(command.output, "OK %d entries (first %d), head %s\n", result.Entries, result.First, result.Head)
+	return err
+}
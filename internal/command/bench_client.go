@@ -0,0 +1,333 @@
+package command
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/correlation"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/status"
+)
+
+// BenchClientMatrix represents parameters swept by client-side benchmark, every combination is benchmarked separately.
+// Sizes and output formats apply to HashData, key types apply to SignCertificate.
+type BenchClientMatrix struct {
+	Operations    []string
+	Profiles      []string
+	Sizes         []int
+	OutputFormats []string
+	KeyTypes      []string
+	Concurrency   []int
+	// Requests is number of requests sent in every combination, they are distributed among concurrent workers.
+	Requests int
+}
+
+// BenchClientCell represents throughput and latency measured for single combination of benchmark parameters.
+type BenchClientCell struct {
+	Operation    string         `json:"operation"`
+	Profile      string         `json:"profile"`
+	Size         int            `json:"size,omitempty"`
+	OutputFormat string         `json:"output_format,omitempty"`
+	KeyType      string         `json:"key_type,omitempty"`
+	Concurrency  int            `json:"concurrency"`
+	Requests     int            `json:"requests"`
+	Errors       int            `json:"errors"`
+	Codes        map[string]int `json:"grpc_codes"`
+	Elapsed      time.Duration  `json:"elapsed_ns"`
+	Throughput   float64        `json:"throughput_rps"`
+	Bandwidth    float64        `json:"throughput_mbps,omitempty"`
+	LatencyMean  time.Duration  `json:"latency_mean_ns"`
+	LatencyP50   time.Duration  `json:"latency_p50_ns"`
+	LatencyP99   time.Duration  `json:"latency_p99_ns"`
+	LatencyMax   time.Duration  `json:"latency_max_ns"`
+}
+
+// BenchClient represents command that measures client-side throughput and latency of broker operations
+// across matrix of input sizes, profiles, output formats, key types and concurrency levels
+type BenchClient struct {
+	logger         *slog.Logger
+	libraries      *pool.Pool
+	tracerProvider *otel.TracerProvider
+}
+
+// NewBenchClient initializes client-side benchmark command
+func NewBenchClient(ctx context.Context, libraries *pool.Pool, logger *slog.Logger, tracerProvider *otel.TracerProvider) (*BenchClient, error) {
+	return &BenchClient{
+		logger:         logger,
+		libraries:      libraries,
+		tracerProvider: tracerProvider,
+	}, nil
+}
+
+// Run benchmarks every combination of matrix parameters and writes one row per combination to w in given output format.
+// CLI retry policy is not applied, but client library retries requests failing with UNAVAILABLE, RESOURCE_EXHAUSTED
+// or ABORTED with backoff, and measured latency includes those retries. Failed requests are counted by gRPC code.
+// Benchmarking stops after the current combination when SIGTERM is received.
+func (command *BenchClient) Run(ctx context.Context, w io.Writer, matrix BenchClientMatrix, flagFormat string) error {
+	defer func() { _ = command.gracefulShutdown() }()
+
+	reporter, err := newBenchClientReporter(w, flagFormat)
+	if err != nil {
+		return err
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(c)
+
+	if err := command.benchmark(ctx, matrix, c, reporter.Write); err != nil {
+		return err
+	}
+
+	return reporter.Flush()
+}
+
+// benchmark benchmarks every combination of matrix parameters and reports result of every combination.
+// Benchmarking stops after the current combination when signal is received from stop or ctx is done.
+func (command *BenchClient) benchmark(ctx context.Context, matrix BenchClientMatrix, stop <-chan os.Signal, report func(BenchClientCell) error) error {
+	cells := benchClientCells(matrix)
+	command.logger.InfoContext(ctx, "Running client-side benchmarks", "combinations", len(cells), "requests", matrix.Requests)
+
+	// Input of every size is generated once and dropped after the last combination using it
+	lastUse := make(map[int]int)
+	for i, cell := range cells {
+		if cell.Operation == otel.OperationHashData.Name {
+			lastUse[cell.Size] = i
+		}
+	}
+
+	inputs := make(map[int][]byte)
+	signingMaterials := make(map[string]*benchSigningMaterial)
+	for i, cell := range cells {
+		select {
+		case <-stop:
+			command.logger.InfoContext(ctx, "Received SIGTERM signal")
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		var send func(ctx context.Context) error
+		switch cell.Operation {
+		case otel.OperationHashData.Name:
+			if _, ok := inputs[cell.Size]; !ok {
+				inputs[cell.Size] = make([]byte, cell.Size)
+				_, _ = rand.Read(inputs[cell.Size])
+			}
+
+			send = command.hashRequest(cell, inputs[cell.Size])
+		case otel.OperationSignCertificate.Name:
+			if _, ok := signingMaterials[cell.KeyType]; !ok {
+				material, err := newBenchSigningMaterial(cell.KeyType)
+				if err != nil {
+					return err
+				}
+
+				signingMaterials[cell.KeyType] = material
+			}
+
+			send = command.signRequest(cell, signingMaterials[cell.KeyType])
+		}
+
+		command.logger.InfoContext(ctx, "Benchmarking combination", "index", i+1, "combinations", len(cells),
+			"operation", cell.Operation, "profile", cell.Profile, "size", cell.Size, "output_format", cell.OutputFormat,
+			"key_type", cell.KeyType, "concurrency", cell.Concurrency)
+
+		result := command.runCell(ctx, cell, matrix.Requests, send)
+		if cell.Operation == otel.OperationHashData.Name && lastUse[cell.Size] == i {
+			delete(inputs, cell.Size)
+		}
+
+		if err := report(result); err != nil {
+			return fmt.Errorf("failed to write benchmark results, err: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// benchClientCells expands matrix to list of combinations ordered by operation, profile and parameters,
+// with concurrency varying fastest, so that performance knees are easy to spot.
+func benchClientCells(matrix BenchClientMatrix) []BenchClientCell {
+	var cells []BenchClientCell
+	for _, operation := range matrix.Operations {
+		for _, profile := range matrix.Profiles {
+			switch operation {
+			case constant.BenchOperationHash:
+				for _, size := range matrix.Sizes {
+					for _, outputFormat := range matrix.OutputFormats {
+						for _, concurrency := range matrix.Concurrency {
+							cells = append(cells, BenchClientCell{Operation: otel.OperationHashData.Name, Profile: profile,
+								Size: size, OutputFormat: outputFormat, Concurrency: concurrency})
+						}
+					}
+				}
+			case constant.BenchOperationSign:
+				for _, keyType := range matrix.KeyTypes {
+					for _, concurrency := range matrix.Concurrency {
+						cells = append(cells, BenchClientCell{Operation: otel.OperationSignCertificate.Name, Profile: profile,
+							KeyType: keyType, Concurrency: concurrency})
+					}
+				}
+			}
+		}
+	}
+
+	return cells
+}
+
+// hashRequest returns function sending hash request of given input for cell.
+func (command *BenchClient) hashRequest(cell BenchClientCell, input []byte) func(ctx context.Context) error {
+	outputFormat := cryptobrokerclientgo.OutputFormatHex
+	if cell.OutputFormat == "raw" {
+		outputFormat = cryptobrokerclientgo.OutputFormatRaw
+	}
+
+	return func(ctx context.Context) error {
+		payload := cryptobrokerclientgo.HashDataPayload{
+			Input:        input,
+			Profile:      cell.Profile,
+			OutputFormat: outputFormat,
+			Metadata:     &cryptobrokerclientgo.Metadata{Id: uuid.New().String()},
+		}
+		setTraceContext(ctx, payload.Metadata, correlation.IDFromContext(ctx))
+
+		finishRequest := otel.StartRequest(ctx, otel.OperationHashData, cell.Profile, len(input))
+		response, err := pool.Bind(command.libraries, (*cryptobrokerclientgo.Library).HashData)(ctx, payload)
+		finishRequest(response, err)
+		return err
+	}
+}
+
+// signRequest returns function sending sign certificate request with given signing material for cell.
+func (command *BenchClient) signRequest(cell BenchClientCell, material *benchSigningMaterial) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		payload := cryptobrokerclientgo.SignCertificatePayload{
+			Profile:      cell.Profile,
+			CSR:          material.csr,
+			CACert:       material.caCert,
+			CAPrivateKey: material.caPrivateKey,
+			Metadata:     &cryptobrokerclientgo.Metadata{Id: uuid.New().String()},
+		}
+		setTraceContext(ctx, payload.Metadata, correlation.IDFromContext(ctx))
+
+		finishRequest := otel.StartRequest(ctx, otel.OperationSignCertificate, cell.Profile, len(payload.CSR)+len(payload.CACert))
+		response, err := pool.Bind(command.libraries, (*cryptobrokerclientgo.Library).SignCertificate)(ctx, payload)
+		finishRequest(response, err)
+		return err
+	}
+}
+
+// runCell sends requests through concurrent workers and measures throughput and latency percentiles.
+func (command *BenchClient) runCell(ctx context.Context, cell BenchClientCell, requests int, send func(ctx context.Context) error) BenchClientCell {
+	tracer := command.tracerProvider.GetTracer("crypto-broker-cli-go")
+	ctx, span := tracer.Start(ctx, "CLI.BenchClient",
+		trace.WithAttributes(
+			otel.AttributeRpcMethod.String(cell.Operation),
+			otel.AttributeCryptoProfile.String(cell.Profile),
+			otel.AttributeCryptoInputSize.Int(cell.Size),
+			otel.AttributeCorrelationId.String(correlation.IDFromContext(ctx)),
+			otel.AttributeCryptoHashOutputFormat.String(cell.OutputFormat),
+			otel.AttributeBenchKeyType.String(cell.KeyType),
+			otel.AttributeBenchConcurrency.Int(cell.Concurrency),
+			otel.AttributeBenchRequests.Int(requests),
+		))
+	defer span.End()
+
+	var mu sync.Mutex
+	latencies := make([]time.Duration, 0, requests)
+	cell.Codes = make(map[string]int)
+
+	jobs := make(chan struct{}, requests)
+	for range requests {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+
+	timestampStart := time.Now()
+	var wg sync.WaitGroup
+	for range cell.Concurrency {
+		wg.Go(func() {
+			for range jobs {
+				requestStart := time.Now()
+				err := send(ctx)
+				latency := time.Since(requestStart)
+
+				mu.Lock()
+				latencies = append(latencies, latency)
+				cell.Codes[status.Code(err).String()]++
+				if err != nil {
+					cell.Errors++
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	cell.Elapsed = time.Since(timestampStart)
+	cell.Requests = len(latencies)
+	if cell.Elapsed > 0 {
+		cell.Throughput = float64(cell.Requests) / cell.Elapsed.Seconds()
+		cell.Bandwidth = float64(cell.Requests*cell.Size) / cell.Elapsed.Seconds() / 1e6
+	}
+
+	if len(latencies) > 0 {
+		var total time.Duration
+		for _, latency := range latencies {
+			total += latency
+		}
+
+		cell.LatencyMean = total / time.Duration(len(latencies))
+		slices.Sort(latencies)
+		cell.LatencyP50 = latencies[nearestRank(len(latencies), 50)]
+		cell.LatencyP99 = latencies[nearestRank(len(latencies), 99)]
+		cell.LatencyMax = latencies[len(latencies)-1]
+	}
+
+	span.SetAttributes(
+		otel.AttributeBenchErrors.Int(cell.Errors),
+		otel.AttributeBenchThroughput.Float64(cell.Throughput),
+		otel.AttributeBenchLatencyP99.Int64(cell.LatencyP99.Microseconds()),
+	)
+	// pool metrics of the last request are replaced by pool metrics after the combination
+	span.SetAttributes(command.libraries.Stats().Attributes()...)
+
+	if cell.Errors > 0 {
+		span.SetStatus(codes.Error, fmt.Sprintf("%d of %d requests failed", cell.Errors, cell.Requests))
+	} else {
+		span.SetStatus(codes.Ok, "Benchmark combination completed successfully")
+	}
+
+	return cell
+}
+
+// nearestRank returns index of nearest-rank percentile p in sorted samples of size n > 0.
+func nearestRank(n int, p int) int {
+	rank := (p*n + 99) / 100
+	return min(max(rank, 1), n) - 1
+}
+
+// gracefulShutdown closes library connections once requests in flight complete.
+func (command *BenchClient) gracefulShutdown() error {
+	command.logger.Info("Closing crypto broker library connections")
+	ctx, cancel := context.WithTimeout(context.Background(), constant.PoolDrainTimeout)
+	defer cancel()
+	return command.libraries.Shutdown(ctx)
+}
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+)
+
+// benchSigningMaterial represents CSR and CA certificate with private key used by sign certificate benchmarks
+type benchSigningMaterial struct {
+	csr          []byte
+	caCert       []byte
+	caPrivateKey []byte
+}
+
+// newBenchSigningMaterial generates self-signed CA and CSR, both with key of given type, encoded as PEM.
+// EC keys are encoded as SEC 1, RSA keys as PKCS #1 and Ed25519 keys as PKCS #8.
+func newBenchSigningMaterial(keyType string) (*benchSigningMaterial, error) {
+	caKey, err := generateBenchKey(keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	csrKey, err := generateBenchKey(keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"Crypto Broker CLI"}, CommonName: "Benchmark CA " + keyType},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	caCert, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s benchmark CA certificate, err: %w", keyType, err)
+	}
+
+	csrTemplate := &x509.CertificateRequest{
+		Subject: pkix.Name{Organization: []string{"Crypto Broker CLI"}, CommonName: "benchmark-" + keyType},
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, csrTemplate, csrKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s benchmark CSR, err: %w", keyType, err)
+	}
+
+	caPrivateKey, err := encodeBenchKey(caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &benchSigningMaterial{
+		csr:          pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}),
+		caCert:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert}),
+		caPrivateKey: caPrivateKey,
+	}, nil
+}
+
+// generateBenchKey generates private key of given type.
+func generateBenchKey(keyType string) (crypto.Signer, error) {
+	switch keyType {
+	case constant.KeyTypeP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case constant.KeyTypeP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case constant.KeyTypeP521:
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case constant.KeyTypeRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case constant.KeyTypeRSA3072:
+		return rsa.GenerateKey(rand.Reader, 3072)
+	case constant.KeyTypeRSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case constant.KeyTypeEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	}
+
+	return nil, fmt.Errorf("unsupported key type: %s", keyType)
+}
+
+// encodeBenchKey encodes private key as PEM.
+func encodeBenchKey(key crypto.Signer) ([]byte, error) {
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return nil, err
+		}
+
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}), nil
+	default:
+		der, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			return nil, err
+		}
+
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+	}
+}
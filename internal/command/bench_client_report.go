@@ -0,0 +1,156 @@
+package command
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+)
+
+// benchClientReporter writes client-side benchmark cells to w in one of supported benchmark output formats.
+// Table is written at once on Flush, so that columns of all combinations are aligned.
+type benchClientReporter struct {
+	w             io.Writer
+	format        string
+	table         *tabwriter.Writer
+	headerWritten bool
+}
+
+// newBenchClientReporter initializes reporter writing cells to w in given format.
+func newBenchClientReporter(w io.Writer, format string) (*benchClientReporter, error) {
+	switch format {
+	case constant.BenchmarkFormatTable, constant.BenchmarkFormatJSON, constant.BenchmarkFormatCSV, constant.BenchmarkFormatGoBench:
+		return &benchClientReporter{w: w, format: format, table: tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported benchmark output format: %s", format)
+}
+
+// Write writes single benchmark cell.
+func (reporter *benchClientReporter) Write(cell BenchClientCell) error {
+	switch reporter.format {
+	case constant.BenchmarkFormatJSON:
+		return json.NewEncoder(reporter.w).Encode(cell)
+	case constant.BenchmarkFormatCSV:
+		return reporter.writeCSV(cell)
+	case constant.BenchmarkFormatGoBench:
+		return reporter.writeGoBench(cell)
+	default:
+		return reporter.writeTable(cell)
+	}
+}
+
+// Flush writes buffered table rows.
+func (reporter *benchClientReporter) Flush() error {
+	if reporter.format != constant.BenchmarkFormatTable || !reporter.headerWritten {
+		return nil
+	}
+
+	return reporter.table.Flush()
+}
+
+// writeTable buffers cell as table row, header is buffered once before the first row.
+func (reporter *benchClientReporter) writeTable(cell BenchClientCell) error {
+	if !reporter.headerWritten {
+		if _, err := fmt.Fprintln(reporter.table, "OPERATION\tPROFILE\tSIZE\tFORMAT\tKEY\tCONCURRENCY\tREQUESTS\tERRORS\tREQ/S\tMB/S\tP50 (µs)\tP99 (µs)\tMAX (µs)"); err != nil {
+			return err
+		}
+		reporter.headerWritten = true
+	}
+
+	size, bandwidth := "-", "-"
+	if cell.Operation == otel.OperationHashData.Name {
+		size, bandwidth = formatBenchSize(cell.Size), fmt.Sprintf("%.2f", cell.Bandwidth)
+	}
+
+	_, err := fmt.Fprintf(reporter.table, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%.2f\t%s\t%d\t%d\t%d\n",
+		cell.Operation, cell.Profile, size, orDash(cell.OutputFormat), orDash(cell.KeyType), cell.Concurrency,
+		cell.Requests, cell.Errors, cell.Throughput, bandwidth,
+		cell.LatencyP50.Microseconds(), cell.LatencyP99.Microseconds(), cell.LatencyMax.Microseconds())
+	return err
+}
+
+// writeCSV writes cell as CSV row, header is written once before the first row.
+func (reporter *benchClientReporter) writeCSV(cell BenchClientCell) error {
+	cw := csv.NewWriter(reporter.w)
+	if !reporter.headerWritten {
+		if err := cw.Write([]string{"operation", "profile", "size", "output_format", "key_type", "concurrency", "requests", "errors",
+			"elapsed_ns", "throughput_rps", "throughput_mbps", "latency_p50_ns", "latency_p99_ns", "latency_max_ns"}); err != nil {
+			return err
+		}
+		reporter.headerWritten = true
+	}
+
+	if err := cw.Write([]string{
+		cell.Operation, cell.Profile, strconv.Itoa(cell.Size), cell.OutputFormat, cell.KeyType,
+		strconv.Itoa(cell.Concurrency), strconv.Itoa(cell.Requests), strconv.Itoa(cell.Errors),
+		strconv.FormatInt(cell.Elapsed.Nanoseconds(), 10),
+		strconv.FormatFloat(cell.Throughput, 'f', 2, 64), strconv.FormatFloat(cell.Bandwidth, 'f', 2, 64),
+		strconv.FormatInt(cell.LatencyP50.Nanoseconds(), 10), strconv.FormatInt(cell.LatencyP99.Nanoseconds(), 10),
+		strconv.FormatInt(cell.LatencyMax.Nanoseconds(), 10),
+	}); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeGoBench writes cell in Go benchmark format accepted by benchstat and the assert command.
+// ns/op is mean latency, as in Go benchmarks, so that latency SLOs apply to client-side results as well.
+func (reporter *benchClientReporter) writeGoBench(cell BenchClientCell) error {
+	line := fmt.Sprintf("%s\t%d\t%d ns/op\t%.2f requests/s", benchClientName(cell), cell.Requests,
+		cell.LatencyMean.Nanoseconds(), cell.Throughput)
+	if cell.Operation == otel.OperationHashData.Name {
+		line += fmt.Sprintf("\t%.2f MB/s", cell.Bandwidth)
+	}
+
+	for _, code := range slices.Sorted(maps.Keys(cell.Codes)) {
+		line += fmt.Sprintf("\t%d grpc_%s", cell.Codes[code], code)
+	}
+
+	_, err := fmt.Fprintln(reporter.w, line)
+	return err
+}
+
+// benchClientName returns Go benchmark name of cell, e.g. BenchmarkHashData_profile_Default_size_1KiB_format_hex_concurrency_8.
+func benchClientName(cell BenchClientCell) string {
+	name := "Benchmark" + cell.Operation + "_profile_" + cell.Profile
+	if cell.Operation == otel.OperationHashData.Name {
+		name += "_size_" + formatBenchSize(cell.Size) + "_format_" + cell.OutputFormat
+	} else {
+		name += "_key_" + cell.KeyType
+	}
+
+	return name + "_concurrency_" + strconv.Itoa(cell.Concurrency)
+}
+
+// formatBenchSize formats size in bytes with the largest binary unit dividing it, e.g. 1KiB or 1536B.
+func formatBenchSize(size int) string {
+	for _, unit := range []struct {
+		suffix string
+		bytes  int
+	}{{"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}} {
+		if size >= unit.bytes && size%unit.bytes == 0 {
+			return strconv.Itoa(size/unit.bytes) + unit.suffix
+		}
+	}
+
+	return strconv.Itoa(size) + "B"
+}
+
+// orDash returns value or dash if value is empty.
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -0,0 +1,115 @@
+package command
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/slo"
+)
+
+func TestBenchClientCells(t *testing.T) {
+	t.Parallel()
+
+	cells := benchClientCells(BenchClientMatrix{
+		Operations:    []string{constant.BenchOperationHash, constant.BenchOperationSign},
+		Profiles:      []string{"Default"},
+		Sizes:         []int{16, 1 << 20},
+		OutputFormats: []string{"hex"},
+		KeyTypes:      []string{constant.KeyTypeP256},
+		Concurrency:   []int{1, 8},
+	})
+
+	var names []string
+	for _, cell := range cells {
+		names = append(names, benchClientName(cell))
+	}
+
+	expected := []string{
+		"BenchmarkHashData_profile_Default_size_16B_format_hex_concurrency_1",
+		"BenchmarkHashData_profile_Default_size_16B_format_hex_concurrency_8",
+		"BenchmarkHashData_profile_Default_size_1MiB_format_hex_concurrency_1",
+		"BenchmarkHashData_profile_Default_size_1MiB_format_hex_concurrency_8",
+		"BenchmarkSignCertificate_profile_Default_key_P256_concurrency_1",
+		"BenchmarkSignCertificate_profile_Default_key_P256_concurrency_8",
+	}
+	if strings.Join(names, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected cells:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(names, "\n"))
+	}
+}
+
+func TestBenchClientReporterGoBench(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+	reporter, err := newBenchClientReporter(&output, constant.BenchmarkFormatGoBench)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	cell := BenchClientCell{
+		Operation: "HashData", Profile: "Default", Size: 1024, OutputFormat: "hex", Concurrency: 8,
+		Requests: 100, Errors: 2, Codes: map[string]int{"OK": 98, "Unavailable": 2},
+		Throughput: 500, Bandwidth: 0.51, LatencyMean: 3 * time.Millisecond,
+	}
+	if err := reporter.Write(cell); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := "BenchmarkHashData_profile_Default_size_1KiB_format_hex_concurrency_8\t100\t3000000 ns/op\t500.00 requests/s\t0.51 MB/s\t98 grpc_OK\t2 grpc_Unavailable\n"
+	if output.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, output.String())
+	}
+
+	results := slo.NewResults()
+	if err := results.Parse(&output); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	parsed := results.List()
+	if len(parsed) != 1 || parsed[0].Operation != "HashData" || parsed[0].Profile != "Default" || parsed[0].Errors != 2 {
+		t.Fatalf("expected results to be parsed by assert, got %+v", parsed)
+	}
+}
+
+func TestNewBenchSigningMaterial(t *testing.T) {
+	t.Parallel()
+
+	for _, keyType := range []string{constant.KeyTypeP256, constant.KeyTypeRSA2048, constant.KeyTypeEd25519} {
+		material, err := newBenchSigningMaterial(keyType)
+		if err != nil {
+			t.Fatalf("expected nil error for %s, got %v", keyType, err)
+		}
+
+		block, _ := pem.Decode(material.csr)
+		if block == nil {
+			t.Fatalf("expected PEM encoded CSR for %s", keyType)
+		}
+
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil || csr.CheckSignature() != nil {
+			t.Fatalf("expected valid CSR for %s, got %v", keyType, err)
+		}
+
+		block, _ = pem.Decode(material.caCert)
+		if block == nil {
+			t.Fatalf("expected PEM encoded CA certificate for %s", keyType)
+		}
+
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			t.Fatalf("expected valid CA certificate for %s, got %v", keyType, err)
+		}
+
+		if block, _ := pem.Decode(material.caPrivateKey); block == nil {
+			t.Fatalf("expected PEM encoded CA private key for %s", keyType)
+		}
+	}
+
+	if _, err := newBenchSigningMaterial("DSA1024"); err == nil {
+		t.Fatal("expected error for unsupported key type, got nil")
+	}
+}
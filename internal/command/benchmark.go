@@ -2,8 +2,8 @@ package command
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -12,7 +12,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/correlation"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
 	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -23,22 +25,35 @@ type Benchmark struct {
 	logger              *slog.Logger
 	cryptoBrokerLibrary *cryptobrokerclientgo.Library
 	tracerProvider      *otel.TracerProvider
+	retryPolicy         retry.Policy
+	version             VersionPayload
+	runs                []BenchmarkRun
 }
 
-// NewBenchmark initializes benchmark command
-func NewBenchmark(ctx context.Context, lib *cryptobrokerclientgo.Library, logger *slog.Logger, tracerProvider *otel.TracerProvider) (*Benchmark, error) {
+// NewBenchmark initializes benchmark command, version is saved together with results
+func NewBenchmark(ctx context.Context, lib *cryptobrokerclientgo.Library, logger *slog.Logger, tracerProvider *otel.TracerProvider, retryPolicy retry.Policy, version VersionPayload) (*Benchmark, error) {
 	return &Benchmark{
 		logger:              logger,
 		cryptoBrokerLibrary: lib,
 		tracerProvider:      tracerProvider,
+		retryPolicy:         retryPolicy,
+		version:             version,
 	}, nil
 }
 
 // Run executes command logic.
-func (command *Benchmark) Run(ctx context.Context, flagLoop int) error {
+// Results of every run are written to w in given output format, see constant.BenchmarkFormatTable and others.
+// Without loop, benchmark is run flagCount times, so that Go benchmark format output contains enough samples for benchstat.
+// With flagSave, results of all runs are saved under that name to BenchmarkDir once benchmarking finishes.
+func (command *Benchmark) Run(ctx context.Context, w io.Writer, flagLoop int, flagCount int, flagFormat string, flagSave string) error {
 	defer func() { _ = command.gracefulShutdown() }()
 
-	command.logger.Info("Running server-side benchmarks")
+	reporter, err := newBenchmarkReporter(w, flagFormat)
+	if err != nil {
+		return err
+	}
+
+	command.logger.InfoContext(ctx, "Running server-side benchmarks")
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
@@ -52,10 +67,10 @@ func (command *Benchmark) Run(ctx context.Context, flagLoop int) error {
 		for {
 			select {
 			case <-c:
-				command.logger.Info("Received SIGTERM signal")
-				return nil
+				command.logger.InfoContext(ctx, "Received SIGTERM signal")
+				return command.save(ctx, flagSave)
 			default:
-				if err := command.runBenchmark(ctx); err != nil {
+				if err := command.runBenchmark(ctx, reporter); err != nil {
 					return err
 				}
 
@@ -63,38 +78,70 @@ func (command *Benchmark) Run(ctx context.Context, flagLoop int) error {
 			}
 		}
 	} else {
-		if err := command.runBenchmark(ctx); err != nil {
-			return err
+		for range max(flagCount, 1) {
+			select {
+			case <-c:
+				command.logger.InfoContext(ctx, "Received SIGTERM signal")
+				return command.save(ctx, flagSave)
+			default:
+				if err := command.runBenchmark(ctx, reporter); err != nil {
+					return err
+				}
+			}
 		}
+		return command.save(ctx, flagSave)
+	}
+}
+
+// save saves results of all runs under name together with metadata of the environment, empty name disables saving.
+// Broker health is checked at the time of saving, so that results collected against a degraded broker can be recognized.
+func (command *Benchmark) save(ctx context.Context, name string) error {
+	if name == "" {
 		return nil
 	}
+
+	record := BenchmarkRecord{
+		Name:         name,
+		Timestamp:    time.Now().UTC(),
+		Version:      command.version,
+		BrokerHealth: command.cryptoBrokerLibrary.HealthData(ctx).Status,
+		Host:         currentBenchmarkHost(),
+		Runs:         command.runs,
+	}
+
+	path, err := SaveBenchmarkRecord(BenchmarkDir(), record)
+	if err != nil {
+		return err
+	}
+
+	command.logger.InfoContext(ctx, "Benchmark results saved", "name", name, "path", path, "runs", len(record.Runs))
+	return nil
 }
 
 // runBenchmark sends benchmark request through crypto broker library.
-// In case of success it displays response and returns nil error, otherwise it returns non-nil error.
+// In case of success it writes results through reporter and returns nil error, otherwise it returns non-nil error.
 // Internally method measures execution time and prints it through logger.
-func (command *Benchmark) runBenchmark(ctx context.Context) error {
+func (command *Benchmark) runBenchmark(ctx context.Context, reporter *benchmarkReporter) error {
 	tracer := command.tracerProvider.GetTracer("crypto-broker-cli-go")
+	correlationId := correlation.IDFromContext(ctx)
 	ctx, span := tracer.Start(ctx, "CLI.Benchmark",
-		trace.WithAttributes(otel.AttributeRpcMethod.String("Benchmark")))
+		trace.WithAttributes(
+			otel.AttributeRpcMethod.String("Benchmark"),
+			otel.AttributeCorrelationId.String(correlationId),
+		))
 	defer span.End()
 
-	// Inject trace context into payload metadata
-	spanContext := span.SpanContext()
+	// Metadata id is shared by all attempts, trace context is injected per attempt
 	payload := cryptobrokerclientgo.BenchmarkDataPayload{
 		Metadata: &cryptobrokerclientgo.Metadata{
 			Id: uuid.New().String(),
-			TraceContext: &cryptobrokerclientgo.TraceContext{
-				TraceId:    spanContext.TraceID().String(),
-				SpanId:     spanContext.SpanID().String(),
-				TraceFlags: spanContext.TraceFlags().String(),
-				TraceState: spanContext.TraceState().String(),
-			},
 		},
 	}
 
 	timestampStart := time.Now()
-	responseBody, err := command.cryptoBrokerLibrary.BenchmarkData(ctx, payload)
+	responseBody, err := retry.Do(ctx, command.retryPolicy, tracer, "CLI.Benchmark.Attempt", payload,
+		func(ctx context.Context) { setTraceContext(ctx, payload.Metadata, correlationId) },
+		otel.MeasureAttempts(otel.OperationBenchmark, "", 0, command.cryptoBrokerLibrary.BenchmarkData))
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -103,20 +150,19 @@ func (command *Benchmark) runBenchmark(ctx context.Context) error {
 
 	timestampFinish := time.Now()
 	durationElapsed := timestampFinish.Sub(timestampStart)
-	marshalledResp, err := json.MarshalIndent(responseBody, " ", "  ")
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return err
-	}
-
-	span.SetAttributes(otel.AttributeCryptoBenchmarkResultsSize.Int(len(marshalledResp)))
+	run := newBenchmarkRun(len(command.runs)+1, durationElapsed, responseBody)
+	command.runs = append(command.runs, run)
 	span.SetStatus(codes.Ok, "Benchmark operation completed successfully")
 
-	command.logger.Info("Benchmark results", "results", responseBody)
-	command.logger.Info(
+	command.logger.InfoContext(ctx, "Benchmark results received", "run", run.Run, "benchmarks", len(run.Results))
+	command.logger.InfoContext(ctx,
 		fmt.Sprintf("Server-side Benchmarking took %d µs", durationElapsed.Microseconds()),
 	)
+
+	if err := reporter.Write(run); err != nil {
+		return fmt.Errorf("failed to write benchmark results, err: %w", err)
+	}
+
 	return nil
 }
 
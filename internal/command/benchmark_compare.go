@@ -0,0 +1,254 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// ErrBenchmarkRegression is returned when compared benchmark results contain significant regression above threshold.
+var ErrBenchmarkRegression = errors.New("benchmark regression detected")
+
+// BenchmarkCompare represents command that compares two saved benchmark results
+type BenchmarkCompare struct {
+	output io.Writer
+}
+
+// NewBenchmarkCompare initializes benchmark compare command writing comparison to output
+func NewBenchmarkCompare(output io.Writer) (*BenchmarkCompare, error) {
+	return &BenchmarkCompare{output: output}, nil
+}
+
+// Run compares results saved as baseName and newer results saved as newName in dir and writes per benchmark deltas.
+// Delta is significant if Mann-Whitney U test p-value is below flagAlpha, in the same manner as benchstat.
+// Returned error wraps ErrBenchmarkRegression if any significant delta exceeds flagThreshold percent.
+func (command *BenchmarkCompare) Run(dir string, baseName string, newName string, flagThreshold float64, flagAlpha float64) error {
+	baseRecord, err := LoadBenchmarkRecord(dir, baseName)
+	if err != nil {
+		return err
+	}
+
+	newRecord, err := LoadBenchmarkRecord(dir, newName)
+	if err != nil {
+		return err
+	}
+
+	if err := writeBenchmarkRecordHeader(command.output, "base", baseRecord); err != nil {
+		return err
+	}
+
+	if err := writeBenchmarkRecordHeader(command.output, "new", newRecord); err != nil {
+		return err
+	}
+
+	deltas := compareBenchmarkRecords(baseRecord, newRecord, flagThreshold, flagAlpha)
+	if err := writeBenchmarkDeltas(command.output, deltas); err != nil {
+		return err
+	}
+
+	var regressions []string
+	for _, delta := range deltas {
+		if delta.Regression {
+			regressions = append(regressions, fmt.Sprintf("%s %+.2f%%", delta.Name, delta.Delta))
+		}
+	}
+
+	if len(regressions) > 0 {
+		return fmt.Errorf("%w: %d benchmarks exceed %.2f%% threshold: %v", ErrBenchmarkRegression, len(regressions), flagThreshold, regressions)
+	}
+
+	return nil
+}
+
+// benchmarkDelta represents comparison of samples of single benchmark present in base or new results.
+type benchmarkDelta struct {
+	Name        string
+	Base        []float64
+	New         []float64
+	BaseMedian  float64
+	NewMedian   float64
+	Delta       float64
+	PValue      float64
+	Significant bool
+	Regression  bool
+}
+
+// compareBenchmarkRecords compares samples of every benchmark, benchmarks are ordered by name.
+// Each run of the record is one sample of average time of every benchmark.
+func compareBenchmarkRecords(base *BenchmarkRecord, newer *BenchmarkRecord, threshold float64, alpha float64) []benchmarkDelta {
+	baseSamples := benchmarkSamples(base)
+	newSamples := benchmarkSamples(newer)
+
+	var names []string
+	for name := range baseSamples {
+		names = append(names, name)
+	}
+
+	for name := range newSamples {
+		if _, ok := baseSamples[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	deltas := make([]benchmarkDelta, 0, len(names))
+	for _, name := range names {
+		delta := benchmarkDelta{Name: name, Base: baseSamples[name], New: newSamples[name], PValue: 1}
+		if len(delta.Base) == 0 || len(delta.New) == 0 {
+			deltas = append(deltas, delta)
+			continue
+		}
+
+		delta.BaseMedian = median(delta.Base)
+		delta.NewMedian = median(delta.New)
+		if delta.BaseMedian > 0 {
+			delta.Delta = (delta.NewMedian - delta.BaseMedian) / delta.BaseMedian * 100
+		}
+
+		delta.PValue = mannWhitneyUTest(delta.Base, delta.New)
+		delta.Significant = delta.PValue < alpha
+		delta.Regression = delta.Significant && delta.Delta > threshold
+		deltas = append(deltas, delta)
+	}
+
+	return deltas
+}
+
+// benchmarkSamples groups average times of record runs by benchmark name.
+func benchmarkSamples(record *BenchmarkRecord) map[string][]float64 {
+	samples := make(map[string][]float64)
+	for _, run := range record.Runs {
+		for _, result := range run.Results {
+			samples[result.Name] = append(samples[result.Name], float64(result.AvgTime))
+		}
+	}
+
+	return samples
+}
+
+// median returns median of non-empty samples.
+func median(samples []float64) float64 {
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	return sorted[middle]
+}
+
+// mannWhitneyUTest returns two-sided p-value of Mann-Whitney U test of samples x and y,
+// computed with normal approximation corrected for ties and continuity.
+// Samples without any variance, e.g. of single run each, result in p-value 1.
+func mannWhitneyUTest(x []float64, y []float64) float64 {
+	type sample struct {
+		value float64
+		fromX bool
+	}
+
+	all := make([]sample, 0, len(x)+len(y))
+	for _, value := range x {
+		all = append(all, sample{value: value, fromX: true})
+	}
+
+	for _, value := range y {
+		all = append(all, sample{value: value})
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].value < all[j].value
+	})
+
+	// Tied values get the average of ranks they span
+	n := float64(len(all))
+	rankSumX, tieCorrection := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromX {
+				rankSumX += rank
+			}
+		}
+
+		ties := float64(j - i)
+		tieCorrection += ties*ties*ties - ties
+		i = j
+	}
+
+	n1, n2 := float64(len(x)), float64(len(y))
+	u := rankSumX - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z <= 0 {
+		return 1
+	}
+
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// writeBenchmarkRecordHeader writes line describing saved results.
+func writeBenchmarkRecordHeader(w io.Writer, label string, record *BenchmarkRecord) error {
+	_, err := fmt.Fprintf(w, "%s: %s (%s, cli %s, client %s, broker %s, %s %s/%s, %d runs)\n",
+		label, record.Name, record.Timestamp.Format(time.RFC3339), record.Version.CLI.Version, record.Version.Client.Version,
+		record.BrokerHealth, record.Host.Hostname, record.Host.OS, record.Host.Arch, len(record.Runs))
+	return err
+}
+
+// writeBenchmarkDeltas writes deltas as aligned table, insignificant deltas are displayed as "~" like by benchstat.
+func writeBenchmarkDeltas(w io.Writer, deltas []benchmarkDelta) error {
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "BENCHMARK\tBASE (ns)\tNEW (ns)\tDELTA\tP\tN"); err != nil {
+		return err
+	}
+
+	for _, delta := range deltas {
+		baseMedian, newMedian, change, pValue := "-", "-", "-", "-"
+		if len(delta.Base) > 0 {
+			baseMedian = strconv.FormatFloat(delta.BaseMedian, 'f', 0, 64)
+		}
+
+		if len(delta.New) > 0 {
+			newMedian = strconv.FormatFloat(delta.NewMedian, 'f', 0, 64)
+		}
+
+		if len(delta.Base) > 0 && len(delta.New) > 0 {
+			pValue = strconv.FormatFloat(delta.PValue, 'f', 3, 64)
+			change = "~"
+			if delta.Significant {
+				change = fmt.Sprintf("%+.2f%%", delta.Delta)
+			}
+
+			if delta.Regression {
+				change += " REGRESSION"
+			}
+		}
+
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d+%d\n",
+			delta.Name, baseMedian, newMedian, change, pValue, len(delta.Base), len(delta.New)); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,86 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMannWhitneyUTest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		x      []float64
+		y      []float64
+		pValue float64
+	}{
+		{name: "separated", x: []float64{1, 2, 3, 4, 5}, y: []float64{6, 7, 8, 9, 10}, pValue: 0.0122},
+		{name: "interleaved", x: []float64{1, 3, 5, 7, 9}, y: []float64{2, 4, 6, 8, 10}, pValue: 0.6761},
+		{name: "identical", x: []float64{5, 5, 5}, y: []float64{5, 5, 5}, pValue: 1},
+		{name: "single_samples", x: []float64{1}, y: []float64{2}, pValue: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if pValue := mannWhitneyUTest(tt.x, tt.y); math.Abs(pValue-tt.pValue) > 0.0001 {
+				t.Fatalf("expected p-value %.4f, got %.4f", tt.pValue, pValue)
+			}
+		})
+	}
+}
+
+func TestBenchmarkCompare(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	saveRecord := func(name string, sha512 []int64, rsa []int64) {
+		record := BenchmarkRecord{Name: name, Timestamp: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), BrokerHealth: "SERVING"}
+		for i := range sha512 {
+			record.Runs = append(record.Runs, BenchmarkRun{Run: i + 1, Results: []BenchmarkRunResult{
+				{Name: "SHA-512", AvgTime: sha512[i]},
+				{Name: "RSA-4096", AvgTime: rsa[i]},
+			}})
+		}
+
+		if _, err := SaveBenchmarkRecord(dir, record); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+
+	saveRecord("base", []int64{100, 101, 102, 103, 104}, []int64{1000, 1010, 1020, 1030, 1040})
+	saveRecord("new", []int64{99, 102, 100, 104, 103}, []int64{1500, 1510, 1520, 1530, 1540})
+
+	var output bytes.Buffer
+	compareCommand, err := NewBenchmarkCompare(&output)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	err = compareCommand.Run(dir, "base", filepath.Join(dir, "new.json"), 10, 0.05)
+	if !errors.Is(err, ErrBenchmarkRegression) {
+		t.Fatalf("expected regression error, got %v", err)
+	}
+
+	for _, expected := range []string{
+		"RSA-4096   1020       1520      +49.02% REGRESSION  0.012  5+5",
+		"SHA-512    102        102       ~                   0.833  5+5",
+	} {
+		if !strings.Contains(output.String(), expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, output.String())
+		}
+	}
+
+	if err := compareCommand.Run(dir, "base", "new", 60, 0.05); err != nil {
+		t.Fatalf("expected nil error below threshold, got %v", err)
+	}
+
+	if err := compareCommand.Run(dir, "base", "missing", 10, 0.05); err == nil {
+		t.Fatal("expected error for missing results, got nil")
+	}
+}
@@ -0,0 +1,104 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+)
+
+// defaultBenchmarkDir is directory of saved benchmark results used when BENCHMARK_DIR is not set
+const defaultBenchmarkDir = ".benchmarks"
+
+// benchmarkRecordExtension is file extension of saved benchmark results
+const benchmarkRecordExtension = ".json"
+
+// BenchmarkRecord represents benchmark results saved together with metadata of environment they were collected in.
+type BenchmarkRecord struct {
+	Name         string         `json:"name"`
+	Timestamp    time.Time      `json:"timestamp"`
+	Version      VersionPayload `json:"version"`
+	BrokerHealth string         `json:"broker_health"`
+	Host         BenchmarkHost  `json:"host"`
+	Runs         []BenchmarkRun `json:"runs"`
+}
+
+// BenchmarkHost represents host benchmark results were collected on.
+type BenchmarkHost struct {
+	Hostname  string `json:"hostname"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	NumCPU    int    `json:"num_cpu"`
+	GoVersion string `json:"go_version"`
+}
+
+// currentBenchmarkHost returns description of host the CLI runs on.
+func currentBenchmarkHost() BenchmarkHost {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return BenchmarkHost{
+		Hostname:  hostname,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		GoVersion: runtime.Version(),
+	}
+}
+
+// BenchmarkDir returns directory of saved benchmark results.
+func BenchmarkDir() string {
+	if dir := os.Getenv(env.BENCHMARK_DIR); dir != "" {
+		return dir
+	}
+
+	return defaultBenchmarkDir
+}
+
+// SaveBenchmarkRecord writes record to dir as <name>.json, replacing previously saved results of the same name.
+// It returns path of written file.
+func SaveBenchmarkRecord(dir string, record BenchmarkRecord) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create benchmark results directory, err: %w", err)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal benchmark results, err: %w", err)
+	}
+
+	path := filepath.Join(dir, record.Name+benchmarkRecordExtension)
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write benchmark results, err: %w", err)
+	}
+
+	return path, nil
+}
+
+// LoadBenchmarkRecord reads benchmark results saved under name in dir.
+// Path of results file can be given instead of name, e.g. to compare results saved on another host.
+func LoadBenchmarkRecord(dir string, nameOrPath string) (*BenchmarkRecord, error) {
+	path := nameOrPath
+	if !strings.ContainsRune(nameOrPath, filepath.Separator) && !strings.HasSuffix(nameOrPath, benchmarkRecordExtension) {
+		path = filepath.Join(dir, nameOrPath+benchmarkRecordExtension)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmark results %s, err: %w", nameOrPath, err)
+	}
+
+	var record BenchmarkRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode benchmark results %s, err: %w", nameOrPath, err)
+	}
+
+	return &record, nil
+}
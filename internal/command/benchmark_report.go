@@ -0,0 +1,151 @@
+package command
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+)
+
+// BenchmarkRun represents results of single server-side benchmark run.
+type BenchmarkRun struct {
+	Run      int                  `json:"run"`
+	Duration time.Duration        `json:"duration_ns"`
+	Results  []BenchmarkRunResult `json:"results"`
+}
+
+// BenchmarkRunResult represents average time of single server-side benchmark.
+type BenchmarkRunResult struct {
+	Name    string `json:"name"`
+	AvgTime int64  `json:"avg_time_ns"`
+}
+
+// newBenchmarkRun converts benchmark response of given run to report representation.
+func newBenchmarkRun(run int, duration time.Duration, response *cryptobrokerclientgo.BenchmarkResults) BenchmarkRun {
+	benchmarkRun := BenchmarkRun{Run: run, Duration: duration, Results: []BenchmarkRunResult{}}
+	if response == nil {
+		return benchmarkRun
+	}
+
+	for _, result := range response.Results {
+		benchmarkRun.Results = append(benchmarkRun.Results, BenchmarkRunResult{Name: result.Name, AvgTime: result.AvgTime})
+	}
+
+	return benchmarkRun
+}
+
+// benchmarkReporter writes benchmark runs to w in one of supported benchmark output formats.
+type benchmarkReporter struct {
+	w             io.Writer
+	format        string
+	headerWritten bool
+}
+
+// newBenchmarkReporter initializes reporter writing runs to w in given format.
+func newBenchmarkReporter(w io.Writer, format string) (*benchmarkReporter, error) {
+	switch format {
+	case constant.BenchmarkFormatTable, constant.BenchmarkFormatJSON, constant.BenchmarkFormatCSV, constant.BenchmarkFormatGoBench:
+		return &benchmarkReporter{w: w, format: format}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported benchmark output format: %s", format)
+}
+
+// Write writes single benchmark run.
+func (reporter *benchmarkReporter) Write(run BenchmarkRun) error {
+	switch reporter.format {
+	case constant.BenchmarkFormatJSON:
+		return reporter.writeJSON(run)
+	case constant.BenchmarkFormatCSV:
+		return reporter.writeCSV(run)
+	case constant.BenchmarkFormatGoBench:
+		return reporter.writeGoBench(run)
+	default:
+		return reporter.writeTable(run)
+	}
+}
+
+// writeTable writes run as aligned table followed by blank line.
+func (reporter *benchmarkReporter) writeTable(run BenchmarkRun) error {
+	if _, err := fmt.Fprintf(reporter.w, "Run %d, took %d µs\n", run.Run, run.Duration.Microseconds()); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(reporter.w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "BENCHMARK\tAVG TIME (ns)"); err != nil {
+		return err
+	}
+
+	for _, result := range run.Results {
+		if _, err := fmt.Fprintf(tw, "%s\t%d\n", result.Name, result.AvgTime); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(reporter.w)
+	return err
+}
+
+// writeJSON writes run as single JSON line.
+func (reporter *benchmarkReporter) writeJSON(run BenchmarkRun) error {
+	return json.NewEncoder(reporter.w).Encode(run)
+}
+
+// writeCSV writes run as CSV rows, header is written once before the first run.
+func (reporter *benchmarkReporter) writeCSV(run BenchmarkRun) error {
+	cw := csv.NewWriter(reporter.w)
+	if !reporter.headerWritten {
+		if err := cw.Write([]string{"run", "name", "avg_time_ns"}); err != nil {
+			return err
+		}
+		reporter.headerWritten = true
+	}
+
+	for _, result := range run.Results {
+		if err := cw.Write([]string{strconv.Itoa(run.Run), result.Name, strconv.FormatInt(result.AvgTime, 10)}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeGoBench writes run in Go benchmark format, each run is one sample of every benchmark for benchstat.
+// Server reports average time per iteration only, therefore iteration count is always 1.
+func (reporter *benchmarkReporter) writeGoBench(run BenchmarkRun) error {
+	for _, result := range run.Results {
+		if _, err := fmt.Fprintf(reporter.w, "%s\t1\t%d ns/op\n", goBenchName(result.Name), result.AvgTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// goBenchName converts server-side benchmark name to Go benchmark name, which must start with "Benchmark"
+// and must not contain white spaces. Dashes are replaced as well, otherwise benchstat and assert command
+// read numeric name suffix, e.g. of "SHA3-256", as GOMAXPROCS suffix.
+func goBenchName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' {
+			return '_'
+		}
+
+		return r
+	}, strings.TrimSpace(name))
+
+	return "Benchmark" + name
+}
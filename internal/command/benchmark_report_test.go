@@ -0,0 +1,121 @@
+package command
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/slo"
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+)
+
+func TestBenchmarkReporter(t *testing.T) {
+	t.Parallel()
+
+	response := &cryptobrokerclientgo.BenchmarkResults{
+		Results: []cryptobrokerclientgo.BenchmarkResult{
+			{Name: "SHA3-512", AvgTime: 1200},
+			{Name: "RSA 4096 Sign", AvgTime: 3500000},
+		},
+	}
+
+	tests := []struct {
+		format string
+		output string
+	}{
+		{
+			format: constant.BenchmarkFormatTable,
+			output: strings.Join([]string{
+				"Run 1, took 1500 µs",
+				"BENCHMARK      AVG TIME (ns)",
+				"SHA3-512       1200",
+				"RSA 4096 Sign  3500000",
+				"",
+				"Run 2, took 1500 µs",
+				"BENCHMARK      AVG TIME (ns)",
+				"SHA3-512       1200",
+				"RSA 4096 Sign  3500000",
+				"",
+				"",
+			}, "\n"),
+		},
+		{
+			format: constant.BenchmarkFormatJSON,
+			output: `{"run":1,"duration_ns":1500000,"results":[{"name":"SHA3-512","avg_time_ns":1200},{"name":"RSA 4096 Sign","avg_time_ns":3500000}]}` + "\n" +
+				`{"run":2,"duration_ns":1500000,"results":[{"name":"SHA3-512","avg_time_ns":1200},{"name":"RSA 4096 Sign","avg_time_ns":3500000}]}` + "\n",
+		},
+		{
+			format: constant.BenchmarkFormatCSV,
+			output: "run,name,avg_time_ns\n1,SHA3-512,1200\n1,RSA 4096 Sign,3500000\n2,SHA3-512,1200\n2,RSA 4096 Sign,3500000\n",
+		},
+		{
+			format: constant.BenchmarkFormatGoBench,
+			output: "BenchmarkSHA3_512\t1\t1200 ns/op\nBenchmarkRSA_4096_Sign\t1\t3500000 ns/op\n" +
+				"BenchmarkSHA3_512\t1\t1200 ns/op\nBenchmarkRSA_4096_Sign\t1\t3500000 ns/op\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+			reporter, err := newBenchmarkReporter(&output, tt.format)
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+
+			for run := 1; run <= 2; run++ {
+				if err := reporter.Write(newBenchmarkRun(run, 1500*time.Microsecond, response)); err != nil {
+					t.Fatalf("expected nil error, got %v", err)
+				}
+			}
+
+			if output.String() != tt.output {
+				t.Fatalf("expected output:\n%q\ngot:\n%q", tt.output, output.String())
+			}
+		})
+	}
+}
+
+func TestBenchmarkReporterGoBenchNames(t *testing.T) {
+	t.Parallel()
+
+	// Names differing only in numeric suffix must not be read as one benchmark with GOMAXPROCS suffix
+	response := &cryptobrokerclientgo.BenchmarkResults{
+		Results: []cryptobrokerclientgo.BenchmarkResult{
+			{Name: "SHA3-256", AvgTime: 1000},
+			{Name: "SHA3-512", AvgTime: 1200},
+		},
+	}
+
+	var output bytes.Buffer
+	reporter, err := newBenchmarkReporter(&output, constant.BenchmarkFormatGoBench)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if err := reporter.Write(newBenchmarkRun(1, time.Millisecond, response)); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	results := slo.NewResults()
+	if err := results.Parse(&output); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	list := results.List()
+	if len(list) != 2 || list[0].Latencies[0] != 1000 || list[1].Latencies[0] != 1200 {
+		t.Fatalf("expected 2 separate benchmarks, got %d", len(list))
+	}
+}
+
+func TestBenchmarkReporterUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newBenchmarkReporter(&bytes.Buffer{}, "xml"); err == nil {
+		t.Fatal("expected error for unsupported format, got nil")
+	}
+}
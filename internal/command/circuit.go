@@ -0,0 +1,66 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// circuit breaker states as observed from the client side
+const (
+	circuitStateClosed   = "closed"
+	circuitStateOpen     = "open"
+	circuitStateHalfOpen = "half-open"
+)
+
+// circuitTracker follows circuit breaker state of the library based on errors returned by consecutive calls
+// and logs every transition together with the time spent in the previous state.
+type circuitTracker struct {
+	logger  *slog.Logger
+	state   string
+	changed time.Time
+}
+
+// newCircuitTracker initializes tracker assuming closed circuit.
+func newCircuitTracker(logger *slog.Logger) *circuitTracker {
+	return &circuitTracker{
+		logger:  logger,
+		state:   circuitStateClosed,
+		changed: time.Now(),
+	}
+}
+
+// observe updates tracked state based on error returned by library call.
+func (tracker *circuitTracker) observe(ctx context.Context, err error) {
+	state := circuitStateClosed
+	switch {
+	case errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen):
+		state = circuitStateOpen
+	case errors.Is(err, cryptobrokerclientgo.ErrCircuitHalfOpen):
+		state = circuitStateHalfOpen
+	}
+
+	if state == tracker.state {
+		return
+	}
+
+	now := time.Now()
+	tracker.logger.WarnContext(ctx, "Circuit breaker state changed",
+		"from", tracker.state,
+		"to", state,
+		"previous_state_duration_ms", now.Sub(tracker.changed).Milliseconds())
+	tracker.state = state
+	tracker.changed = now
+}
+
+// recordCircuitOpen reports request rejected by open circuit breaker through logger and span.
+func recordCircuitOpen(ctx context.Context, logger *slog.Logger, span trace.Span, operation string) {
+	logger.WarnContext(ctx, "Request short-circuited by circuit breaker", "operation", operation)
+	span.AddEvent("circuit_breaker.open")
+	span.SetStatus(codes.Error, cryptobrokerclientgo.ErrCircuitOpen.Error())
+}
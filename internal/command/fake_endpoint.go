@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -11,38 +12,78 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/chaos"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
 	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// errCancelledByFaultInjection is the cause of contexts cancelled mid-flight by fault injection.
+var errCancelledByFaultInjection = errors.New("request cancelled by fault injection")
+
 // FakeEndpoint represents command that repeatedly sends fake endpoint request to crypto broker and displays its response
 type FakeEndpoint struct {
 	logger              *slog.Logger
 	cryptoBrokerLibrary *cryptobrokerclientgo.Library
 	tracerProvider      *otel.TracerProvider
+	retryPolicy         retry.Policy
+	injector            *chaos.Injector
+	circuit             *circuitTracker
+	stats               fakeEndpointStats
+}
+
+// fakeEndpointStats counts outcomes of fake endpoint calls.
+type fakeEndpointStats struct {
+	loopStats
+	dropped   int
+	delayed   int
+	cancelled int
+	corrupted int
 }
 
 // NewFakeEndpoint initializes fake endpoint command
-func NewFakeEndpoint(ctx context.Context, lib *cryptobrokerclientgo.Library, logger *slog.Logger, tracerProvider *otel.TracerProvider) (*FakeEndpoint, error) {
+func NewFakeEndpoint(ctx context.Context, lib *cryptobrokerclientgo.Library, logger *slog.Logger, tracerProvider *otel.TracerProvider, retryPolicy retry.Policy) (*FakeEndpoint, error) {
 	return &FakeEndpoint{
 		logger:              logger,
 		cryptoBrokerLibrary: lib,
 		tracerProvider:      tracerProvider,
+		retryPolicy:         retryPolicy,
+		circuit:             newCircuitTracker(logger),
 	}, nil
 }
 
 // Run executes command logic.
-func (command *FakeEndpoint) Run(ctx context.Context, flagLoop int) error {
+// When chaosConfig enables any fault, errors of single calls are logged and the loop carries on,
+// so that circuit breaker transitions can be observed over time.
+func (command *FakeEndpoint) Run(ctx context.Context, flagLoop int, chaosConfig chaos.Config) error {
 	defer func() { _ = command.gracefulShutdown() }()
 
+	if chaosConfig.Enabled() {
+		injector, err := chaos.NewInjector(chaosConfig)
+		if err != nil {
+			return fmt.Errorf("could not initialize fault injector, err: %w", err)
+		}
+
+		command.injector = injector
+		command.logger.InfoContext(ctx, "Fault injection enabled",
+			"drop_rate", chaosConfig.DropRate,
+			"latency", chaosConfig.Latency.String(),
+			"latency_jitter", chaosConfig.LatencyJitter.String(),
+			"latency_distribution", chaosConfig.LatencyDistribution,
+			"cancel_rate", chaosConfig.CancelRate,
+			"cancel_after", chaosConfig.CancelAfter.String(),
+			"corrupt_rate", chaosConfig.CorruptRate,
+			"corrupt_size", chaosConfig.CorruptSize)
+	}
+
 	payload := cryptobrokerclientgo.FakeEndpointPayload{
 		Metadata: nil,
 	}
 
-	command.logger.Info("Calling fake endpoint")
+	command.logger.InfoContext(ctx, "Calling fake endpoint")
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
@@ -53,14 +94,19 @@ func (command *FakeEndpoint) Run(ctx context.Context, flagLoop int) error {
 			panic(err)
 		}
 
+		defer command.logSummary()
 		for {
 			select {
 			case <-c:
-				command.logger.Info("Received SIGTERM signal")
+				command.logger.InfoContext(ctx, "Received SIGTERM signal")
 				return nil
 			default:
 				if err := command.callFakeEndpoint(ctx, payload); err != nil {
-					return err
+					if command.injector == nil {
+						return err
+					}
+
+					command.logger.WarnContext(ctx, "Fake endpoint call failed", "error", err)
 				}
 
 				time.Sleep(toSleep)
@@ -79,10 +125,7 @@ func (command *FakeEndpoint) Run(ctx context.Context, flagLoop int) error {
 // Internally method measures execution time and prints it through logger.
 func (command *FakeEndpoint) callFakeEndpoint(ctx context.Context, payload cryptobrokerclientgo.FakeEndpointPayload) error {
 	tracer := command.tracerProvider.GetTracer("crypto-broker-cli-go")
-	correlationId := ""
-	if payload.Metadata != nil && payload.Metadata.TraceContext != nil {
-		correlationId = payload.Metadata.TraceContext.CorrelationId
-	}
+	correlationId := correlationIdOf(ctx, payload.Metadata)
 
 	ctx, span := tracer.Start(ctx, "CLI.FakeEndpoint",
 		trace.WithAttributes(
@@ -91,23 +134,26 @@ func (command *FakeEndpoint) callFakeEndpoint(ctx context.Context, payload crypt
 		))
 	defer span.End()
 
-	spanContext := span.SpanContext()
+	// Metadata id is shared by all attempts, trace context is injected per attempt
 	if payload.Metadata == nil {
 		payload.Metadata = &cryptobrokerclientgo.Metadata{
 			Id: uuid.New().String(),
 		}
 	}
-	payload.Metadata.TraceContext = &cryptobrokerclientgo.TraceContext{
-		TraceId:       spanContext.TraceID().String(),
-		SpanId:        spanContext.SpanID().String(),
-		TraceFlags:    spanContext.TraceFlags().String(),
-		TraceState:    spanContext.TraceState().String(),
-		CorrelationId: correlationId,
-	}
 
 	timestampFakeEndpointStart := time.Now()
-	responseBody, err := command.cryptoBrokerLibrary.FakeEndpoint(ctx, payload)
+	// Injected faults are recorded like failures of the broker they simulate
+	responseBody, err := retry.Do(ctx, command.retryPolicy, tracer, "CLI.FakeEndpoint.Attempt", payload,
+		func(ctx context.Context) { setTraceContext(ctx, payload.Metadata, correlationId) },
+		otel.MeasureAttempts(otel.OperationFakeEndpoint, "", 0, withFaults(command, command.cryptoBrokerLibrary.FakeEndpoint)))
+	command.circuit.observe(ctx, err)
+	command.stats.record(err)
 	if err != nil {
+		if errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen) {
+			recordCircuitOpen(ctx, command.logger, span, "FakeEndpoint")
+			return err
+		}
+
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return err
@@ -118,12 +164,97 @@ func (command *FakeEndpoint) callFakeEndpoint(ctx context.Context, payload crypt
 
 	span.SetStatus(codes.Ok, "Fake endpoint operation completed successfully")
 
-	command.logger.Info("Fake endpoint response", "response", responseBody)
-	command.logger.Info("Fake endpoint call took", "duration_microseconds", float64(durationElapsedFakeEndpoint.Nanoseconds())/1000.0)
+	command.logger.InfoContext(ctx, "Fake endpoint response", "response", responseBody)
+	command.logger.InfoContext(ctx, "Fake endpoint call took", "duration_microseconds", float64(durationElapsedFakeEndpoint.Nanoseconds())/1000.0)
 
 	return nil
 }
 
+// withFaults wraps library call, so that faults are drawn and applied for every attempt of retry policy.
+// Dropped attempts fail with chaos.ErrDropped, which carries UNAVAILABLE code, and are therefore retried
+// and observed like any other failed attempt. Attempts cancelled by fault injection report it as cause.
+func withFaults[R any](command *FakeEndpoint, call func(context.Context, cryptobrokerclientgo.FakeEndpointPayload) (R, error)) func(context.Context, cryptobrokerclientgo.FakeEndpointPayload) (R, error) {
+	return func(ctx context.Context, payload cryptobrokerclientgo.FakeEndpointPayload) (R, error) {
+		ctx, cancel, err := command.injectFault(ctx, trace.SpanFromContext(ctx), &payload)
+		defer cancel()
+		if err != nil {
+			var response R
+			return response, err
+		}
+
+		response, err := call(ctx, payload)
+		if cause := context.Cause(ctx); err != nil && errors.Is(cause, errCancelledByFaultInjection) {
+			command.stats.cancelled++
+			err = fmt.Errorf("%w: %w", cause, err)
+		}
+
+		return response, err
+	}
+}
+
+// injectFault applies faults drawn by the injector to single attempt of the request.
+// It returns context to be used for the attempt, its cancel function and non-nil error if the attempt was dropped.
+func (command *FakeEndpoint) injectFault(ctx context.Context, span trace.Span, payload *cryptobrokerclientgo.FakeEndpointPayload) (context.Context, context.CancelFunc, error) {
+	if command.injector == nil {
+		return ctx, func() {}, nil
+	}
+
+	fault := command.injector.Next()
+	span.SetAttributes(
+		otel.AttributeChaosDropped.Bool(fault.Drop),
+		otel.AttributeChaosDelayMs.Int64(fault.Delay.Milliseconds()),
+		otel.AttributeChaosCancelAfterMs.Int64(fault.CancelAfter.Milliseconds()),
+		otel.AttributeChaosCorruptSize.Int(fault.CorruptSize),
+	)
+
+	if fault.Drop {
+		command.stats.dropped++
+		span.AddEvent("chaos.dropped")
+		return ctx, func() {}, chaos.ErrDropped
+	}
+
+	if fault.Delay > 0 {
+		command.stats.delayed++
+		span.AddEvent("chaos.delayed")
+		select {
+		case <-time.After(fault.Delay):
+		case <-ctx.Done():
+			return ctx, func() {}, ctx.Err()
+		}
+	}
+
+	if fault.CorruptSize > 0 {
+		command.stats.corrupted++
+		span.AddEvent("chaos.corrupted")
+		// metadata is shared by attempts, corrupted id is set on copy of this attempt only
+		metadata := *payload.Metadata
+		metadata.Id = chaos.CorruptID(metadata.Id, fault.CorruptSize)
+		payload.Metadata = &metadata
+	}
+
+	if fault.CancelAfter <= 0 {
+		return ctx, func() {}, nil
+	}
+
+	span.AddEvent("chaos.cancel_scheduled")
+	ctx, cancel := context.WithCancelCause(ctx)
+	timer := time.AfterFunc(fault.CancelAfter, func() { cancel(errCancelledByFaultInjection) })
+
+	return ctx, func() {
+		timer.Stop()
+		cancel(nil)
+	}, nil
+}
+
+// logSummary prints outcome counters of looped calls.
+func (command *FakeEndpoint) logSummary() {
+	command.stats.logSummary(command.logger, "Fake endpoint summary",
+		"dropped", command.stats.dropped,
+		"delayed", command.stats.delayed,
+		"cancelled", command.stats.cancelled,
+		"corrupted", command.stats.corrupted)
+}
+
 // gracefulShutdown closes library connection.
 func (command *FakeEndpoint) gracefulShutdown() error {
 	command.logger.Info("Closing crypto broker library connection")
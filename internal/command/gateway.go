@@ -0,0 +1,565 @@
+package command
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/audit"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/correlation"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/flags"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// paths of gateway endpoints
+const (
+	gatewayPathHash            = "/v1/hash"
+	gatewayPathSignCertificate = "/v1/sign-certificate"
+	gatewayPathHealth          = "/v1/health"
+	gatewayPathBenchmark       = "/v1/benchmark"
+	gatewayPathOpenAPI         = "/openapi.json"
+)
+
+// gatewayHeaderCorrelationId is header of correlation id sent with broker requests of HTTP request.
+// It is generated when missing and returned in response.
+const gatewayHeaderCorrelationId = "X-Correlation-Id"
+
+// timeouts of gateway HTTP server, responses are not bounded as benchmarks may take long
+const (
+	gatewayReadHeaderTimeout = 5 * time.Second
+	gatewayReadTimeout       = 30 * time.Second
+	gatewayShutdownTimeout   = 30 * time.Second
+)
+
+// Gateway represents command that exposes broker operations as JSON endpoints over HTTP,
+// for services that cannot use client library.
+type Gateway struct {
+	logger          *slog.Logger
+	libraries       *pool.Pool
+	tracerProvider  *otel.TracerProvider
+	hashData        *HashData
+	signCertificate *SignCertificate
+	health          *Health
+	benchClient     *BenchClient
+	// token is bearer token required by authenticated endpoints, empty token disables authentication
+	token                []byte
+	maxRequestSize       int64
+	maxBenchmarkRequests int
+	// benchmarking is held by running benchmark, so that concurrent benchmarks do not skew each other
+	benchmarking sync.Mutex
+	version      string
+	// decode decodes request body, it is decodeGatewayRequest unless replaced by tests
+	decode func(body []byte, request any) error
+}
+
+// gatewayRoute represents gateway endpoint together with its OpenAPI description.
+type gatewayRoute struct {
+	method      string
+	path        string
+	operationId string
+	summary     string
+	// authenticated endpoints require bearer token, if gateway is configured with one
+	authenticated bool
+	// request and response are zero values of body types, nil request means endpoint has no body
+	request  any
+	response any
+	serve    func(ctx context.Context, body []byte) (int, any)
+}
+
+// NewGateway initializes gateway command. Every signing request is recorded in auditLog, nil audit log
+// disables auditing. Requests must carry token as bearer token, empty token disables authentication.
+func NewGateway(ctx context.Context, libraries *pool.Pool, logger *slog.Logger, tracerProvider *otel.TracerProvider, retryPolicy retry.Policy, auditLog *audit.Log, token string, version string) (*Gateway, error) {
+	hashData, err := NewHashData(ctx, libraries, logger, tracerProvider, retryPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	signCertificate, err := NewSignCertificate(ctx, libraries, logger, tracerProvider, retryPolicy, auditLog)
+	if err != nil {
+		return nil, err
+	}
+
+	health, err := NewHealth(ctx, libraries, logger, tracerProvider, retryPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	benchClient, err := NewBenchClient(ctx, libraries, logger, tracerProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Gateway{
+		logger:          logger,
+		libraries:       libraries,
+		tracerProvider:  tracerProvider,
+		hashData:        hashData,
+		signCertificate: signCertificate,
+		health:          health,
+		benchClient:     benchClient,
+		token:           []byte(token),
+		version:         version,
+		decode:          decodeGatewayRequest,
+	}, nil
+}
+
+// Run executes command logic.
+// It serves gateway endpoints on flagListen until SIGTERM is received, then it waits for requests in flight.
+// Request bodies larger than flagMaxRequestSize bytes are refused, as are benchmarks of more than
+// flagMaxBenchmarkRequests requests in total.
+func (command *Gateway) Run(ctx context.Context, flagListen string, flagMaxRequestSize int, flagMaxBenchmarkRequests int) error {
+	defer func() { _ = command.gracefulShutdown() }()
+
+	command.maxRequestSize = int64(flagMaxRequestSize)
+	command.maxBenchmarkRequests = flagMaxBenchmarkRequests
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	server := &http.Server{
+		Addr:              flagListen,
+		Handler:           command.handler(),
+		ReadHeaderTimeout: gatewayReadHeaderTimeout,
+		ReadTimeout:       gatewayReadTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	if len(command.token) == 0 {
+		command.logger.WarnContext(ctx, "Gateway token is not set, all endpoints are served without authentication",
+			"listen", flagListen)
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		command.logger.InfoContext(ctx, "Serving gateway", "listen", flagListen, "authentication", len(command.token) > 0,
+			"max_request_size", flagMaxRequestSize)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(c)
+
+	var err error
+	select {
+	case <-c:
+		command.logger.InfoContext(ctx, "Received SIGTERM signal")
+	case err = <-serverErr:
+		err = fmt.Errorf("gateway server failed, err: %w", err)
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), gatewayShutdownTimeout)
+	defer cancelShutdown()
+
+	return errors.Join(err, server.Shutdown(shutdownCtx))
+}
+
+// routes returns endpoints of gateway.
+func (command *Gateway) routes() []gatewayRoute {
+	return []gatewayRoute{
+		{
+			method:        http.MethodPost,
+			path:          gatewayPathHash,
+			operationId:   "hashData",
+			summary:       "Hash data",
+			authenticated: true,
+			request:       GatewayHashRequest{},
+			response:      GatewayHashResponse{},
+			serve:         command.serveHash,
+		},
+		{
+			method:        http.MethodPost,
+			path:          gatewayPathSignCertificate,
+			operationId:   "signCertificate",
+			summary:       "Sign certificate",
+			authenticated: true,
+			request:       GatewaySignCertificateRequest{},
+			response:      GatewaySignCertificateResponse{},
+			serve:         command.serveSignCertificate,
+		},
+		{
+			method:      http.MethodGet,
+			path:        gatewayPathHealth,
+			operationId: "checkHealth",
+			summary:     "Check broker health, responds with 503 status unless broker is SERVING",
+			response:    GatewayHealthResponse{},
+			serve:       command.serveHealth,
+		},
+		{
+			method:        http.MethodPost,
+			path:          gatewayPathBenchmark,
+			operationId:   "runBenchmark",
+			summary:       "Run client-side benchmark, responds with 409 status while another benchmark is running",
+			authenticated: true,
+			request:       GatewayBenchmarkRequest{},
+			response:      GatewayBenchmarkResponse{},
+			serve:         command.serveBenchmark,
+		},
+	}
+}
+
+// handler builds HTTP handler serving gateway endpoints and OpenAPI spec.
+func (command *Gateway) handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, route := range command.routes() {
+		mux.Handle(route.method+" "+route.path, command.traced(route))
+	}
+
+	mux.HandleFunc("GET "+gatewayPathOpenAPI, func(w http.ResponseWriter, r *http.Request) {
+		spec, err := GatewayOpenAPI(command.version)
+		if err != nil {
+			command.respond(w, r, http.StatusInternalServerError, GatewayError{Error: err.Error()})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(spec)
+	})
+
+	return mux
+}
+
+// traced wraps route in server span that joins trace of incoming traceparent header. Trace context
+// and correlation id of the request are returned in response headers.
+func (command *Gateway) traced(route gatewayRoute) http.Handler {
+	tracer := command.tracerProvider.GetTracer("crypto-broker-cli-go")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationId := r.Header.Get(gatewayHeaderCorrelationId)
+		if correlationId == "" {
+			correlationId = correlation.NewID()
+		}
+
+		ctx := correlation.ContextWithID(otel.ContextWithHTTPParent(r.Context(), r.Header), correlationId)
+		ctx, span := tracer.Start(ctx, "Gateway "+route.method+" "+route.path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				otel.AttributeHttpRequestMethod.String(route.method),
+				otel.AttributeHttpRoute.String(route.path),
+				otel.AttributeCorrelationId.String(correlationId),
+			))
+		defer span.End()
+
+		otel.InjectHTTP(ctx, w.Header())
+		w.Header().Set(gatewayHeaderCorrelationId, correlationId)
+
+		code, response := command.serve(ctx, w, r, route)
+		span.SetAttributes(otel.AttributeHttpResponseStatusCode.Int(code))
+		if gatewayErr, ok := response.(GatewayError); ok && code >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, gatewayErr.Error)
+		}
+
+		command.respond(w, r.WithContext(ctx), code, response)
+	})
+}
+
+// serve authenticates request, reads its body within size limit and passes it to route.
+// Body is zeroed once served, as it may contain private key.
+func (command *Gateway) serve(ctx context.Context, w http.ResponseWriter, r *http.Request, route gatewayRoute) (int, any) {
+	if route.authenticated && !command.authenticated(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="crypto-broker-gateway"`)
+		return http.StatusUnauthorized, GatewayError{Error: "missing or invalid bearer token"}
+	}
+
+	if route.request == nil {
+		return route.serve(ctx, nil)
+	}
+
+	body, err := readAllZeroing(http.MaxBytesReader(w, r.Body, command.maxRequestSize))
+	if _, ok := errors.AsType[*http.MaxBytesError](err); ok {
+		return http.StatusRequestEntityTooLarge, GatewayError{Error: fmt.Sprintf("request body exceeds %d bytes", command.maxRequestSize)}
+	}
+
+	if err != nil {
+		return http.StatusBadRequest, GatewayError{Error: err.Error()}
+	}
+	defer clear(body)
+
+	trace.SpanFromContext(ctx).SetAttributes(otel.AttributeHttpRequestBodySize.Int(len(body)))
+	return route.serve(ctx, body)
+}
+
+// authenticated reports whether request carries configured bearer token, compared in constant time.
+func (command *Gateway) authenticated(r *http.Request) bool {
+	if len(command.token) == 0 {
+		return true
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), command.token) == 1
+}
+
+// respond writes response as JSON with given status code.
+func (command *Gateway) respond(w http.ResponseWriter, r *http.Request, code int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		command.logger.WarnContext(r.Context(), "Failed to write gateway response", "error", err)
+	}
+}
+
+// serveHash sends hash request of body.
+func (command *Gateway) serveHash(ctx context.Context, body []byte) (int, any) {
+	var request GatewayHashRequest
+	if err := command.decode(body, &request); err != nil {
+		return http.StatusBadRequest, GatewayError{Error: err.Error()}
+	}
+
+	input := []byte(request.Input)
+	switch {
+	case request.Input != "" && request.InputBase64 != nil:
+		return http.StatusBadRequest, GatewayError{Error: "only one of input and input_base64 can be set"}
+	case request.InputBase64 != nil:
+		input = request.InputBase64
+	case request.Input == "":
+		return http.StatusBadRequest, GatewayError{Error: "input or input_base64 is required"}
+	}
+
+	payload := cryptobrokerclientgo.HashDataPayload{
+		Input:        input,
+		Profile:      cmp.Or(request.Profile, "Default"),
+		OutputFormat: cryptobrokerclientgo.OutputFormatHex,
+	}
+
+	switch request.OutputFormat {
+	case "", "hex":
+	case "raw":
+		payload.OutputFormat = cryptobrokerclientgo.OutputFormatRaw
+	default:
+		return http.StatusBadRequest, GatewayError{Error: "output_format must be hex or raw"}
+	}
+
+	command.logger.InfoContext(ctx, "Hashing input", "request", hashDataPayloadLog(payload))
+	response, err := command.hashData.hashBytes(ctx, payload)
+	if err != nil {
+		return gatewayError(err)
+	}
+
+	if response == nil {
+		return http.StatusBadGateway, GatewayError{Error: "broker returned empty response"}
+	}
+
+	return http.StatusOK, GatewayHashResponse{
+		HashAlgorithm: response.GetHashAlgorithm(),
+		HashValueHex:  response.GetHashValueHex(),
+		HashValueRaw:  response.GetHashValueRaw(),
+	}
+}
+
+// serveSignCertificate sends certificate signing request of body. Signed certificate is returned only
+// once its issuance is audited, private key is zeroed once the request is sent, except its copy made by client library.
+func (command *Gateway) serveSignCertificate(ctx context.Context, body []byte) (int, any) {
+	var request GatewaySignCertificateRequest
+	// closure zeroes key decoded below, also when decoding of other fields fails
+	defer func() { clear(request.CAPrivateKey) }()
+	if err := command.decode(body, &request); err != nil {
+		return http.StatusBadRequest, GatewayError{Error: err.Error()}
+	}
+
+	if request.CSR == "" || request.CACert == "" || len(request.CAPrivateKey) == 0 {
+		return http.StatusBadRequest, GatewayError{Error: "csr, ca_cert and ca_private_key are required"}
+	}
+
+	encoding := cmp.Or(request.Encoding, constant.EncodingPEM)
+	if err := flags.ValidateFlagEncoding(encoding); err != nil {
+		return http.StatusBadRequest, GatewayError{Error: fmt.Sprintf("encoding must be %s or %s", constant.EncodingPEM, constant.EncodingDER)}
+	}
+
+	payload := cryptobrokerclientgo.SignCertificatePayload{
+		Profile:      cmp.Or(request.Profile, "Default"),
+		CSR:          []byte(request.CSR),
+		CAPrivateKey: request.CAPrivateKey,
+		CACert:       []byte(request.CACert),
+	}
+
+	if request.Subject != "" {
+		payload.Subject = &request.Subject
+	}
+
+	command.logger.InfoContext(ctx,
+		fmt.Sprintf("Signing certificate using %s profile", payload.Profile),
+		"request", signCertificatePayloadLog(payload),
+	)
+
+	response, err := command.signCertificate.issueCertificate(ctx, payload, encoding)
+	if err != nil {
+		return gatewayError(err)
+	}
+
+	if response == nil {
+		return http.StatusBadGateway, GatewayError{Error: "broker returned empty response"}
+	}
+
+	return http.StatusOK, GatewaySignCertificateResponse{
+		Certificate:    response.GetPem(),
+		CertificateDER: response.GetDer(),
+	}
+}
+
+// serveHealth checks broker health, status other than SERVING is reported with 503 status code.
+func (command *Gateway) serveHealth(ctx context.Context, _ []byte) (int, any) {
+	durationElapsed, err := command.health.checkHealth(ctx)
+	response := GatewayHealthResponse{
+		Status:    cryptobrokerclientgo.StatusServing,
+		LatencyMs: float64(durationElapsed.Nanoseconds()) / 1e6,
+	}
+
+	switch {
+	case err == nil:
+		return http.StatusOK, response
+	case errors.Is(err, ErrHealthNotServing):
+		response.Status = cryptobrokerclientgo.StatusNotServing
+	default:
+		response.Status = cryptobrokerclientgo.StatusUnknown
+	}
+
+	response.Error = err.Error()
+	return http.StatusServiceUnavailable, response
+}
+
+// serveBenchmark runs client-side benchmark of matrix of body and returns results of all combinations.
+// Only one benchmark runs at a time, benchmark is stopped after the current combination if client disconnects.
+func (command *Gateway) serveBenchmark(ctx context.Context, body []byte) (int, any) {
+	var request GatewayBenchmarkRequest
+	if err := command.decode(body, &request); err != nil {
+		return http.StatusBadRequest, GatewayError{Error: err.Error()}
+	}
+
+	matrix, err := command.benchmarkMatrix(request)
+	if err != nil {
+		return http.StatusBadRequest, GatewayError{Error: err.Error()}
+	}
+
+	if !command.benchmarking.TryLock() {
+		return http.StatusConflict, GatewayError{Error: "another benchmark is running"}
+	}
+	defer command.benchmarking.Unlock()
+
+	response := GatewayBenchmarkResponse{Results: []BenchClientCell{}}
+	err = command.benchClient.benchmark(ctx, matrix, nil, func(cell BenchClientCell) error {
+		response.Results = append(response.Results, cell)
+		return nil
+	})
+	if err != nil {
+		return http.StatusInternalServerError, GatewayError{Error: err.Error()}
+	}
+
+	return http.StatusOK, response
+}
+
+// benchmarkMatrix returns validated benchmark matrix of request with defaults of empty parameters.
+func (command *Gateway) benchmarkMatrix(request GatewayBenchmarkRequest) (BenchClientMatrix, error) {
+	matrix := BenchClientMatrix{
+		Operations:    orDefault(request.Operations, constant.BenchOperationHash),
+		Profiles:      orDefault(request.Profiles, "Default"),
+		OutputFormats: orDefault(request.OutputFormats, "hex"),
+		KeyTypes:      orDefault(request.KeyTypes, constant.KeyTypeP256),
+		Concurrency:   orDefault(request.Concurrency, 1),
+		Requests:      cmp.Or(request.Requests, constant.DefaultBenchClientRequests),
+	}
+
+	if err := flags.ValidateFlagBenchClient(matrix.Operations, matrix.OutputFormats, matrix.KeyTypes, matrix.Concurrency, matrix.Requests); err != nil {
+		return BenchClientMatrix{}, err
+	}
+
+	sizes, err := flags.ParseFlagSizes(orDefault(request.Sizes, "1KiB"))
+	if err != nil {
+		return BenchClientMatrix{}, err
+	}
+
+	// Inputs are generated in gateway memory, so sizes are limited to what the broker accepts
+	if size := slices.Max(sizes); size > constant.MaxGatewayBenchmarkSize {
+		return BenchClientMatrix{}, fmt.Errorf("benchmark size of %d bytes exceeds limit of %dMiB", size, constant.MaxGatewayBenchmarkSize>>20)
+	}
+	matrix.Sizes = sizes
+
+	if total := len(benchClientCells(matrix)) * matrix.Requests; total > command.maxBenchmarkRequests {
+		return BenchClientMatrix{}, fmt.Errorf("benchmark of %d requests exceeds limit of %d requests", total, command.maxBenchmarkRequests)
+	}
+
+	return matrix, nil
+}
+
+// gracefulShutdown closes library connections shared by all endpoints once requests in flight complete.
+func (command *Gateway) gracefulShutdown() error {
+	command.logger.Info("Closing crypto broker library connections")
+	ctx, cancel := context.WithTimeout(context.Background(), constant.PoolDrainTimeout)
+	defer cancel()
+	return command.libraries.Shutdown(ctx)
+}
+
+// decodeGatewayRequest decodes JSON body into request, unknown fields are refused.
+func decodeGatewayRequest(body []byte, request any) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(request); err != nil {
+		return fmt.Errorf("invalid request body, err: %w", err)
+	}
+
+	return nil
+}
+
+// orDefault returns values, or defaultValue alone if values are empty.
+func orDefault[T any](values []T, defaultValue T) []T {
+	if len(values) == 0 {
+		return []T{defaultValue}
+	}
+
+	return values
+}
+
+// gatewayError maps error of broker request to HTTP status code and response. Errors returned by the broker
+// are reported with their gRPC code, requests short-circuited by an open circuit breaker or refused by library pool
+// without healthy library as unavailable.
+func gatewayError(err error) (int, any) {
+	if errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen) || errors.Is(err, pool.ErrNoLibrary) || errors.Is(err, pool.ErrClosed) {
+		return http.StatusServiceUnavailable, GatewayError{Error: err.Error()}
+	}
+
+	grpcStatus, ok := status.FromError(err)
+	if !ok || grpcStatus.Code() == grpccodes.Unknown {
+		return http.StatusInternalServerError, GatewayError{Error: err.Error()}
+	}
+
+	code := http.StatusBadGateway
+	switch grpcStatus.Code() {
+	case grpccodes.InvalidArgument, grpccodes.FailedPrecondition, grpccodes.OutOfRange:
+		code = http.StatusBadRequest
+	case grpccodes.NotFound:
+		code = http.StatusNotFound
+	case grpccodes.PermissionDenied:
+		code = http.StatusForbidden
+	case grpccodes.Unimplemented:
+		code = http.StatusNotImplemented
+	case grpccodes.Unavailable, grpccodes.ResourceExhausted, grpccodes.Aborted:
+		code = http.StatusServiceUnavailable
+	case grpccodes.DeadlineExceeded:
+		code = http.StatusGatewayTimeout
+	}
+
+	return code, GatewayError{Error: err.Error(), Code: grpcStatus.Code().String()}
+}
@@ -0,0 +1,129 @@
+package command
+
+import (
+	"errors"
+	"strconv"
+	"unicode/utf8"
+)
+
+// GatewayHashRequest is body of hash request of gateway.
+type GatewayHashRequest struct {
+	Input        string `json:"input,omitempty" doc:"Text to be hashed, exclusive with input_base64"`
+	InputBase64  []byte `json:"input_base64,omitempty" doc:"Base64 encoded bytes to be hashed, exclusive with input"`
+	Profile      string `json:"profile,omitempty" doc:"Crypto profile, Default when empty"`
+	OutputFormat string `json:"output_format,omitempty" enum:"hex,raw" doc:"Format of hash value, hex when empty"`
+}
+
+// GatewayHashResponse is body of successful hash response of gateway.
+type GatewayHashResponse struct {
+	HashAlgorithm string `json:"hash_algorithm"`
+	HashValueHex  string `json:"hash_value_hex,omitempty" doc:"Hex encoded hash value, set for hex output format"`
+	HashValueRaw  []byte `json:"hash_value_raw,omitempty" doc:"Base64 encoded hash value, set for raw output format"`
+}
+
+// GatewaySignCertificateRequest is body of sign certificate request of gateway.
+type GatewaySignCertificateRequest struct {
+	Profile      string     `json:"profile,omitempty" doc:"Crypto profile, Default when empty"`
+	CSR          string     `json:"csr" doc:"PEM encoded certificate signing request"`
+	CACert       string     `json:"ca_cert" doc:"PEM encoded CA certificate"`
+	CAPrivateKey gatewayKey `json:"ca_private_key" doc:"PEM encoded CA private key, zeroed once the request is sent"`
+	Subject      string     `json:"subject,omitempty" doc:"Custom subject overriding subject of the CSR"`
+	Encoding     string     `json:"encoding,omitempty" enum:"pem,der" doc:"Encoding of signed certificate, pem when empty"`
+}
+
+// GatewaySignCertificateResponse is body of successful sign certificate response of gateway.
+type GatewaySignCertificateResponse struct {
+	Certificate    string `json:"certificate,omitempty" doc:"PEM encoded certificate, set for pem encoding"`
+	CertificateDER []byte `json:"certificate_der,omitempty" doc:"Base64 encoded DER certificate, set for der encoding"`
+}
+
+// GatewayHealthResponse is body of health response of gateway.
+type GatewayHealthResponse struct {
+	Status    string  `json:"status" enum:"SERVING,NOT_SERVING,UNKNOWN"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// GatewayBenchmarkRequest is body of client-side benchmark request of gateway, see BenchClientMatrix.
+// Empty parameters default to single combination of hash operation.
+type GatewayBenchmarkRequest struct {
+	Operations    []string `json:"operations,omitempty" doc:"Benchmarked operations hash and sign, hash when empty"`
+	Profiles      []string `json:"profiles,omitempty" doc:"Crypto profiles, Default when empty"`
+	Sizes         []string `json:"sizes,omitempty" doc:"Hash input sizes with optional B, KiB or MiB unit, up to 4MiB, 1KiB when empty"`
+	OutputFormats []string `json:"output_formats,omitempty" doc:"Hash output formats hex and raw, hex when empty"`
+	KeyTypes      []string `json:"key_types,omitempty" doc:"Key types of generated CA and CSR for sign operation, P256 when empty"`
+	Concurrency   []int    `json:"concurrency,omitempty" doc:"Numbers of concurrent requests, 1 when empty"`
+	Requests      int      `json:"requests,omitempty" doc:"Number of requests per combination, 100 when empty"`
+}
+
+// GatewayBenchmarkResponse is body of successful client-side benchmark response of gateway.
+type GatewayBenchmarkResponse struct {
+	Results []BenchClientCell `json:"results"`
+}
+
+// GatewayError is body of failed gateway response.
+type GatewayError struct {
+	Error string `json:"error"`
+	Code  string `json:"grpc_code,omitempty" doc:"gRPC status code returned by the broker"`
+}
+
+// gatewayKey is private key decoded from JSON string without intermediate string, so that it can be zeroed.
+type gatewayKey []byte
+
+// UnmarshalJSON implements json.Unmarshaler. Input is valid JSON string, as it is validated by decoder beforehand.
+func (key *gatewayKey) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return errors.New("private key must be JSON string")
+	}
+
+	data = data[1 : len(data)-1]
+	decoded := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] != '\\' {
+			decoded = append(decoded, data[i])
+			continue
+		}
+
+		i++
+		if i == len(data) {
+			clear(decoded)
+			return errors.New("private key ends with unescaped backslash")
+		}
+
+		switch data[i] {
+		case 'b':
+			decoded = append(decoded, '\b')
+		case 'f':
+			decoded = append(decoded, '\f')
+		case 'n':
+			decoded = append(decoded, '\n')
+		case 'r':
+			decoded = append(decoded, '\r')
+		case 't':
+			decoded = append(decoded, '\t')
+		case 'u':
+			if i+4 >= len(data) {
+				clear(decoded)
+				return errors.New("private key contains invalid unicode escape")
+			}
+
+			r, err := strconv.ParseUint(string(data[i+1:i+5]), 16, 16)
+			if err != nil {
+				clear(decoded)
+				return errors.New("private key contains invalid unicode escape")
+			}
+
+			decoded = utf8.AppendRune(decoded, rune(r))
+			i += 4
+		default:
+			decoded = append(decoded, data[i])
+		}
+	}
+
+	*key = decoded
+	return nil
+}
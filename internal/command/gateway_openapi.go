@@ -0,0 +1,181 @@
+package command
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gatewayOpenAPIVersion is version of OpenAPI specification gateway spec conforms to
+const gatewayOpenAPIVersion = "3.0.3"
+
+// GatewayOpenAPI returns OpenAPI specification of gateway endpoints in JSON. Schemas are generated from
+// request and response types, so the spec cannot drift from the endpoints.
+func GatewayOpenAPI(version string) ([]byte, error) {
+	schemas := make(map[string]any)
+	errorSchema := openAPISchema(reflect.TypeFor[GatewayError](), schemas)
+	paths := make(map[string]any)
+	for _, route := range (&Gateway{}).routes() {
+		responses := map[string]any{
+			strconv.Itoa(http.StatusOK): openAPIContent("Successful response", openAPISchema(reflect.TypeOf(route.response), schemas)),
+		}
+
+		errorCodes := []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable}
+		if route.request != nil {
+			errorCodes = append(errorCodes, http.StatusBadRequest, http.StatusRequestEntityTooLarge)
+		}
+
+		if route.authenticated {
+			errorCodes = append(errorCodes, http.StatusUnauthorized)
+		}
+
+		for _, code := range errorCodes {
+			responses[strconv.Itoa(code)] = openAPIContent(http.StatusText(code), errorSchema)
+		}
+
+		operation := map[string]any{
+			"summary":     route.summary,
+			"operationId": route.operationId,
+			"responses":   responses,
+			"parameters": []any{map[string]any{
+				"name":        "traceparent",
+				"in":          "header",
+				"description": "W3C trace context of the caller, spans of the request join its trace",
+				"schema":      map[string]any{"type": "string"},
+			}, map[string]any{
+				"name":        gatewayHeaderCorrelationId,
+				"in":          "header",
+				"description": "Correlation id sent with broker requests, generated when missing",
+				"schema":      map[string]any{"type": "string"},
+			}},
+		}
+
+		if route.request != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": openAPISchema(reflect.TypeOf(route.request), schemas)}},
+			}
+		}
+
+		if route.authenticated {
+			operation["security"] = []any{map[string]any{"bearer": []string{}}}
+		}
+
+		paths[route.path] = map[string]any{strings.ToLower(route.method): operation}
+	}
+
+	spec := map[string]any{
+		"openapi": gatewayOpenAPIVersion,
+		"info": map[string]any{
+			"title":       "Crypto Broker Gateway",
+			"description": "JSON endpoints of crypto broker operations for services that cannot use client library.",
+			"version":     version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearer": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+
+	return json.MarshalIndent(spec, "", "  ")
+}
+
+// openAPIContent returns OpenAPI response of JSON body with schema.
+func openAPIContent(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content":     map[string]any{"application/json": map[string]any{"schema": schema}},
+	}
+}
+
+// openAPISchema returns OpenAPI schema of t as encoded by encoding/json. Structs are added to schemas
+// by name and referenced. Fields are described by doc tag and restricted to values of comma separated enum tag,
+// fields without omitempty or omitzero option are required.
+func openAPISchema(t reflect.Type, schemas map[string]any) map[string]any {
+	switch {
+	case t == reflect.TypeFor[time.Duration]():
+		return map[string]any{"type": "integer", "format": "int64", "description": "Duration in nanoseconds"}
+	case t == reflect.TypeFor[time.Time]():
+		return map[string]any{"type": "string", "format": "date-time"}
+	case reflect.PointerTo(t).Implements(reflect.TypeFor[json.Unmarshaler]()):
+		// custom decoding, e.g. of private key, accepts JSON string
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Pointer:
+		return openAPISchema(t.Elem(), schemas)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+
+		return map[string]any{"type": "array", "items": openAPISchema(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": openAPISchema(t.Elem(), schemas)}
+	case reflect.Struct:
+		ref := map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+		if _, ok := schemas[t.Name()]; ok {
+			return ref
+		}
+
+		// placeholder stops recursion of self-referencing types
+		schemas[t.Name()] = nil
+		properties := make(map[string]any)
+		var required []string
+		for _, field := range reflect.VisibleFields(t) {
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || field.Anonymous || name == "-" {
+				continue
+			}
+
+			name = cmp.Or(name, field.Name)
+			schema := openAPISchema(field.Type, schemas)
+			doc := field.Tag.Get("doc")
+			if _, ok := schema["$ref"]; ok && doc != "" {
+				// siblings of reference are ignored, so it is wrapped to be described
+				schema = map[string]any{"allOf": []any{schema}}
+			}
+
+			if doc != "" {
+				schema["description"] = doc
+			}
+
+			if enum := field.Tag.Get("enum"); enum != "" {
+				schema["enum"] = strings.Split(enum, ",")
+			}
+
+			properties[name] = schema
+			if !strings.Contains(options, "omitempty") && !strings.Contains(options, "omitzero") {
+				required = append(required, name)
+			}
+		}
+
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+
+		schemas[t.Name()] = schema
+		return ref
+	}
+
+	return map[string]any{}
+}
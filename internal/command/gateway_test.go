@@ -0,0 +1,249 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+)
+
+func newTestGateway(t *testing.T) *Gateway {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tracerProvider, err := otel.NewTracerProvider(context.Background(), logger)
+	if err != nil {
+		t.Fatalf("could not instantiate tracer provider, err: %s", err.Error())
+	}
+
+	return &Gateway{
+		logger:               logger,
+		tracerProvider:       tracerProvider,
+		token:                []byte("s3cret"),
+		maxRequestSize:       64,
+		maxBenchmarkRequests: 1000,
+		version:              "v1.2.3",
+		decode:               decodeGatewayRequest,
+	}
+}
+
+func TestGateway_Requests(t *testing.T) {
+	t.Parallel()
+
+	gateway := newTestGateway(t)
+	tests := map[string]struct {
+		path    string
+		body    string
+		token   string
+		code    int
+		message string
+	}{
+		"unauthenticated": {path: gatewayPathHash, body: `{"input":"abc"}`, code: http.StatusUnauthorized, message: "bearer token"},
+		"invalid_token":   {path: gatewayPathHash, body: `{"input":"abc"}`, token: "secret", code: http.StatusUnauthorized, message: "bearer token"},
+		"too_large":       {path: gatewayPathHash, body: `{"input":"` + strings.Repeat("a", 64) + `"}`, token: "s3cret", code: http.StatusRequestEntityTooLarge, message: "exceeds 64 bytes"},
+		"invalid_json":    {path: gatewayPathHash, body: `{"input":`, token: "s3cret", code: http.StatusBadRequest, message: "invalid request body"},
+		"unknown_field":   {path: gatewayPathHash, body: `{"data":"abc"}`, token: "s3cret", code: http.StatusBadRequest, message: `unknown field "data"`},
+		"missing_input":   {path: gatewayPathHash, body: `{}`, token: "s3cret", code: http.StatusBadRequest, message: "input or input_base64 is required"},
+		"both_inputs":     {path: gatewayPathHash, body: `{"input":"a","input_base64":"YQ=="}`, token: "s3cret", code: http.StatusBadRequest, message: "only one of"},
+		"output_format":   {path: gatewayPathHash, body: `{"input":"a","output_format":"b64"}`, token: "s3cret", code: http.StatusBadRequest, message: "hex or raw"},
+		"missing_key":     {path: gatewayPathSignCertificate, body: `{"csr":"a","ca_cert":"b"}`, token: "s3cret", code: http.StatusBadRequest, message: "are required"},
+		"encoding":        {path: gatewayPathSignCertificate, body: `{"csr":"a","ca_cert":"b","ca_private_key":"c","encoding":"b64"}`, token: "s3cret", code: http.StatusBadRequest, message: "pem or der"},
+		"benchmark_limit": {path: gatewayPathBenchmark, body: `{"concurrency":[1,2],"requests":600}`, token: "s3cret", code: http.StatusBadRequest, message: "exceeds limit of 1000 requests"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			request := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(test.body))
+			if test.token != "" {
+				request.Header.Set("Authorization", "Bearer "+test.token)
+			}
+
+			recorder := httptest.NewRecorder()
+			gateway.handler().ServeHTTP(recorder, request)
+			if recorder.Code != test.code {
+				t.Fatalf("expected status %d, got %d: %s", test.code, recorder.Code, recorder.Body.String())
+			}
+
+			var response GatewayError
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("expected JSON error response, got %q", recorder.Body.String())
+			}
+
+			if !strings.Contains(response.Error, test.message) {
+				t.Fatalf("expected error containing %q, got %q", test.message, response.Error)
+			}
+		})
+	}
+}
+
+func TestGateway_SignCertificateZeroesKey(t *testing.T) {
+	t.Parallel()
+
+	gateway := newTestGateway(t)
+	var request *GatewaySignCertificateRequest
+	gateway.decode = func(body []byte, decoded any) error {
+		request = decoded.(*GatewaySignCertificateRequest)
+		return decodeGatewayRequest(body, decoded)
+	}
+
+	for name, body := range map[string]string{
+		"invalid_body":     `{"ca_private_key":"private-key","data":"abc"}`,
+		"invalid_encoding": `{"csr":"a","ca_cert":"b","ca_private_key":"private-key","encoding":"b64"}`,
+	} {
+		if code, _ := gateway.serveSignCertificate(context.Background(), []byte(body)); code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status %d, got %d", name, http.StatusBadRequest, code)
+		}
+
+		if len(request.CAPrivateKey) != len("private-key") || !bytes.Equal(request.CAPrivateKey, make([]byte, len("private-key"))) {
+			t.Fatalf("%s: expected decoded private key zeroed, got %q", name, request.CAPrivateKey)
+		}
+	}
+}
+
+func TestGateway_TraceContext(t *testing.T) {
+	t.Parallel()
+
+	gateway := newTestGateway(t)
+	request := httptest.NewRequest(http.MethodPost, gatewayPathHash, strings.NewReader(`{}`))
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	request.Header.Set(gatewayHeaderCorrelationId, "request-1")
+
+	recorder := httptest.NewRecorder()
+	gateway.handler().ServeHTTP(recorder, request)
+	traceparent := recorder.Header().Get("traceparent")
+	if !strings.HasPrefix(traceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-") || strings.Contains(traceparent, "00f067aa0ba902b7") {
+		t.Fatalf("expected traceparent of child span in trace of request, got %q", traceparent)
+	}
+
+	if correlationId := recorder.Header().Get(gatewayHeaderCorrelationId); correlationId != "request-1" {
+		t.Fatalf("expected correlation id of request, got %q", correlationId)
+	}
+
+	// correlation id is generated when request has none
+	recorder = httptest.NewRecorder()
+	gateway.handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, gatewayPathHash, strings.NewReader(`{}`)))
+	if recorder.Header().Get(gatewayHeaderCorrelationId) == "" || recorder.Header().Get("traceparent") == "" {
+		t.Fatalf("expected generated correlation id and traceparent, got %v", recorder.Header())
+	}
+}
+
+func TestGateway_OpenAPI(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	newTestGateway(t).handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, gatewayPathOpenAPI, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	var spec struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any `json:"properties"`
+				Required   []string                  `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if spec.Info.Version != "v1.2.3" {
+		t.Fatalf("expected version v1.2.3, got %q", spec.Info.Version)
+	}
+
+	for path, method := range map[string]string{
+		gatewayPathHash:            "post",
+		gatewayPathSignCertificate: "post",
+		gatewayPathHealth:          "get",
+		gatewayPathBenchmark:       "post",
+	} {
+		if _, ok := spec.Paths[path][method]; !ok {
+			t.Fatalf("expected %s operation of %s, got %v", method, path, spec.Paths[path])
+		}
+	}
+
+	signRequest := spec.Components.Schemas["GatewaySignCertificateRequest"]
+	if key := signRequest.Properties["ca_private_key"]; key["type"] != "string" {
+		t.Fatalf("expected string private key, got %v", key)
+	}
+
+	if encoding := signRequest.Properties["encoding"]; len(encoding["enum"].([]any)) != 2 {
+		t.Fatalf("expected encoding enum, got %v", encoding)
+	}
+
+	if strings.Join(signRequest.Required, ",") != "csr,ca_cert,ca_private_key" {
+		t.Fatalf("expected required csr, ca_cert and ca_private_key, got %q", signRequest.Required)
+	}
+}
+
+func TestGatewayKey_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		json string
+		key  string
+		err  bool
+	}{
+		"pem":     {json: `"-----BEGIN KEY-----\nabc\/dA\"\n-----END KEY-----"`, key: "-----BEGIN KEY-----\nabc/dA\"\n-----END KEY-----"},
+		"null":    {json: `null`},
+		"number":  {json: `1`, err: true},
+		"unicode": {json: `"é"`, key: "é"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var request struct {
+				Key gatewayKey `json:"key"`
+			}
+
+			err := json.Unmarshal([]byte(`{"key":`+test.json+`}`), &request)
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+
+			if !bytes.Equal(request.Key, []byte(test.key)) {
+				t.Fatalf("expected key %q, got %q", test.key, request.Key)
+			}
+		})
+	}
+}
+
+func TestGateway_BenchmarkMatrix(t *testing.T) {
+	t.Parallel()
+
+	gateway := newTestGateway(t)
+	matrix, err := gateway.benchmarkMatrix(GatewayBenchmarkRequest{})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(benchClientCells(matrix)) != 1 || matrix.Operations[0] != constant.BenchOperationHash || matrix.Sizes[0] != 1024 ||
+		matrix.Requests != constant.DefaultBenchClientRequests {
+		t.Fatalf("expected single hash combination of 1KiB input, got %+v", matrix)
+	}
+
+	for name, request := range map[string]GatewayBenchmarkRequest{
+		"operation": {Operations: []string{"encrypt"}},
+		"size":      {Sizes: []string{"1TiB"}},
+		"grpc_size": {Sizes: []string{"1KiB", "5MiB"}},
+		"requests":  {Requests: -1},
+	} {
+		if _, err := gateway.benchmarkMatrix(request); err == nil {
+			t.Fatalf("%s: expected error, got nil", name)
+		}
+	}
+}
@@ -14,6 +14,8 @@ import (
 
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
 	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -23,22 +25,27 @@ import (
 
 // HashData represents command that repeatedly sends hash request to crypto broker and displays its response
 type HashData struct {
-	logger              *slog.Logger
-	cryptoBrokerLibrary *cryptobrokerclientgo.Library
-	tracerProvider      *otel.TracerProvider
+	logger         *slog.Logger
+	libraries      *pool.Pool
+	tracerProvider *otel.TracerProvider
+	retryPolicy    retry.Policy
 }
 
 // NewHashData initializes hash command
-func NewHashData(ctx context.Context, lib *cryptobrokerclientgo.Library, logger *slog.Logger, tracerProvider *otel.TracerProvider) (*HashData, error) {
+func NewHashData(ctx context.Context, libraries *pool.Pool, logger *slog.Logger, tracerProvider *otel.TracerProvider, retryPolicy retry.Policy) (*HashData, error) {
 	return &HashData{
-		logger:              logger,
-		cryptoBrokerLibrary: lib,
-		tracerProvider:      tracerProvider,
+		logger:         logger,
+		libraries:      libraries,
+		tracerProvider: tracerProvider,
+		retryPolicy:    retryPolicy,
 	}, nil
 }
 
 // Run executes command logic.
-func (command *HashData) Run(ctx context.Context, input []byte, flagOutputFormat string, flagProfile string, flagLoop int) error {
+// Requests short-circuited by an open circuit breaker are logged and counted. In loop mode they stop the loop
+// only when flagFailOnCircuitOpen is set. Returned error wraps cryptobrokerclientgo.ErrCircuitOpen
+// if the final request was short-circuited.
+func (command *HashData) Run(ctx context.Context, input []byte, flagOutputFormat string, flagProfile string, flagLoop int, flagFailOnCircuitOpen bool) error {
 	defer func() { _ = command.gracefulShutdown() }()
 
 	payload := cryptobrokerclientgo.HashDataPayload{
@@ -53,7 +60,7 @@ func (command *HashData) Run(ctx context.Context, input []byte, flagOutputFormat
 		payload.OutputFormat = cryptobrokerclientgo.OutputFormatHex
 	}
 
-	command.logger.Info("Hashing input", "input", string(input), "profile", flagProfile)
+	command.logger.InfoContext(ctx, "Hashing input", "request", hashDataPayloadLog(payload))
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
@@ -64,38 +71,38 @@ func (command *HashData) Run(ctx context.Context, input []byte, flagOutputFormat
 			panic(err)
 		}
 
+		var stats loopStats
+		defer stats.logSummary(command.logger, "Hash loop summary")
+
+		var lastErr error
 		for {
 			select {
 			case <-c:
-				command.logger.Info("Received SIGTERM signal")
-				return nil
+				command.logger.InfoContext(ctx, "Received SIGTERM signal")
+				return lastErr
 			default:
-				err := command.hashBytes(ctx, payload)
-				if err != nil && !errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen) {
-					return err
+				_, lastErr = command.hashBytes(ctx, payload)
+				stats.record(lastErr)
+				if lastErr != nil && (!errors.Is(lastErr, cryptobrokerclientgo.ErrCircuitOpen) || flagFailOnCircuitOpen) {
+					return lastErr
 				}
 
 				time.Sleep(toSleep)
 			}
 		}
 	} else {
-		err := command.hashBytes(ctx, payload)
-		if err != nil && !errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen) {
-			return err
-		}
-		return nil
+		_, err := command.hashBytes(ctx, payload)
+		return err
 	}
 }
 
 // hashBytes sends hash request through crypto broker library.
-// In case of success it displays response and returns nil error, otherwise it returns non-nil error.
+// In case of success it displays response and returns it with nil error, otherwise it returns non-nil error.
+// Requests short-circuited by an open circuit breaker return cryptobrokerclientgo.ErrCircuitOpen.
 // Internally method measures execution time and prints it through logger.
-func (command *HashData) hashBytes(ctx context.Context, payload cryptobrokerclientgo.HashDataPayload) error {
+func (command *HashData) hashBytes(ctx context.Context, payload cryptobrokerclientgo.HashDataPayload) (hashDataResponse, error) {
 	tracer := command.tracerProvider.GetTracer("crypto-broker-cli-go")
-	correlationId := ""
-	if payload.Metadata != nil && payload.Metadata.TraceContext != nil {
-		correlationId = payload.Metadata.TraceContext.CorrelationId
-	}
+	correlationId := correlationIdOf(ctx, payload.Metadata)
 
 	ctx, span := tracer.Start(ctx, "CLI.HashData",
 		trace.WithAttributes(
@@ -106,27 +113,27 @@ func (command *HashData) hashBytes(ctx context.Context, payload cryptobrokerclie
 		))
 	defer span.End()
 
-	// Inject trace context into payload metadata
-	spanContext := span.SpanContext()
+	// Metadata id is shared by all attempts, trace context is injected per attempt
 	if payload.Metadata == nil {
 		payload.Metadata = &cryptobrokerclientgo.Metadata{
 			Id: uuid.New().String(),
 		}
 	}
-	payload.Metadata.TraceContext = &cryptobrokerclientgo.TraceContext{
-		TraceId:       spanContext.TraceID().String(),
-		SpanId:        spanContext.SpanID().String(),
-		TraceFlags:    spanContext.TraceFlags().String(),
-		TraceState:    spanContext.TraceState().String(),
-		CorrelationId: correlationId,
-	}
+
 	timestampHashingStart := time.Now()
-	responseBody, err := command.cryptoBrokerLibrary.HashData(ctx, payload)
+	responseBody, err := retry.Do(ctx, command.retryPolicy, tracer, "CLI.HashData.Attempt", payload,
+		func(ctx context.Context) { setTraceContext(ctx, payload.Metadata, correlationId) },
+		otel.MeasureAttempts(otel.OperationHashData, payload.Profile, len(payload.Input),
+			pool.Bind(command.libraries, (*cryptobrokerclientgo.Library).HashData)))
+	if errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen) {
+		recordCircuitOpen(ctx, command.logger, span, "HashData")
+		return nil, err
+	}
 
-	if err != nil && !errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen) {
+	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return err
+		return nil, err
 	}
 
 	if responseBody != nil {
@@ -145,17 +152,21 @@ func (command *HashData) hashBytes(ctx context.Context, payload cryptobrokerclie
 		)
 		span.SetStatus(codes.Ok, "Hash operation completed successfully")
 
-		command.logger.Info("Hashed response", "response", responseBody)
-		command.logger.Info(
+		command.logger.InfoContext(ctx, "Hashed response", "response", hashDataResponseLog{response: responseBody})
+		command.logger.InfoContext(ctx,
 			fmt.Sprintf("Data Hashing took %d µs", durationElapsedHashing.Microseconds()),
 		)
+
+		return responseBody, nil
 	}
 
-	return nil
+	return nil, nil
 }
 
-// gracefulShutdown closes library connection.
+// gracefulShutdown closes library connections once requests in flight complete.
 func (command *HashData) gracefulShutdown() error {
-	command.logger.Info("Closing crypto broker library connection")
-	return command.cryptoBrokerLibrary.Close()
+	command.logger.Info("Closing crypto broker library connections")
+	ctx, cancel := context.WithTimeout(context.Background(), constant.PoolDrainTimeout)
+	defer cancel()
+	return command.libraries.Shutdown(ctx)
 }
@@ -9,6 +9,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
 	cryptobroker "github.com/open-crypto-broker/crypto-broker-client-go"
 )
 
@@ -25,12 +27,12 @@ func BenchmarkHashData_profile_Default_Sequential(b *testing.B) {
 	if err != nil {
 		b.Fatalf("could not instantiate tracer provider, err: %s", err.Error())
 	}
-	lib, err := cryptobroker.NewLibrary(ctx)
+	libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 	if err != nil {
 		b.Fatalf("could not instantiate library, err: %s", err.Error())
 	}
 
-	hashCmd, err := NewHashData(ctx, lib, logger, tracerProvider)
+	hashCmd, err := NewHashData(ctx, libraries, logger, tracerProvider, retry.Policy{})
 	if err != nil {
 		b.Fatalf("could not instantiate hash, err: %s", err.Error())
 	}
@@ -46,7 +48,7 @@ func BenchmarkHashData_profile_Default_Sequential(b *testing.B) {
 	}
 
 	for b.Loop() {
-		err := hashCmd.hashBytes(ctx, payload)
+		_, err := hashCmd.hashBytes(ctx, payload)
 		if err != nil && !errors.Is(err, cryptobroker.ErrCircuitOpen) {
 			b.Fatalf("could not run hash, err: %s", err.Error())
 		}
@@ -68,12 +70,12 @@ func BenchmarkHashData_profile_Default_Parallel(b *testing.B) {
 	}
 
 	b.RunParallel(func(p *testing.PB) {
-		lib, err := cryptobroker.NewLibrary(ctx)
+		libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 		if err != nil {
 			b.Fatalf("could not instantiate library, err: %s", err.Error())
 		}
 
-		hashCmd, err := NewHashData(ctx, lib, logger, tracerProvider)
+		hashCmd, err := NewHashData(ctx, libraries, logger, tracerProvider, retry.Policy{})
 		if err != nil {
 			b.Fatalf("could not instantiate hash, err: %s", err.Error())
 		}
@@ -89,7 +91,7 @@ func BenchmarkHashData_profile_Default_Parallel(b *testing.B) {
 		}
 
 		for p.Next() {
-			err := hashCmd.hashBytes(ctx, payload)
+			_, err := hashCmd.hashBytes(ctx, payload)
 
 			if err != nil && !errors.Is(err, cryptobroker.ErrCircuitOpen) {
 				b.Fatalf("could not run hash, err: %s", err.Error())
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -11,90 +12,213 @@ import (
 	"time"
 
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/correlation"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
 	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// Errors returned when broker health status is other than SERVING.
+var (
+	ErrHealthNotServing    = errors.New("broker is not serving")
+	ErrHealthStatusUnknown = errors.New("broker health status is unknown")
+)
+
+// errHealthStatusUnknown is reported to retry policy when health check returns UNKNOWN status.
+var errHealthStatusUnknown = status.Error(grpccodes.Unavailable, "health status is UNKNOWN")
+
 // Health represents command that checks broker server health status
 type Health struct {
-	logger              *slog.Logger
-	cryptoBrokerLibrary *cryptobrokerclientgo.Library
-	tracerProvider      *otel.TracerProvider
+	logger         *slog.Logger
+	libraries      *pool.Pool
+	tracerProvider *otel.TracerProvider
+	retryPolicy    retry.Policy
 }
 
 // NewHealth initializes health command
-func NewHealth(ctx context.Context, lib *cryptobrokerclientgo.Library, logger *slog.Logger, tracerProvider *otel.TracerProvider) (*Health, error) {
+func NewHealth(ctx context.Context, libraries *pool.Pool, logger *slog.Logger, tracerProvider *otel.TracerProvider, retryPolicy retry.Policy) (*Health, error) {
 	return &Health{
-		logger:              logger,
-		cryptoBrokerLibrary: lib,
-		tracerProvider:      tracerProvider,
+		logger:         logger,
+		libraries:      libraries,
+		tracerProvider: tracerProvider,
+		retryPolicy:    retryPolicy,
 	}, nil
 }
 
 // Run executes command logic.
-func (command *Health) Run(ctx context.Context, flagLoop int) error {
+// Returned error wraps ErrHealthNotServing or ErrHealthStatusUnknown if the final check did not report SERVING status.
+// With flagWait it polls every flagInterval until the broker is SERVING or ctx is done.
+func (command *Health) Run(ctx context.Context, flagLoop int, flagWait bool, flagInterval time.Duration) error {
 	defer func() { _ = command.gracefulShutdown() }()
 
-	command.logger.Info("Checking broker server health")
+	command.logger.InfoContext(ctx, "Checking broker server health")
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
+	if flagWait {
+		return command.waitServing(ctx, c, flagInterval)
+	}
+
 	if flagLoop >= constant.MinLoopFlagValue && flagLoop <= constant.MaxLoopFlagValue {
 		toSleep, err := time.ParseDuration(fmt.Sprintf("%dms", flagLoop))
 		if err != nil {
 			panic(err)
 		}
 
+		var stats loopStats
+		defer stats.logSummary(command.logger, "Health loop summary")
+
+		var lastErr error
 		for {
 			select {
 			case <-c:
-				command.logger.Info("Received SIGTERM signal")
-				return nil
+				command.logger.InfoContext(ctx, "Received SIGTERM signal")
+				return lastErr
 			default:
-				err := command.checkHealth(ctx)
-				if err != nil && !errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen) {
-					return err
-				}
-
+				_, lastErr = command.checkHealth(ctx)
+				stats.record(lastErr)
 				time.Sleep(toSleep)
 			}
 		}
 	} else {
-		err := command.checkHealth(ctx)
-		if err != nil && !errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen) {
+		_, err := command.checkHealth(ctx)
+		return err
+	}
+}
+
+// RunNagios performs single health check and writes monitoring plugin result line to w.
+// Returned exit code follows monitoring plugin conventions, see nagiosResult.
+func (command *Health) RunNagios(ctx context.Context, w io.Writer, flagWarning time.Duration, flagCritical time.Duration) (int, error) {
+	defer func() { _ = command.gracefulShutdown() }()
+
+	durationElapsed, err := command.checkHealth(ctx)
+	status := cryptobrokerclientgo.StatusServing
+	switch {
+	case errors.Is(err, ErrHealthNotServing):
+		status = cryptobrokerclientgo.StatusNotServing
+	case err != nil:
+		status = cryptobrokerclientgo.StatusUnknown
+	}
+
+	exitCode, line := nagiosResult(status, durationElapsed, flagWarning, flagCritical)
+	if _, err := fmt.Fprintln(w, line); err != nil {
+		return constant.ExitCodeNagiosUnknown, fmt.Errorf("failed to write monitoring plugin output, err: %w", err)
+	}
+
+	return exitCode, nil
+}
+
+// waitServing polls broker health until it reports SERVING status, ctx is done or signal is received.
+func (command *Health) waitServing(ctx context.Context, c <-chan os.Signal, interval time.Duration) error {
+	timestampStart := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for attempt := 1; ; attempt++ {
+		_, err := command.checkHealth(ctx)
+		if err == nil {
+			command.logger.InfoContext(ctx, "Broker is serving", "attempts", attempt, "waited_ms", time.Since(timestampStart).Milliseconds())
+			return nil
+		}
+
+		if !isHealthStatusError(err) {
 			return err
 		}
-		return nil
+
+		select {
+		case <-c:
+			command.logger.InfoContext(ctx, "Received SIGTERM signal")
+			return err
+		case <-ctx.Done():
+			return fmt.Errorf("broker did not become serving after %d attempts: %w", attempt, err)
+		case <-ticker.C:
+		}
 	}
 }
 
 // checkHealth sends health check request through crypto broker library.
-// In case of success it displays response and returns nil error, otherwise it returns non-nil error.
-// Internally method measures execution time and prints it through logger.
-func (command *Health) checkHealth(ctx context.Context) error {
+// It displays response and returns nil error for SERVING status, otherwise it returns
+// ErrHealthNotServing or ErrHealthStatusUnknown.
+// Internally method measures execution time, prints it through logger and returns it.
+func (command *Health) checkHealth(ctx context.Context) (time.Duration, error) {
 	tracer := command.tracerProvider.GetTracer("crypto-broker-cli-go")
 	ctx, span := tracer.Start(ctx, "CLI.Health",
-		trace.WithAttributes(otel.AttributeRpcMethod.String("Health")))
+		trace.WithAttributes(
+			otel.AttributeRpcMethod.String("Health"),
+			otel.AttributeCorrelationId.String(correlation.IDFromContext(ctx)),
+		))
 	defer span.End()
 
+	// HealthData does not return errors, requests short-circuited by an open circuit breaker
+	// are reported as UNKNOWN status and cannot be told apart from other failures here.
 	timestampStart := time.Now()
-	responseBody := command.cryptoBrokerLibrary.HealthData(ctx)
+	responseBody, _ := retry.Do(ctx, command.retryPolicy, tracer, "CLI.Health.Attempt", struct{}{}, nil, command.healthData)
 	timestampFinish := time.Now()
 	durationElapsed := timestampFinish.Sub(timestampStart)
 
-	span.SetStatus(codes.Ok, "Health check completed successfully")
+	span.SetAttributes(otel.AttributeHealthStatus.String(responseBody.Status))
+
+	command.logger.InfoContext(ctx, "Health check response", "response", responseBody)
+	command.logger.InfoContext(ctx, "Health check took", "duration_microseconds", float64(durationElapsed.Nanoseconds())/1000.0)
 
-	command.logger.Info("Health check response", "response", responseBody)
-	command.logger.Info("Health check took", "duration_microseconds", float64(durationElapsed.Nanoseconds())/1000.0)
+	var err error
+	switch responseBody.Status {
+	case cryptobrokerclientgo.StatusServing:
+		span.SetStatus(codes.Ok, "Health check completed successfully")
+		return durationElapsed, nil
+	case cryptobrokerclientgo.StatusNotServing:
+		err = ErrHealthNotServing
+	default:
+		err = ErrHealthStatusUnknown
+	}
+
+	span.SetStatus(codes.Error, err.Error())
+	return durationElapsed, err
+}
+
+// healthData adapts library health check to retry.Do. Since HealthData does not return errors,
+// UNKNOWN status is reported as codes.Unavailable, so it is retried when the policy retries on UNAVAILABLE.
+// Every attempt is recorded in request metrics, NOT_SERVING status as failed request.
+func (command *Health) healthData(ctx context.Context, _ struct{}) (*cryptobrokerclientgo.HealthDataResponse, error) {
+	finishRequest := otel.StartRequest(ctx, otel.OperationHealth, "", 0)
+
+	// pool without healthy library is reported like failed health check of the library
+	lib, release, err := command.libraries.Acquire(ctx)
+	if err != nil {
+		finishRequest(nil, err)
+		return &cryptobrokerclientgo.HealthDataResponse{Status: cryptobrokerclientgo.StatusUnknown}, err
+	}
+	defer release()
+
+	responseBody := lib.HealthData(ctx)
+	switch responseBody.Status {
+	case cryptobrokerclientgo.StatusServing:
+		finishRequest(responseBody, nil)
+	case cryptobrokerclientgo.StatusNotServing:
+		finishRequest(responseBody, ErrHealthNotServing)
+	default:
+		finishRequest(responseBody, errHealthStatusUnknown)
+		return responseBody, errHealthStatusUnknown
+	}
+
+	return responseBody, nil
+}
 
-	return nil
+// isHealthStatusError reports whether err describes health status other than SERVING.
+func isHealthStatusError(err error) bool {
+	return errors.Is(err, ErrHealthNotServing) || errors.Is(err, ErrHealthStatusUnknown)
 }
 
-// gracefulShutdown closes library connection.
+// gracefulShutdown closes library connections once requests in flight complete.
 func (command *Health) gracefulShutdown() error {
-	command.logger.Info("Closing crypto broker library connection")
-	return command.cryptoBrokerLibrary.Close()
+	command.logger.Info("Closing crypto broker library connections")
+	ctx, cancel := context.WithTimeout(context.Background(), constant.PoolDrainTimeout)
+	defer cancel()
+	return command.libraries.Shutdown(ctx)
 }
@@ -0,0 +1,241 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+)
+
+// paths of health probe endpoints
+const (
+	healthServePathLive    = "/livez"
+	healthServePathReady   = "/readyz"
+	healthServePathHealthz = "/healthz"
+)
+
+// healthServeShutdownTimeout bounds graceful shutdown of the HTTP server.
+const healthServeShutdownTimeout = 5 * time.Second
+
+// HealthProbeStatus is JSON detail returned by health probe endpoints.
+type HealthProbeStatus struct {
+	Status              string    `json:"status"`
+	Live                bool      `json:"live"`
+	Ready               bool      `json:"ready"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	FailureThreshold    int       `json:"failure_threshold"`
+	Checks              int       `json:"checks"`
+	Failures            int       `json:"failures"`
+	LatencyMs           float64   `json:"latency_ms"`
+	LastCheck           time.Time `json:"last_check,omitzero"`
+	LastSuccess         time.Time `json:"last_success,omitzero"`
+	Error               string    `json:"error,omitempty"`
+}
+
+// HealthServe represents command that exposes broker health as HTTP probe endpoints
+type HealthServe struct {
+	logger *slog.Logger
+	health *Health
+
+	mu               sync.RWMutex
+	state            HealthProbeStatus
+	cacheTTL         time.Duration
+	failureThreshold int
+	started          time.Time
+	now              func() time.Time
+}
+
+// NewHealthServe initializes health serve command
+func NewHealthServe(ctx context.Context, libraries *pool.Pool, logger *slog.Logger, tracerProvider *otel.TracerProvider, retryPolicy retry.Policy) (*HealthServe, error) {
+	health, err := NewHealth(ctx, libraries, logger, tracerProvider, retryPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HealthServe{
+		logger: logger,
+		health: health,
+		state:  HealthProbeStatus{Status: cryptobrokerclientgo.StatusUnknown},
+		now:    time.Now,
+	}, nil
+}
+
+// Run executes command logic.
+// It checks broker health every flagInterval and serves cached result on probe endpoints until SIGTERM is received.
+// Readiness fails after flagFailureThreshold consecutive non-SERVING checks, liveness fails when no check
+// completed within flagCacheTTL.
+func (command *HealthServe) Run(ctx context.Context, flagListen string, flagInterval time.Duration, flagCacheTTL time.Duration, flagFailureThreshold int) error {
+	defer func() { _ = command.health.gracefulShutdown() }()
+
+	command.cacheTTL = flagCacheTTL
+	command.failureThreshold = flagFailureThreshold
+	command.state.FailureThreshold = flagFailureThreshold
+	command.started = command.now()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	server := &http.Server{
+		Addr:              flagListen,
+		Handler:           command.handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		command.logger.InfoContext(ctx, "Serving health probes", "listen", flagListen,
+			"paths", []string{healthServePathLive, healthServePathReady, healthServePathHealthz})
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	go command.checkPeriodically(ctx, flagInterval)
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	var err error
+	select {
+	case <-c:
+		command.logger.InfoContext(ctx, "Received SIGTERM signal")
+	case <-ctx.Done():
+	case err = <-serverErr:
+		err = fmt.Errorf("health probe server failed, err: %w", err)
+	}
+
+	cancel()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), healthServeShutdownTimeout)
+	defer cancelShutdown()
+
+	return errors.Join(err, server.Shutdown(shutdownCtx))
+}
+
+// checkPeriodically checks broker health immediately and then every interval until ctx is done.
+func (command *HealthServe) checkPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		command.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check performs single health check bounded by cache TTL and updates cached state.
+func (command *HealthServe) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, command.cacheTTL)
+	defer cancel()
+
+	durationElapsed, err := command.health.checkHealth(ctx)
+
+	status := cryptobrokerclientgo.StatusServing
+	switch {
+	case errors.Is(err, ErrHealthNotServing):
+		status = cryptobrokerclientgo.StatusNotServing
+	case err != nil:
+		status = cryptobrokerclientgo.StatusUnknown
+	}
+
+	command.record(status, err, command.now(), durationElapsed)
+}
+
+// record stores result of a health check.
+func (command *HealthServe) record(status string, err error, checkedAt time.Time, latency time.Duration) {
+	command.mu.Lock()
+	defer command.mu.Unlock()
+
+	command.state.Status = status
+	command.state.Checks++
+	command.state.LastCheck = checkedAt
+	command.state.LatencyMs = float64(latency.Nanoseconds()) / 1e6
+	command.state.Error = ""
+	if status == cryptobrokerclientgo.StatusServing {
+		command.state.ConsecutiveFailures = 0
+		command.state.LastSuccess = checkedAt
+		return
+	}
+
+	command.state.Failures++
+	command.state.ConsecutiveFailures++
+	if err != nil {
+		command.state.Error = err.Error()
+	}
+}
+
+// snapshot returns cached state with liveness and readiness evaluated at current time.
+// Liveness holds during the first cache TTL after start, before the first check completes.
+func (command *HealthServe) snapshot() HealthProbeStatus {
+	command.mu.RLock()
+	state := command.state
+	command.mu.RUnlock()
+
+	now := command.now()
+	fresh := !state.LastCheck.IsZero() && now.Sub(state.LastCheck) <= command.cacheTTL
+	starting := state.LastCheck.IsZero() && now.Sub(command.started) <= command.cacheTTL
+	state.Live = fresh || starting
+	state.Ready = fresh && !state.LastSuccess.IsZero() && state.ConsecutiveFailures < command.failureThreshold
+	if !fresh && state.Error == "" && !state.LastCheck.IsZero() {
+		state.Error = fmt.Sprintf("last health check is older than %s", command.cacheTTL)
+	}
+
+	return state
+}
+
+// handler builds HTTP handler serving probe endpoints.
+func (command *HealthServe) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+healthServePathLive, func(w http.ResponseWriter, r *http.Request) {
+		state := command.snapshot()
+		command.respond(w, r, state, state.Live, false)
+	})
+	mux.HandleFunc("GET "+healthServePathReady, func(w http.ResponseWriter, r *http.Request) {
+		state := command.snapshot()
+		command.respond(w, r, state, state.Ready, false)
+	})
+	mux.HandleFunc("GET "+healthServePathHealthz, func(w http.ResponseWriter, r *http.Request) {
+		state := command.snapshot()
+		command.respond(w, r, state, state.Live && state.Ready, true)
+	})
+
+	return mux
+}
+
+// respond writes probe result. JSON detail is written when detail is set or verbose query parameter is present.
+func (command *HealthServe) respond(w http.ResponseWriter, r *http.Request, state HealthProbeStatus, ok bool, detail bool) {
+	code := http.StatusOK
+	if !ok {
+		code = http.StatusServiceUnavailable
+	}
+
+	if !detail && !r.URL.Query().Has("verbose") {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(code)
+		_, _ = fmt.Fprintln(w, http.StatusText(code))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(state); err != nil {
+		command.logger.WarnContext(r.Context(), "Failed to write health probe response", "error", err)
+	}
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+)
+
+func TestHealthServe_Probes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newServe := func() *HealthServe {
+		return &HealthServe{
+			logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+			state:            HealthProbeStatus{Status: cryptobrokerclientgo.StatusUnknown},
+			cacheTTL:         15 * time.Second,
+			failureThreshold: 2,
+			started:          now,
+			now:              func() time.Time { return now },
+		}
+	}
+
+	probe := func(t *testing.T, serve *HealthServe, path string) int {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		serve.handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder.Code
+	}
+
+	t.Run("starting", func(t *testing.T) {
+		t.Parallel()
+		serve := newServe()
+		if code := probe(t, serve, healthServePathLive); code != http.StatusOK {
+			t.Fatalf("expected live during startup, got %d", code)
+		}
+
+		if code := probe(t, serve, healthServePathReady); code != http.StatusServiceUnavailable {
+			t.Fatalf("expected not ready before first check, got %d", code)
+		}
+	})
+
+	t.Run("failure_threshold", func(t *testing.T) {
+		t.Parallel()
+		serve := newServe()
+		serve.record(cryptobrokerclientgo.StatusServing, nil, now, time.Millisecond)
+		serve.record(cryptobrokerclientgo.StatusNotServing, ErrHealthNotServing, now, time.Millisecond)
+		if code := probe(t, serve, healthServePathReady); code != http.StatusOK {
+			t.Fatalf("expected ready below failure threshold, got %d", code)
+		}
+
+		serve.record(cryptobrokerclientgo.StatusUnknown, errors.New("unknown"), now, time.Millisecond)
+		if code := probe(t, serve, healthServePathReady); code != http.StatusServiceUnavailable {
+			t.Fatalf("expected not ready at failure threshold, got %d", code)
+		}
+
+		if code := probe(t, serve, healthServePathLive); code != http.StatusOK {
+			t.Fatalf("expected live with fresh check, got %d", code)
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		t.Parallel()
+		serve := newServe()
+		serve.record(cryptobrokerclientgo.StatusServing, nil, now.Add(-time.Minute), time.Millisecond)
+		if code := probe(t, serve, healthServePathLive); code != http.StatusServiceUnavailable {
+			t.Fatalf("expected not live with stale check, got %d", code)
+		}
+
+		recorder := httptest.NewRecorder()
+		serve.handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, healthServePathHealthz, nil))
+		if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+			t.Fatalf("expected JSON detail, got %q", contentType)
+		}
+	})
+}
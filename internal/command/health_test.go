@@ -7,7 +7,8 @@ import (
 	"testing"
 
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
-	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
 )
 
 func BenchmarkHealth_Sequential(b *testing.B) {
@@ -23,17 +24,17 @@ func BenchmarkHealth_Sequential(b *testing.B) {
 	if err != nil {
 		b.Fatalf("could not instantiate tracer provider, err: %s", err.Error())
 	}
-	lib, err := cryptobrokerclientgo.NewLibrary(ctx)
+	libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 	if err != nil {
 		b.Fatalf("could not instantiate library, err: %s", err.Error())
 	}
-	healthCmd, err := NewHealth(ctx, lib, logger, tracerProvider)
+	healthCmd, err := NewHealth(ctx, libraries, logger, tracerProvider, retry.Policy{})
 	if err != nil {
 		b.Fatalf("could not instantiate health, err: %s", err.Error())
 	}
 
 	for b.Loop() {
-		err := healthCmd.checkHealth(ctx)
+		_, err := healthCmd.checkHealth(ctx)
 		if err != nil {
 			b.Fatalf("could not run health, err: %s", err.Error())
 		}
@@ -54,17 +55,17 @@ func BenchmarkHealth_Parallel(b *testing.B) {
 		b.Fatalf("could not instantiate tracer provider, err: %s", err.Error())
 	}
 	b.RunParallel(func(p *testing.PB) {
-		lib, err := cryptobrokerclientgo.NewLibrary(ctx)
+		libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 		if err != nil {
 			b.Fatalf("could not instantiate library, err: %s", err.Error())
 		}
-		healthCmd, err := NewHealth(ctx, lib, logger, tracerProvider)
+		healthCmd, err := NewHealth(ctx, libraries, logger, tracerProvider, retry.Policy{})
 		if err != nil {
 			b.Fatalf("could not instantiate health, err: %s", err.Error())
 		}
 
 		for p.Next() {
-			err := healthCmd.checkHealth(ctx)
+			_, err := healthCmd.checkHealth(ctx)
 			if err != nil {
 				b.Fatalf("could not run health, err: %s", err.Error())
 			}
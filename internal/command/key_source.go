@@ -0,0 +1,103 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrInsecureKeyPermissions is returned when private key file is readable by group or others.
+var ErrInsecureKeyPermissions = errors.New("private key file is accessible by group or others")
+
+// prefixes of private key sources other than files
+const (
+	keySourceStdin     = "-"
+	keySourceFdPrefix  = "fd:"
+	keySourceEnvPrefix = "env:"
+)
+
+// readKeyBytes reads private key from source, which is path of file, "-" for stdin, "fd:N" for file descriptor
+// inherited from parent process or "env:NAME" for environment variable, which is unset once read.
+// Files accessible by group or others are refused, unless allowInsecure is set, in which case only warning is logged.
+// Returned buffer should be zeroed once key is no longer needed, intermediate buffers are zeroed while reading.
+// Zeroing is partial: key of environment variable remains in the initial process environment and in the string
+// it was looked up as, and client library copies the key to request string, neither can be zeroed.
+func readKeyBytes(logger *slog.Logger, source string, allowInsecure bool) ([]byte, error) {
+	switch {
+	case source == keySourceStdin:
+		return readAllZeroing(os.Stdin)
+	case strings.HasPrefix(source, keySourceFdPrefix):
+		fd, err := strconv.Atoi(strings.TrimPrefix(source, keySourceFdPrefix))
+		if err != nil || fd < 0 {
+			return nil, fmt.Errorf("invalid key file descriptor %q", source)
+		}
+
+		f := os.NewFile(uintptr(fd), source)
+		if f == nil {
+			return nil, fmt.Errorf("invalid key file descriptor %q", source)
+		}
+		defer func() { _ = f.Close() }()
+
+		return readAllZeroing(f)
+	case strings.HasPrefix(source, keySourceEnvPrefix):
+		name := strings.TrimPrefix(source, keySourceEnvPrefix)
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("environment variable %s with key is not set", name)
+		}
+
+		// child processes, e.g. of shell command, must not inherit the key
+		_ = os.Unsetenv(name)
+		return []byte(value), nil
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s file, err: %w", source, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("could not stat %s file, err: %w", source, err)
+	}
+
+	if perm := info.Mode().Perm(); info.Mode().IsRegular() && perm&0o077 != 0 {
+		if !allowInsecure {
+			return nil, fmt.Errorf("%w: %s has mode %#o, restrict it with chmod 600", ErrInsecureKeyPermissions, source, perm)
+		}
+
+		logger.Warn("Private key file is accessible by group or others", "file", source, "mode", fmt.Sprintf("%#o", perm))
+	}
+
+	return readAllZeroing(f)
+}
+
+// readAllZeroing reads r until EOF like io.ReadAll, but zeroes buffers outgrown while reading,
+// so that no copies of key material are left behind in memory.
+func readAllZeroing(r io.Reader) ([]byte, error) {
+	buf := make([]byte, 0, 4096)
+	for {
+		n, err := r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if errors.Is(err, io.EOF) {
+			return buf, nil
+		}
+
+		if err != nil {
+			clear(buf)
+			return nil, fmt.Errorf("could not read key, err: %w", err)
+		}
+
+		if len(buf) == cap(buf) {
+			grown := make([]byte, len(buf), 2*cap(buf))
+			copy(grown, buf)
+			clear(buf)
+			buf = grown
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"log/slog"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/clog"
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+)
+
+// hashDataPayloadLog implements slog.LogValuer of hash request, input is logged according to redaction policy.
+type hashDataPayloadLog cryptobrokerclientgo.HashDataPayload
+
+// LogValue implements slog.LogValuer.
+func (payload hashDataPayloadLog) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("profile", payload.Profile),
+		slog.Any("input", clog.Sensitive(payload.Input)),
+		slog.Int("input_size", len(payload.Input)),
+	)
+}
+
+// signCertificatePayloadLog implements slog.LogValuer of sign certificate request,
+// CSR, CA certificate and CA private key are logged according to redaction policy.
+type signCertificatePayloadLog cryptobrokerclientgo.SignCertificatePayload
+
+// LogValue implements slog.LogValuer.
+func (payload signCertificatePayloadLog) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("profile", payload.Profile),
+		slog.Any("csr", clog.Sensitive(payload.CSR)),
+		slog.Any("ca_cert", clog.Sensitive(payload.CACert)),
+		slog.Any("ca_private_key", clog.Sensitive(payload.CAPrivateKey)),
+	}
+	if payload.Subject != nil {
+		attrs = append(attrs, slog.String("subject", *payload.Subject))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// hashDataResponse represents getters of hash response used in logs.
+type hashDataResponse interface {
+	GetHashAlgorithm() string
+	GetHashValueHex() string
+	GetHashValueRaw() []byte
+}
+
+// hashDataResponseLog implements slog.LogValuer of hash response. Hash value is logged verbatim as it is command output.
+type hashDataResponseLog struct {
+	response hashDataResponse
+}
+
+// LogValue implements slog.LogValuer.
+func (log hashDataResponseLog) LogValue() slog.Value {
+	if log.response.GetHashValueRaw() != nil {
+		return slog.GroupValue(
+			slog.String("hash_algorithm", log.response.GetHashAlgorithm()),
+			slog.Any("hash_value_raw", log.response.GetHashValueRaw()),
+		)
+	}
+
+	return slog.GroupValue(
+		slog.String("hash_algorithm", log.response.GetHashAlgorithm()),
+		slog.String("hash_value_hex", log.response.GetHashValueHex()),
+	)
+}
+
+// signCertificateResponse represents getters of sign certificate response used in logs.
+type signCertificateResponse interface {
+	GetPem() string
+	GetDer() []byte
+}
+
+// signCertificateResponseLog implements slog.LogValuer of sign certificate response,
+// signed certificate is logged according to redaction policy.
+type signCertificateResponseLog struct {
+	response signCertificateResponse
+}
+
+// LogValue implements slog.LogValuer.
+func (log signCertificateResponseLog) LogValue() slog.Value {
+	if log.response.GetDer() != nil {
+		return slog.GroupValue(slog.Any("certificate", clog.Sensitive(log.response.GetDer())), slog.String("encoding", "der"))
+	}
+
+	return slog.GroupValue(slog.Any("certificate", clog.SensitiveString(log.response.GetPem())), slog.String("encoding", "pem"))
+}
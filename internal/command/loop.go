@@ -0,0 +1,39 @@
+package command
+
+import (
+	"errors"
+	"log/slog"
+
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+)
+
+// loopStats counts outcomes of requests sent in loop mode.
+type loopStats struct {
+	requests    int
+	succeeded   int
+	failed      int
+	circuitOpen int
+}
+
+// record counts outcome of a single request.
+func (stats *loopStats) record(err error) {
+	stats.requests++
+	switch {
+	case err == nil:
+		stats.succeeded++
+	case errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen):
+		stats.circuitOpen++
+	default:
+		stats.failed++
+	}
+}
+
+// logSummary prints counters with given message.
+func (stats *loopStats) logSummary(logger *slog.Logger, msg string, args ...any) {
+	logger.Info(msg, append([]any{
+		"requests", stats.requests,
+		"succeeded", stats.succeeded,
+		"failed", stats.failed,
+		"circuit_open", stats.circuitOpen,
+	}, args...)...)
+}
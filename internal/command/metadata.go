@@ -0,0 +1,35 @@
+package command
+
+import (
+	"context"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/correlation"
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// correlationIdOf returns correlation id of the CLI invocation carried by ctx,
+// falling back to correlation id already present in request metadata.
+func correlationIdOf(ctx context.Context, metadata *cryptobrokerclientgo.Metadata) string {
+	if correlationId := correlation.IDFromContext(ctx); correlationId != "" {
+		return correlationId
+	}
+
+	if metadata != nil && metadata.TraceContext != nil {
+		return metadata.TraceContext.CorrelationId
+	}
+
+	return ""
+}
+
+// setTraceContext injects span context found in ctx into request metadata.
+func setTraceContext(ctx context.Context, metadata *cryptobrokerclientgo.Metadata, correlationId string) {
+	spanContext := trace.SpanContextFromContext(ctx)
+	metadata.TraceContext = &cryptobrokerclientgo.TraceContext{
+		TraceId:       spanContext.TraceID().String(),
+		SpanId:        spanContext.SpanID().String(),
+		TraceFlags:    spanContext.TraceFlags().String(),
+		TraceState:    spanContext.TraceState().String(),
+		CorrelationId: correlationId,
+	}
+}
@@ -0,0 +1,29 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/correlation"
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+)
+
+func TestCorrelationIdOf(t *testing.T) {
+	t.Parallel()
+
+	metadata := &cryptobrokerclientgo.Metadata{
+		TraceContext: &cryptobrokerclientgo.TraceContext{CorrelationId: "from-metadata"},
+	}
+
+	if got := correlationIdOf(correlation.ContextWithID(context.Background(), "from-context"), metadata); got != "from-context" {
+		t.Fatalf("expected correlation id from context, got %q", got)
+	}
+
+	if got := correlationIdOf(context.Background(), metadata); got != "from-metadata" {
+		t.Fatalf("expected correlation id from metadata, got %q", got)
+	}
+
+	if got := correlationIdOf(context.Background(), nil); got != "" {
+		t.Fatalf("expected empty correlation id, got %q", got)
+	}
+}
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+)
+
+// nagiosServiceName prefixes monitoring plugin output line.
+const nagiosServiceName = "CRYPTO_BROKER"
+
+// nagiosStates maps monitoring plugin exit codes to state names.
+var nagiosStates = map[int]string{
+	constant.ExitCodeNagiosOK:       "OK",
+	constant.ExitCodeNagiosWarning:  "WARNING",
+	constant.ExitCodeNagiosCritical: "CRITICAL",
+	constant.ExitCodeNagiosUnknown:  "UNKNOWN",
+}
+
+// nagiosResult evaluates health check result as monitoring plugin (Nagios/Icinga) check.
+// SERVING status is OK, unless latency exceeds warning or critical threshold (zero disables threshold).
+// NOT_SERVING status is CRITICAL and any other status is UNKNOWN.
+// It returns exit code and the STATUS - message | perfdata line with latency in seconds as perfdata.
+func nagiosResult(status string, latency time.Duration, warning time.Duration, critical time.Duration) (int, string) {
+	exitCode := constant.ExitCodeNagiosOK
+	message := fmt.Sprintf("broker is %s, response time %.3f ms", status, float64(latency.Nanoseconds())/1e6)
+	switch {
+	case status == cryptobrokerclientgo.StatusNotServing:
+		exitCode = constant.ExitCodeNagiosCritical
+	case status != cryptobrokerclientgo.StatusServing:
+		exitCode = constant.ExitCodeNagiosUnknown
+	case critical > 0 && latency > critical:
+		exitCode = constant.ExitCodeNagiosCritical
+		message += fmt.Sprintf(" exceeds critical threshold %s", critical)
+	case warning > 0 && latency > warning:
+		exitCode = constant.ExitCodeNagiosWarning
+		message += fmt.Sprintf(" exceeds warning threshold %s", warning)
+	}
+
+	perfdata := fmt.Sprintf("time=%ss;%s;%s;0;", nagiosSeconds(latency), nagiosThreshold(warning), nagiosThreshold(critical))
+
+	return exitCode, fmt.Sprintf("%s %s - %s | %s", nagiosServiceName, nagiosStates[exitCode], message, perfdata)
+}
+
+// NagiosUnknown returns monitoring plugin UNKNOWN line for check that could not be performed.
+func NagiosUnknown(err error) string {
+	return fmt.Sprintf("%s %s - %s", nagiosServiceName, nagiosStates[constant.ExitCodeNagiosUnknown], err)
+}
+
+// nagiosThreshold formats threshold for perfdata, disabled threshold is left empty.
+func nagiosThreshold(threshold time.Duration) string {
+	if threshold <= 0 {
+		return ""
+	}
+
+	return nagiosSeconds(threshold)
+}
+
+// nagiosSeconds formats duration as seconds for perfdata.
+func nagiosSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 6, 64)
+}
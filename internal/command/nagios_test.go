@@ -0,0 +1,65 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+)
+
+func TestNagiosResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		status   string
+		latency  time.Duration
+		warning  time.Duration
+		critical time.Duration
+		exitCode int
+		line     string
+	}{
+		{
+			name: "ok", status: cryptobrokerclientgo.StatusServing, latency: 1500 * time.Microsecond,
+			exitCode: constant.ExitCodeNagiosOK,
+			line:     "CRYPTO_BROKER OK - broker is SERVING, response time 1.500 ms | time=0.001500s;;;0;",
+		},
+		{
+			name: "warning", status: cryptobrokerclientgo.StatusServing, latency: 200 * time.Millisecond,
+			warning: 100 * time.Millisecond, critical: time.Second,
+			exitCode: constant.ExitCodeNagiosWarning,
+			line:     "CRYPTO_BROKER WARNING - broker is SERVING, response time 200.000 ms exceeds warning threshold 100ms | time=0.200000s;0.100000;1.000000;0;",
+		},
+		{
+			name: "critical_latency", status: cryptobrokerclientgo.StatusServing, latency: 2 * time.Second,
+			warning: 100 * time.Millisecond, critical: time.Second,
+			exitCode: constant.ExitCodeNagiosCritical,
+			line:     "CRYPTO_BROKER CRITICAL - broker is SERVING, response time 2000.000 ms exceeds critical threshold 1s | time=2.000000s;0.100000;1.000000;0;",
+		},
+		{
+			name: "not_serving", status: cryptobrokerclientgo.StatusNotServing, latency: time.Millisecond,
+			exitCode: constant.ExitCodeNagiosCritical,
+			line:     "CRYPTO_BROKER CRITICAL - broker is NOT_SERVING, response time 1.000 ms | time=0.001000s;;;0;",
+		},
+		{
+			name: "unknown", status: cryptobrokerclientgo.StatusUnknown, latency: time.Millisecond, warning: time.Nanosecond,
+			exitCode: constant.ExitCodeNagiosUnknown,
+			line:     "CRYPTO_BROKER UNKNOWN - broker is UNKNOWN, response time 1.000 ms | time=0.001000s;0.000000;;0;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			exitCode, line := nagiosResult(tt.status, tt.latency, tt.warning, tt.critical)
+			if exitCode != tt.exitCode {
+				t.Fatalf("expected exit code %d, got %d", tt.exitCode, exitCode)
+			}
+
+			if line != tt.line {
+				t.Fatalf("expected line\n%q\ngot\n%q", tt.line, line)
+			}
+		})
+	}
+}
@@ -0,0 +1,516 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/audit"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// shellPrompt is prompt of interactive shell
+const shellPrompt = "crypto-broker> "
+
+// Shell represents interactive command that keeps one crypto broker library connection open
+// and sends hash, sign and health requests entered line by line.
+type Shell struct {
+	logger          *slog.Logger
+	libraries       *pool.Pool
+	hashData        *HashData
+	signCertificate *SignCertificate
+	health          *Health
+	output          *ShellOutput
+	history         *shellHistory
+	// profiles are completed after --profile, profiles used in the session are added to them
+	profiles []string
+	commands []*shellCommand
+}
+
+// shellCommand represents command available in the shell.
+type shellCommand struct {
+	names []string
+	usage string
+	short string
+	// flags returns flag set of command, flags are parsed into fresh values on every line
+	flags func() *pflag.FlagSet
+	// pathFlags are flags completed with file paths
+	pathFlags []string
+	// run returns one line result of command, which is displayed together with its duration if timed
+	run   func(ctx context.Context, flags *pflag.FlagSet) (string, error)
+	timed bool
+}
+
+// errShellExit is returned by exit command to end the session.
+var errShellExit = errors.New("exit")
+
+// NewShell initializes shell command. Commands write their output and signed certificates to output,
+// lines entered are persisted in history file at historyPath. Profiles are offered by tab completion of --profile.
+// Every signing request is recorded in auditLog, nil audit log disables auditing.
+func NewShell(ctx context.Context, libraries *pool.Pool, logger *slog.Logger, tracerProvider *otel.TracerProvider, retryPolicy retry.Policy, auditLog *audit.Log, output *ShellOutput, historyPath string, profiles []string) (*Shell, error) {
+	hashData, err := NewHashData(ctx, libraries, logger, tracerProvider, retryPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	signCertificate, err := NewSignCertificate(ctx, libraries, logger, tracerProvider, retryPolicy, auditLog)
+	if err != nil {
+		return nil, err
+	}
+	signCertificate.output = output
+
+	health, err := NewHealth(ctx, libraries, logger, tracerProvider, retryPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := loadShellHistory(historyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	command := &Shell{
+		logger:          logger,
+		libraries:       libraries,
+		hashData:        hashData,
+		signCertificate: signCertificate,
+		health:          health,
+		output:          output,
+		history:         history,
+		profiles:        slices.Clone(profiles),
+	}
+	command.commands = command.shellCommands()
+
+	return command, nil
+}
+
+// Run reads commands from input until exit command or end of input. Terminal input is line edited with
+// history and tab completion, other input, e.g. piped script, is read line by line without prompt.
+// Library connection is closed once the session ends.
+func (command *Shell) Run(ctx context.Context, input *os.File) error {
+	defer func() { _ = command.gracefulShutdown() }()
+
+	if term.IsTerminal(int(input.Fd())) {
+		return command.runTerminal(ctx, input)
+	}
+
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		if errors.Is(command.execute(ctx, scanner.Text()), errShellExit) {
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read shell input, err: %w", err)
+	}
+
+	return nil
+}
+
+// runTerminal reads commands from terminal in raw mode. Commands run in cooked mode, so that Ctrl-C
+// interrupts the running command instead of the shell.
+func (command *Shell) runTerminal(ctx context.Context, input *os.File) error {
+	fd := int(input.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to set terminal to raw mode, err: %w", err)
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{input, command.output.w}, shellPrompt)
+	terminal.History = command.history
+	terminal.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+
+		completed, candidates := command.complete(line[:pos])
+		if len(candidates) > 0 {
+			_, _ = fmt.Fprintln(command.output, strings.Join(candidates, "  "))
+		}
+
+		return completed + line[pos:], len(completed), true
+	}
+
+	if width, height, err := term.GetSize(fd); err == nil && width > 0 {
+		_ = terminal.SetSize(width, height)
+	}
+
+	command.output.setTerminal(terminal)
+	defer command.output.setTerminal(nil)
+
+	_, _ = fmt.Fprintln(command.output, `Type "help" for available commands, Tab completes commands, flags and profiles.`)
+	for {
+		line, err := terminal.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to read shell input, err: %w", err)
+		}
+
+		if err := term.Restore(fd, state); err != nil {
+			return fmt.Errorf("failed to restore terminal mode, err: %w", err)
+		}
+
+		err = command.execute(ctx, line)
+		if errors.Is(err, errShellExit) {
+			return nil
+		}
+
+		if _, err := term.MakeRaw(fd); err != nil {
+			return fmt.Errorf("failed to set terminal to raw mode, err: %w", err)
+		}
+	}
+}
+
+// execute runs command of line and displays its result. Failures are displayed, so that the session continues,
+// only errShellExit is returned.
+func (command *Shell) execute(ctx context.Context, line string) error {
+	words, err := splitShellLine(line)
+	if err != nil {
+		_, _ = fmt.Fprintf(command.output, "error: %v\n", err)
+		return nil
+	}
+
+	if len(words) == 0 {
+		return nil
+	}
+
+	shellCmd := command.lookup(words[0])
+	if shellCmd == nil {
+		_, _ = fmt.Fprintf(command.output, "error: unknown command %q, type \"help\" for available commands\n", words[0])
+		return nil
+	}
+
+	flags := shellCmd.flags()
+	if err := flags.Parse(words[1:]); errors.Is(err, pflag.ErrHelp) {
+		command.printUsage(shellCmd)
+		return nil
+	} else if err != nil {
+		_, _ = fmt.Fprintf(command.output, "error: %v\n", err)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	timestampStart := time.Now()
+	result, err := shellCmd.run(ctx, flags)
+	elapsed := time.Since(timestampStart).Round(time.Microsecond)
+	switch {
+	case errors.Is(err, errShellExit):
+		return err
+	case err != nil && shellCmd.timed:
+		_, _ = fmt.Fprintf(command.output, "error: %v (%s)\n", err, elapsed)
+	case err != nil:
+		_, _ = fmt.Fprintf(command.output, "error: %v\n", err)
+	case shellCmd.timed:
+		_, _ = fmt.Fprintf(command.output, "%s (%s)\n", result, elapsed)
+	case result != "":
+		_, _ = fmt.Fprintln(command.output, result)
+	}
+
+	return nil
+}
+
+// complete completes the last word of line prefix before cursor with command name, flag name, profile name
+// or file path, depending on its position. Candidates are returned if the word cannot be extended.
+func (command *Shell) complete(prefix string) (string, []string) {
+	start := strings.LastIndexAny(prefix, " \t") + 1
+	word := prefix[start:]
+	previous := strings.Fields(prefix[:start])
+	if len(previous) == 0 {
+		var names []string
+		for _, shellCmd := range command.commands {
+			names = append(names, shellCmd.names...)
+		}
+
+		completed, candidates := completeWord(word, names)
+		return prefix[:start] + completed, candidates
+	}
+
+	shellCmd := command.lookup(previous[0])
+	if shellCmd == nil {
+		return prefix, nil
+	}
+
+	flags := shellCmd.flags()
+	var candidates []string
+	switch last := strings.TrimLeft(previous[len(previous)-1], "-"); {
+	case len(previous) > 1 && last == constant.KeywordFlagProfile:
+		candidates = command.profiles
+	case len(previous) > 1 && slices.Contains(shellCmd.pathFlags, last):
+		candidates = completePath(word)
+	case strings.HasPrefix(word, "-"):
+		flags.VisitAll(func(flag *pflag.Flag) {
+			candidates = append(candidates, "--"+flag.Name)
+		})
+	}
+
+	completed, candidates := completeWord(word, candidates)
+	return prefix[:start] + completed, candidates
+}
+
+// lookup returns command of name, or nil if there is no such command.
+func (command *Shell) lookup(name string) *shellCommand {
+	for _, shellCmd := range command.commands {
+		if slices.Contains(shellCmd.names, name) {
+			return shellCmd
+		}
+	}
+
+	return nil
+}
+
+// printUsage displays usage and flags of shell command.
+func (command *Shell) printUsage(shellCmd *shellCommand) {
+	_, _ = fmt.Fprintf(command.output, "%s\n\nUsage: %s\n", shellCmd.short, shellCmd.usage)
+	if usages := shellCmd.flags().FlagUsages(); usages != "" {
+		_, _ = fmt.Fprintf(command.output, "\nFlags:\n%s", usages)
+	}
+}
+
+// useProfile adds profile to profiles offered by tab completion.
+func (command *Shell) useProfile(profile string) {
+	if !slices.Contains(command.profiles, profile) {
+		command.profiles = append(command.profiles, profile)
+	}
+}
+
+// shellCommands returns commands available in the shell.
+func (command *Shell) shellCommands() []*shellCommand {
+	newFlags := func(name string) *pflag.FlagSet {
+		flags := pflag.NewFlagSet(name, pflag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		return flags
+	}
+
+	return []*shellCommand{
+		{
+			names: []string{"hash"},
+			usage: "hash TEXT [--profile PROFILE]",
+			short: "Hash sends hashing request of TEXT to crypto broker and displays hex encoded hash.",
+			flags: func() *pflag.FlagSet {
+				flags := newFlags("hash")
+				flags.String(constant.KeywordFlagProfile, "Default", "Specify profile to be used")
+				return flags
+			},
+			run:   command.hash,
+			timed: true,
+		},
+		{
+			names: []string{"sign"},
+			usage: "sign --csr FILE --caCert FILE --caKey SOURCE [--profile PROFILE] [--subject SUBJECT]",
+			short: "Sign sends certificate signing request to crypto broker and displays PEM encoded certificate.",
+			flags: func() *pflag.FlagSet {
+				flags := newFlags("sign")
+				flags.String(constant.KeywordFlagFilePathCSR, "", "Specify path to CSR file")
+				flags.String(constant.KeywordFlagFilePathCACert, "", "Specify path to CA certificate file")
+				flags.String(constant.KeywordFlagFilePathSigningKey, "", `Specify path to signing key file, "fd:N" or "env:NAME"`)
+				flags.String(constant.KeywordFlagProfile, "Default", "Specify profile to be used")
+				flags.String(constant.KeywordFlagSubject, "", "Specify custom subject to be used for certificate generation")
+				flags.Bool(constant.KeywordFlagAllowInsecureKey, false, "Allow signing key file accessible by group or others")
+				return flags
+			},
+			pathFlags: []string{constant.KeywordFlagFilePathCSR, constant.KeywordFlagFilePathCACert, constant.KeywordFlagFilePathSigningKey},
+			run:       command.sign,
+			timed:     true,
+		},
+		{
+			names: []string{"health"},
+			usage: "health",
+			short: "Health checks the broker server status.",
+			flags: func() *pflag.FlagSet { return newFlags("health") },
+			run:   command.checkHealth,
+			timed: true,
+		},
+		{
+			names: []string{"history"},
+			usage: "history",
+			short: "History displays lines entered in the shell, the most recent last.",
+			flags: func() *pflag.FlagSet { return newFlags("history") },
+			run: func(context.Context, *pflag.FlagSet) (string, error) {
+				lines := make([]string, 0, command.history.Len())
+				for i, entry := range command.history.entries {
+					lines = append(lines, fmt.Sprintf("%5d  %s", i+1, entry))
+				}
+
+				return strings.Join(lines, "\n"), nil
+			},
+		},
+		{
+			names: []string{"help"},
+			usage: "help [COMMAND]",
+			short: "Help displays available commands or usage of COMMAND.",
+			flags: func() *pflag.FlagSet { return newFlags("help") },
+			run:   command.help,
+		},
+		{
+			names: []string{"exit", "quit"},
+			usage: "exit",
+			short: "Exit ends the session, so does Ctrl-D.",
+			flags: func() *pflag.FlagSet { return newFlags("exit") },
+			run: func(context.Context, *pflag.FlagSet) (string, error) {
+				return "", errShellExit
+			},
+		},
+	}
+}
+
+// hash sends hash request of the single argument and returns hex encoded hash.
+func (command *Shell) hash(ctx context.Context, flags *pflag.FlagSet) (string, error) {
+	if flags.NArg() != 1 {
+		return "", fmt.Errorf("hash accepts 1 argument, received %d, quote text containing spaces", flags.NArg())
+	}
+
+	profile, _ := flags.GetString(constant.KeywordFlagProfile)
+	command.useProfile(profile)
+
+	payload := cryptobrokerclientgo.HashDataPayload{
+		Input:        []byte(flags.Arg(0)),
+		Profile:      profile,
+		OutputFormat: cryptobrokerclientgo.OutputFormatHex,
+	}
+
+	command.logger.InfoContext(ctx, "Hashing input", "request", hashDataPayloadLog(payload))
+	response, err := command.hashData.hashBytes(ctx, payload)
+	if err != nil {
+		return "", err
+	}
+
+	if response == nil {
+		return "", errors.New("empty hash response")
+	}
+
+	return response.GetHashValueHex(), nil
+}
+
+// sign sends certificate signing request, signed certificate is written to shell output once it is audited.
+// Signing key is zeroed once the request is sent, except its copy made by client library, see readKeyBytes.
+// It cannot be read from stdin, which is input of the shell.
+func (command *Shell) sign(ctx context.Context, flags *pflag.FlagSet) (string, error) {
+	if flags.NArg() != 0 {
+		return "", fmt.Errorf("sign accepts no arguments, received %d", flags.NArg())
+	}
+
+	filePathCSR, _ := flags.GetString(constant.KeywordFlagFilePathCSR)
+	filePathCACert, _ := flags.GetString(constant.KeywordFlagFilePathCACert)
+	signingKeySource, _ := flags.GetString(constant.KeywordFlagFilePathSigningKey)
+	if filePathCSR == "" || filePathCACert == "" || signingKeySource == "" {
+		return "", fmt.Errorf("flags --%s, --%s and --%s are required",
+			constant.KeywordFlagFilePathCSR, constant.KeywordFlagFilePathCACert, constant.KeywordFlagFilePathSigningKey)
+	}
+
+	if signingKeySource == keySourceStdin {
+		return "", errors.New("signing key cannot be read from stdin in the shell")
+	}
+
+	profile, _ := flags.GetString(constant.KeywordFlagProfile)
+	flagSubject, _ := flags.GetString(constant.KeywordFlagSubject)
+	allowInsecureKey, _ := flags.GetBool(constant.KeywordFlagAllowInsecureKey)
+	command.useProfile(profile)
+
+	rawContentCSR, err := command.signCertificate.readFileBytes(filePathCSR)
+	if err != nil {
+		return "", fmt.Errorf("could not read certificate signing request file, err: %w", err)
+	}
+
+	rawContentCACert, err := command.signCertificate.readFileBytes(filePathCACert)
+	if err != nil {
+		return "", fmt.Errorf("could not read CA Certificate file, err: %w", err)
+	}
+
+	rawContentSigningKey, err := readKeyBytes(command.logger, signingKeySource, allowInsecureKey)
+	if err != nil {
+		return "", fmt.Errorf("could not read signing key, err: %w", err)
+	}
+	// zeroes read buffer only, client library copies the key to request string
+	defer clear(rawContentSigningKey)
+
+	var subject *string
+	if flagSubject != "" {
+		subject = &flagSubject
+	}
+
+	payload := cryptobrokerclientgo.SignCertificatePayload{
+		Profile:      profile,
+		CSR:          rawContentCSR,
+		CAPrivateKey: rawContentSigningKey,
+		CACert:       rawContentCACert,
+		Subject:      subject,
+	}
+
+	command.logger.InfoContext(ctx,
+		fmt.Sprintf("Signing certificate using %s profile", profile),
+		"request", signCertificatePayloadLog(payload),
+	)
+
+	if err := command.signCertificate.signCertificate(ctx, payload, constant.EncodingPEM); err != nil {
+		return "", err
+	}
+
+	return "certificate issued", nil
+}
+
+// checkHealth checks broker health and returns its status.
+func (command *Shell) checkHealth(ctx context.Context, flags *pflag.FlagSet) (string, error) {
+	if flags.NArg() != 0 {
+		return "", fmt.Errorf("health accepts no arguments, received %d", flags.NArg())
+	}
+
+	if _, err := command.health.checkHealth(ctx); err != nil {
+		return "", err
+	}
+
+	return cryptobrokerclientgo.StatusServing, nil
+}
+
+// help returns list of available commands, or usage of command passed as argument.
+func (command *Shell) help(_ context.Context, flags *pflag.FlagSet) (string, error) {
+	if flags.NArg() > 0 {
+		shellCmd := command.lookup(flags.Arg(0))
+		if shellCmd == nil {
+			return "", fmt.Errorf("unknown command %q", flags.Arg(0))
+		}
+
+		command.printUsage(shellCmd)
+		return "", nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Available commands:\n")
+	for _, shellCmd := range command.commands {
+		_, _ = fmt.Fprintf(&builder, "  %-16s %s\n", strings.Join(shellCmd.names, ", "), shellCmd.short)
+	}
+	builder.WriteString("\nType \"help COMMAND\" or \"COMMAND --help\" for its usage.")
+
+	return builder.String(), nil
+}
+
+// gracefulShutdown closes library connection shared by all commands of the session.
+func (command *Shell) gracefulShutdown() error {
+	command.logger.Info("Closing crypto broker library connections")
+	ctx, cancel := context.WithTimeout(context.Background(), constant.PoolDrainTimeout)
+	defer cancel()
+	return command.libraries.Shutdown(ctx)
+}
@@ -0,0 +1,275 @@
+package command
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+)
+
+// ShellOutput is output of shell commands, console logs and console telemetry exporters.
+// Once shell reads from terminal, writes go through the terminal, so that they do not corrupt prompt
+// and line being edited.
+type ShellOutput struct {
+	mu       sync.Mutex
+	w        io.Writer
+	terminal io.Writer
+}
+
+// NewShellOutput returns shell output writing to w until shell reads from terminal.
+func NewShellOutput(w io.Writer) *ShellOutput {
+	return &ShellOutput{w: w}
+}
+
+// Write implements io.Writer.
+func (output *ShellOutput) Write(p []byte) (int, error) {
+	output.mu.Lock()
+	defer output.mu.Unlock()
+
+	if output.terminal != nil {
+		return output.terminal.Write(p)
+	}
+
+	return output.w.Write(p)
+}
+
+// setTerminal routes writes through terminal, nil terminal routes them to underlying writer again.
+func (output *ShellOutput) setTerminal(terminal io.Writer) {
+	output.mu.Lock()
+	defer output.mu.Unlock()
+
+	output.terminal = terminal
+}
+
+// splitShellLine splits line into words like POSIX shell does. Words are separated by unquoted whitespace,
+// single quotes preserve their content literally, double quotes preserve it except backslash escapes
+// of '"' and '\' and unquoted backslash escapes any character.
+func splitShellLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			if quote == '"' && r != '"' && r != '\\' {
+				word.WriteRune('\\')
+			}
+
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if escaped {
+		return nil, errors.New("line ends with unescaped backslash")
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// shellHistory implements term.History of lines entered in the shell, persisted in file,
+// so that they can be recalled in later sessions.
+type shellHistory struct {
+	path string
+	// entries are ordered from the oldest to the most recent one
+	entries []string
+}
+
+// DefaultShellHistoryPath returns path of shell history file in user configuration directory.
+func DefaultShellHistoryPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, "crypto-broker-cli", "shell_history")
+}
+
+// loadShellHistory reads the last constant.MaxShellHistory entries of history file at path, missing file
+// starts empty history. File grown beyond the limit is rewritten with the entries kept.
+func loadShellHistory(path string) (*shellHistory, error) {
+	history := &shellHistory{path: path}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not open shell history file, err: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			history.entries = append(history.entries, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read shell history file, err: %w", err)
+	}
+
+	if len(history.entries) > constant.MaxShellHistory {
+		history.entries = slices.Clone(history.entries[len(history.entries)-constant.MaxShellHistory:])
+		content := strings.Join(history.entries, "\n") + "\n"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			return nil, fmt.Errorf("could not truncate shell history file, err: %w", err)
+		}
+	}
+
+	return history, nil
+}
+
+// Add implements term.History. Repeated entry is recorded once. Entry is appended to history file, unless
+// it contains plaintext input of hash command, failure to persist it does not interrupt the session.
+func (history *shellHistory) Add(entry string) {
+	// completion leaves space after the last word
+	entry = strings.TrimSpace(entry)
+	if entry == "" || (len(history.entries) > 0 && history.entries[len(history.entries)-1] == entry) {
+		return
+	}
+
+	history.entries = append(history.entries, entry)
+	if len(history.entries) > constant.MaxShellHistory {
+		history.entries = history.entries[1:]
+	}
+
+	if !isPersistedShellLine(entry) {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(history.path), 0o700); err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(history.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	_, _ = fmt.Fprintln(f, entry)
+}
+
+// isPersistedShellLine reports whether line can be written to history file. Lines of hash command are kept
+// in memory only, as their input may be secret, and so are lines that cannot be split to tell their command.
+func isPersistedShellLine(line string) bool {
+	words, err := splitShellLine(line)
+	return err == nil && (len(words) == 0 || words[0] != "hash")
+}
+
+// Len implements term.History.
+func (history *shellHistory) Len() int {
+	return len(history.entries)
+}
+
+// At implements term.History, index 0 is the most recent entry.
+func (history *shellHistory) At(idx int) string {
+	return history.entries[len(history.entries)-1-idx]
+}
+
+// completeWord completes word to the longest common prefix of candidates it prefixes. Completion of single
+// candidate is terminated by space, unless it is directory. Candidates are returned only if word
+// cannot be extended, so that they can be listed to the user.
+func completeWord(word string, candidates []string) (string, []string) {
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, word) && !slices.Contains(matches, candidate) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return word, nil
+	case 1:
+		if strings.HasSuffix(matches[0], string(filepath.Separator)) {
+			return matches[0], nil
+		}
+
+		return matches[0] + " ", nil
+	}
+
+	prefix := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	if len(prefix) > len(word) {
+		return prefix, nil
+	}
+
+	slices.Sort(matches)
+	return word, matches
+}
+
+// completePath returns paths of files and directories prefixed by word, directories end with separator.
+func completePath(word string) []string {
+	dir, base := filepath.Split(word)
+	entries, err := os.ReadDir(cmp.Or(dir, "."))
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".")) {
+			continue
+		}
+
+		if entry.IsDir() {
+			name += string(filepath.Separator)
+		}
+
+		paths = append(paths, dir+name)
+	}
+
+	return paths
+}
@@ -0,0 +1,180 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+)
+
+func TestSplitShellLine(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		line  string
+		words []string
+		err   bool
+	}{
+		"plain":         {line: "  hash abc\t--profile X ", words: []string{"hash", "abc", "--profile", "X"}},
+		"double_quotes": {line: `hash "a \"b\" \n"`, words: []string{"hash", `a "b" \n`}},
+		"single_quotes": {line: `hash 'a "b" \'`, words: []string{"hash", `a "b" \`}},
+		"escape":        {line: `hash a\ b ""`, words: []string{"hash", "a b", ""}},
+		"adjacent":      {line: `hash a"b c"'d'`, words: []string{"hash", "ab cd"}},
+		"empty":         {line: "   "},
+		"unterminated":  {line: `hash "abc`, err: true},
+		"backslash":     {line: `hash abc\`, err: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			words, err := splitShellLine(test.line)
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+
+			if !slices.Equal(words, test.words) {
+				t.Fatalf("expected words %q, got %q", test.words, words)
+			}
+		})
+	}
+}
+
+func TestShellComplete(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"ca.crt", "ca.key", "csr.pem"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+
+	shell := &Shell{profiles: []string{"Default", "PCI-DSS"}}
+	shell.commands = shell.shellCommands()
+	shell.useProfile("Partner")
+
+	tests := map[string]struct {
+		prefix     string
+		completed  string
+		candidates []string
+	}{
+		"command":          {prefix: "si", completed: "sign "},
+		"command_prefix":   {prefix: "h", candidates: []string{"hash", "health", "help", "history"}},
+		"common_prefix":    {prefix: "hi", completed: "history "},
+		"flag":             {prefix: "hash abc --pr", completed: "hash abc --profile "},
+		"flag_candidates":  {prefix: "sign --ca", candidates: []string{"--caCert", "--caKey"}},
+		"flag_complete":    {prefix: "sign --caC", completed: "sign --caCert "},
+		"profile":          {prefix: "hash abc --profile P", candidates: []string{"PCI-DSS", "Partner"}},
+		"profile_complete": {prefix: "hash abc --profile D", completed: "hash abc --profile Default "},
+		"path":             {prefix: "sign --csr " + dir + "/cs", completed: "sign --csr " + dir + "/csr.pem "},
+		"path_candidates":  {prefix: "sign --caKey " + dir + "/ca.", candidates: []string{dir + "/ca.crt", dir + "/ca.key"}},
+		"unknown_command":  {prefix: "foo --pr", completed: "foo --pr"},
+		"argument":         {prefix: "hash ab", completed: "hash ab"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			completed, candidates := shell.complete(test.prefix)
+			if test.completed == "" {
+				test.completed = test.prefix
+			}
+
+			if completed != test.completed || !slices.Equal(candidates, test.candidates) {
+				t.Fatalf("expected %q with candidates %q, got %q with %q", test.completed, test.candidates, completed, candidates)
+			}
+		})
+	}
+}
+
+func TestShellExecute(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+	shell := &Shell{output: NewShellOutput(&output), history: &shellHistory{}}
+	shell.commands = shell.shellCommands()
+
+	tests := map[string]struct {
+		line   string
+		output string
+		exit   bool
+	}{
+		"help":           {line: "help", output: "Available commands:"},
+		"help_command":   {line: "help sign", output: "Usage: sign --csr FILE"},
+		"flag_help":      {line: "hash --help", output: "--profile string"},
+		"unknown":        {line: "foo", output: `error: unknown command "foo"`},
+		"unknown_flag":   {line: "health --loop 5", output: "error: unknown flag: --loop"},
+		"quote":          {line: `hash "abc`, output: "error: unterminated \" quote"},
+		"hash_arguments": {line: "hash a b", output: "error: hash accepts 1 argument, received 2"},
+		"sign_required":  {line: "sign --csr a.csr", output: "error: flags --csr, --caCert and --caKey are required ("},
+		"sign_stdin":     {line: "sign --csr a --caCert b --caKey -", output: "signing key cannot be read from stdin"},
+		"exit":           {line: "quit", exit: true},
+	}
+
+	for name, test := range tests {
+		output.Reset()
+		err := shell.execute(context.Background(), test.line)
+		if (err != nil) != test.exit {
+			t.Fatalf("%s: expected exit %v, got %v", name, test.exit, err)
+		}
+
+		if !strings.Contains(output.String(), test.output) {
+			t.Fatalf("%s: expected output containing %q, got %q", name, test.output, output.String())
+		}
+	}
+}
+
+func TestShellHistory(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "shell", "history")
+	history, err := loadShellHistory(path)
+	if err != nil || history.Len() != 0 {
+		t.Fatalf("expected empty history, got %d entries, %v", history.Len(), err)
+	}
+
+	for _, entry := range []string{"health ", "health", "", "hash abc"} {
+		history.Add(entry)
+	}
+
+	if history.Len() != 2 || history.At(0) != "hash abc" || history.At(1) != "health" {
+		t.Fatalf("expected 2 entries, the most recent first, got %q", history.entries)
+	}
+
+	// history is persisted and truncated to the last entries when loaded, except hash input kept in memory only
+	for i := range constant.MaxShellHistory {
+		history.Add(fmt.Sprintf("sign --csr %d.pem", i))
+	}
+
+	history.Add(`"hash" 'secret input'`)
+	if history.At(0) != `"hash" 'secret input'` {
+		t.Fatalf("expected hash line recalled in session, got %q", history.At(0))
+	}
+
+	history, err = loadShellHistory(path)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if history.Len() != constant.MaxShellHistory || history.At(0) != fmt.Sprintf("sign --csr %d.pem", constant.MaxShellHistory-1) {
+		t.Fatalf("expected %d entries, got %d, the most recent %q", constant.MaxShellHistory, history.Len(), history.At(0))
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if lines := strings.Count(string(content), "\n"); lines != constant.MaxShellHistory {
+		t.Fatalf("expected history file truncated to %d lines, got %d", constant.MaxShellHistory, lines)
+	}
+
+	if strings.Contains(string(content), "hash") {
+		t.Fatalf("expected hash lines not persisted, got %q", content)
+	}
+}
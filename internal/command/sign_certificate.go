@@ -13,30 +13,46 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/audit"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
 	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type SignCertificate struct {
-	logger              *slog.Logger
-	cryptoBrokerLibrary *cryptobrokerclientgo.Library
-	tracerProvider      *otel.TracerProvider
+	logger         *slog.Logger
+	libraries      *pool.Pool
+	tracerProvider *otel.TracerProvider
+	retryPolicy    retry.Policy
+	// output receives signed certificates, logs contain them only redacted
+	output   io.Writer
+	auditLog *audit.Log
 }
 
 // NewSignCertificate initializes sign command. This may panic in case of failure.
-func NewSignCertificate(ctx context.Context, lib *cryptobrokerclientgo.Library, logger *slog.Logger, tracerProvider *otel.TracerProvider) (*SignCertificate, error) {
+// Every signing request is recorded in auditLog, nil audit log disables auditing.
+func NewSignCertificate(ctx context.Context, libraries *pool.Pool, logger *slog.Logger, tracerProvider *otel.TracerProvider, retryPolicy retry.Policy, auditLog *audit.Log) (*SignCertificate, error) {
 	return &SignCertificate{
-		logger:              logger,
-		cryptoBrokerLibrary: lib,
-		tracerProvider:      tracerProvider,
+		logger:         logger,
+		libraries:      libraries,
+		tracerProvider: tracerProvider,
+		retryPolicy:    retryPolicy,
+		output:         os.Stdout,
+		auditLog:       auditLog,
 	}, nil
 }
 
 // Run executes command logic.
-func (command *SignCertificate) Run(ctx context.Context, filePathCSR, filePathCACert, filePathSigningKey, flagProfile, flagEncoding, flagSubject string, flagLoop int) error {
+// Requests short-circuited by an open circuit breaker are logged and counted. In loop mode they stop the loop
+// only when flagFailOnCircuitOpen is set. Returned error wraps cryptobrokerclientgo.ErrCircuitOpen
+// if the final request was short-circuited.
+// Signing key is read from signingKeySource, see readKeyBytes, and zeroed once all requests are sent.
+// Copy of the key made by client library for each request is not zeroed.
+func (command *SignCertificate) Run(ctx context.Context, filePathCSR, filePathCACert, signingKeySource, flagProfile, flagEncoding, flagSubject string, flagLoop int, flagFailOnCircuitOpen bool, flagAllowInsecureKey bool) error {
 	defer func() { _ = command.gracefulShutdown() }()
 
 	rawContentCSR, err := command.readFileBytes(filePathCSR)
@@ -49,10 +65,12 @@ func (command *SignCertificate) Run(ctx context.Context, filePathCSR, filePathCA
 		return fmt.Errorf("could not read CA Certificate file, err: %w", err)
 	}
 
-	rawContentSigningKey, err := command.readFileBytes(filePathSigningKey)
+	rawContentSigningKey, err := readKeyBytes(command.logger, signingKeySource, flagAllowInsecureKey)
 	if err != nil {
-		return fmt.Errorf("could not read signing key file, err: %w", err)
+		return fmt.Errorf("could not read signing key, err: %w", err)
 	}
+	// zeroes read buffer only, client library copies the key to request string
+	defer clear(rawContentSigningKey)
 
 	var subject *string
 	if flagSubject != "" {
@@ -70,8 +88,9 @@ func (command *SignCertificate) Run(ctx context.Context, filePathCSR, filePathCA
 		Metadata:     nil, // Will be set in signCertificate with trace context
 	}
 
-	command.logger.Info(
+	command.logger.InfoContext(ctx,
 		fmt.Sprintf("Signing certificate using %s profile", flagProfile),
+		"request", signCertificatePayloadLog(payload),
 	)
 
 	c := make(chan os.Signal, 1)
@@ -83,33 +102,54 @@ func (command *SignCertificate) Run(ctx context.Context, filePathCSR, filePathCA
 			return fmt.Errorf("could not parse duration, err: %w", err)
 		}
 
+		var stats loopStats
+		defer stats.logSummary(command.logger, "Sign certificate loop summary")
+
+		var lastErr error
 		for {
 			select {
 			case <-c:
-				command.logger.Info("Received SIGTERM signal")
-				return nil
+				command.logger.InfoContext(ctx, "Received SIGTERM signal")
+				return lastErr
 			default:
-				if err := command.signCertificate(ctx, payload, flagEncoding); err != nil {
-					return err
+				lastErr = command.signCertificate(ctx, payload, flagEncoding)
+				stats.record(lastErr)
+				if lastErr != nil && (!errors.Is(lastErr, cryptobrokerclientgo.ErrCircuitOpen) || flagFailOnCircuitOpen) {
+					return lastErr
 				}
 
 				time.Sleep(toSleep)
 			}
 		}
 	} else {
-		if err := command.signCertificate(ctx, payload, flagEncoding); err != nil {
-			return err
-		}
-		return nil
+		return command.signCertificate(ctx, payload, flagEncoding)
 	}
 }
 
+// signCertificate sends certificate signing request through crypto broker library.
+// In case of success it displays response and returns nil error, otherwise it returns non-nil error.
+// Requests short-circuited by an open circuit breaker return cryptobrokerclientgo.ErrCircuitOpen.
 func (command *SignCertificate) signCertificate(ctx context.Context, payload cryptobrokerclientgo.SignCertificatePayload, flagEncoding string) error {
-	tracer := command.tracerProvider.GetTracer("crypto-broker-cli-go")
-	correlationId := ""
-	if payload.Metadata != nil && payload.Metadata.TraceContext != nil {
-		correlationId = payload.Metadata.TraceContext.CorrelationId
+	responseBody, err := command.issueCertificate(ctx, payload, flagEncoding)
+	if err != nil {
+		return err
+	}
+
+	if responseBody != nil {
+		if err := command.writeCertificate(responseBody); err != nil {
+			return fmt.Errorf("failed to write signed certificate, err: %w", err)
+		}
 	}
+
+	return nil
+}
+
+// issueCertificate sends certificate signing request through crypto broker library and returns response
+// once issuance is audited, so that certificate is never handed out without audit entry.
+// Requests short-circuited by an open circuit breaker return cryptobrokerclientgo.ErrCircuitOpen.
+func (command *SignCertificate) issueCertificate(ctx context.Context, payload cryptobrokerclientgo.SignCertificatePayload, flagEncoding string) (signCertificateResponse, error) {
+	tracer := command.tracerProvider.GetTracer("crypto-broker-cli-go")
+	correlationId := correlationIdOf(ctx, payload.Metadata)
 	ctx, span := tracer.Start(ctx, "CLI.SignCertificate",
 		trace.WithAttributes(
 			otel.AttributeRpcMethod.String("SignCertificate"),
@@ -121,20 +161,12 @@ func (command *SignCertificate) signCertificate(ctx context.Context, payload cry
 		))
 	defer span.End()
 
-	// Inject trace context into payload metadata
-	spanContext := span.SpanContext()
+	// Metadata id is shared by all attempts, trace context is injected per attempt
 	if payload.Metadata == nil {
 		payload.Metadata = &cryptobrokerclientgo.Metadata{
 			Id: uuid.New().String(),
 		}
 	}
-	payload.Metadata.TraceContext = &cryptobrokerclientgo.TraceContext{
-		TraceId:       spanContext.TraceID().String(),
-		SpanId:        spanContext.SpanID().String(),
-		TraceFlags:    spanContext.TraceFlags().String(),
-		TraceState:    spanContext.TraceState().String(),
-		CorrelationId: correlationId,
-	}
 
 	timestampSignCertificateStart := time.Now()
 	payload.OutputFormat = cryptobrokerclientgo.OutputFormatPem // default output format
@@ -142,11 +174,26 @@ func (command *SignCertificate) signCertificate(ctx context.Context, payload cry
 		payload.OutputFormat = cryptobrokerclientgo.OutputFormatDer
 	}
 
-	responseBody, err := command.cryptoBrokerLibrary.SignCertificate(ctx, payload)
-	if err != nil && !errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen) {
+	auditEntry := audit.NewSignCertificateEntry(payload.Profile, payload.CSR, payload.CACert, payload.Subject)
+	auditEntry.MetadataId = payload.Metadata.Id
+	auditEntry.TraceId = span.SpanContext().TraceID().String()
+	auditEntry.CorrelationId = correlationId
+
+	responseBody, err := retry.Do(ctx, command.retryPolicy, tracer, "CLI.SignCertificate.Attempt", payload,
+		func(ctx context.Context) { setTraceContext(ctx, payload.Metadata, correlationId) },
+		otel.MeasureAttempts(otel.OperationSignCertificate, payload.Profile, len(payload.CSR)+len(payload.CACert)+len(payload.CAPrivateKey),
+			pool.Bind(command.libraries, (*cryptobrokerclientgo.Library).SignCertificate)))
+	if errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen) {
+		recordCircuitOpen(ctx, command.logger, span, "SignCertificate")
+		auditEntry.Outcome, auditEntry.Error = audit.OutcomeShortCircuited, err.Error()
+		return nil, errors.Join(err, command.audit(ctx, auditEntry))
+	}
+
+	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return fmt.Errorf("failed to obtain signed certificate through CryptoBroker library, err: %w", err)
+		auditEntry.Outcome, auditEntry.Error = audit.OutcomeFailed, err.Error()
+		return nil, errors.Join(fmt.Errorf("failed to obtain signed certificate through CryptoBroker library, err: %w", err), command.audit(ctx, auditEntry))
 	}
 
 	if responseBody != nil {
@@ -156,19 +203,52 @@ func (command *SignCertificate) signCertificate(ctx context.Context, payload cry
 		span.SetAttributes(otel.AttributeCryptoSignedCertSize.Int(len(responseBody.GetDer()) + len(responseBody.GetPem())))
 		span.SetStatus(codes.Ok, "Certificate signing completed successfully")
 
-		command.logger.Info("Sign certificate response", "response", responseBody)
-		command.logger.Info(
+		command.logger.InfoContext(ctx, "Sign certificate response", "response", signCertificateResponseLog{response: responseBody})
+
+		// certificate is returned only once its issuance is audited
+		auditEntry.Outcome = audit.OutcomeIssued
+		auditEntry.SetCertificate(append(responseBody.GetDer(), responseBody.GetPem()...))
+		if err := command.audit(ctx, auditEntry); err != nil {
+			return nil, err
+		}
+
+		command.logger.InfoContext(ctx,
 			fmt.Sprintf("Certificate Signing took %d µs", durationElapsedSignCertificate.Microseconds()),
 		)
+
+		return responseBody, nil
+	}
+
+	return nil, nil
+}
+
+// audit records signing request in audit log.
+func (command *SignCertificate) audit(ctx context.Context, entry *audit.Entry) error {
+	if err := command.auditLog.Write(entry); err != nil {
+		command.logger.ErrorContext(ctx, "Failed to write audit log entry", "error", err, "outcome", entry.Outcome)
+		return fmt.Errorf("failed to write audit log entry, err: %w", err)
 	}
 
 	return nil
 }
 
-// gracefulShutdown closes library connection.
+// writeCertificate writes signed certificate of response to command output, PEM as is and DER as raw bytes.
+func (command *SignCertificate) writeCertificate(response signCertificateResponse) error {
+	if response.GetDer() != nil {
+		_, err := command.output.Write(response.GetDer())
+		return err
+	}
+
+	_, err := io.WriteString(command.output, response.GetPem())
+	return err
+}
+
+// gracefulShutdown closes library connections once requests in flight complete.
 func (command *SignCertificate) gracefulShutdown() error {
-	command.logger.Info("Closing crypto broker library connection")
-	return command.cryptoBrokerLibrary.Close()
+	command.logger.Info("Closing crypto broker library connections")
+	ctx, cancel := context.WithTimeout(context.Background(), constant.PoolDrainTimeout)
+	defer cancel()
+	return command.libraries.Shutdown(ctx)
 }
 
 // readFileBytes opens a file and reads its bytes
@@ -9,6 +9,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otel"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/retry"
 	cryptobroker "github.com/open-crypto-broker/crypto-broker-client-go"
 )
 
@@ -25,14 +27,15 @@ func BenchmarkSignCertificate_profile_Default_CSR_SECP256R1_CA_RSA4096_Sequentia
 	if err != nil {
 		b.Fatalf("could not instantiate tracer provider, err: %s", err.Error())
 	}
-	lib, err := cryptobroker.NewLibrary(ctx)
+	libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 	if err != nil {
 		b.Fatalf("could not instantiate library, err: %s", err.Error())
 	}
-	signCrtCmd, err := NewSignCertificate(ctx, lib, logger, tracerProvider)
+	signCrtCmd, err := NewSignCertificate(ctx, libraries, logger, tracerProvider, retry.Policy{}, nil)
 	if err != nil {
 		b.Fatalf("could not instantiate sign, err: %s", err.Error())
 	}
+	signCrtCmd.output = io.Discard
 
 	payload := cryptobroker.SignCertificatePayload{
 		Metadata: &cryptobroker.Metadata{
@@ -101,15 +104,16 @@ func BenchmarkSignCertificate_profile_Default_CSR_SECP256R1_CA_RSA4096_Parallel(
 	}
 
 	b.RunParallel(func(p *testing.PB) {
-		lib, err := cryptobroker.NewLibrary(ctx)
+		libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 		if err != nil {
 			b.Fatalf("could not instantiate library, err: %s", err.Error())
 		}
 
-		signCrtCmd, err := NewSignCertificate(ctx, lib, logger, tracerProvider)
+		signCrtCmd, err := NewSignCertificate(ctx, libraries, logger, tracerProvider, retry.Policy{}, nil)
 		if err != nil {
 			b.Fatalf("could not instantiate sign, err: %s", err.Error())
 		}
+		signCrtCmd.output = io.Discard
 
 		payload := cryptobroker.SignCertificatePayload{
 			Metadata: &cryptobroker.Metadata{
@@ -177,14 +181,15 @@ func BenchmarkSignCertificate_profile_Default_CSR_SECP521R1_CA_SECP521R1_Sequent
 	if err != nil {
 		b.Fatalf("could not instantiate tracer provider, err: %s", err.Error())
 	}
-	lib, err := cryptobroker.NewLibrary(ctx)
+	libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 	if err != nil {
 		b.Fatalf("could not instantiate library, err: %s", err.Error())
 	}
-	signCrtCmd, err := NewSignCertificate(ctx, lib, logger, tracerProvider)
+	signCrtCmd, err := NewSignCertificate(ctx, libraries, logger, tracerProvider, retry.Policy{}, nil)
 	if err != nil {
 		b.Fatalf("could not instantiate sign, err: %s", err.Error())
 	}
+	signCrtCmd.output = io.Discard
 
 	payload := cryptobroker.SignCertificatePayload{
 		Metadata: &cryptobroker.Metadata{
@@ -256,15 +261,16 @@ func BenchmarkSignCertificate_profile_Default_CSR_SECP521R1_CA_SECP521R1_Paralle
 	}
 
 	b.RunParallel(func(p *testing.PB) {
-		lib, err := cryptobroker.NewLibrary(ctx)
+		libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 		if err != nil {
 			b.Fatalf("could not instantiate library, err: %s", err.Error())
 		}
 
-		signCrtCmd, err := NewSignCertificate(ctx, lib, logger, tracerProvider)
+		signCrtCmd, err := NewSignCertificate(ctx, libraries, logger, tracerProvider, retry.Policy{}, nil)
 		if err != nil {
 			b.Fatalf("could not instantiate sign, err: %s", err.Error())
 		}
+		signCrtCmd.output = io.Discard
 
 		payload := cryptobroker.SignCertificatePayload{
 			Metadata: &cryptobroker.Metadata{
@@ -335,14 +341,15 @@ func BenchmarkSignCertificate_profile_Default_CSR_SECP256R1_CA_SECP384R1_Sequent
 	if err != nil {
 		b.Fatalf("could not instantiate tracer provider, err: %s", err.Error())
 	}
-	lib, err := cryptobroker.NewLibrary(ctx)
+	libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 	if err != nil {
 		b.Fatalf("could not instantiate library, err: %s", err.Error())
 	}
-	signCrtCmd, err := NewSignCertificate(ctx, lib, logger, tracerProvider)
+	signCrtCmd, err := NewSignCertificate(ctx, libraries, logger, tracerProvider, retry.Policy{}, nil)
 	if err != nil {
 		b.Fatalf("could not instantiate sign certificate, err: %s", err.Error())
 	}
+	signCrtCmd.output = io.Discard
 
 	payload := cryptobroker.SignCertificatePayload{
 		Metadata: &cryptobroker.Metadata{
@@ -409,15 +416,16 @@ func BenchmarkSignCertificate_profile_Default_CSR_SECP256R1_CA_SECP384R1_Paralle
 	}
 
 	b.RunParallel(func(p *testing.PB) {
-		lib, err := cryptobroker.NewLibrary(ctx)
+		libraries, err := pool.New(ctx, logger, pool.Config{Size: 1})
 		if err != nil {
 			b.Fatalf("could not instantiate library, err: %s", err.Error())
 		}
 
-		signCrtCmd, err := NewSignCertificate(ctx, lib, logger, tracerProvider)
+		signCrtCmd, err := NewSignCertificate(ctx, libraries, logger, tracerProvider, retry.Policy{}, nil)
 		if err != nil {
 			b.Fatalf("could not instantiate sign certificate, err: %s", err.Error())
 		}
+		signCrtCmd.output = io.Discard
 
 		payload := cryptobroker.SignCertificatePayload{
 			Metadata: &cryptobroker.Metadata{
@@ -3,6 +3,8 @@ package command
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"runtime"
 	"strconv"
@@ -12,6 +14,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
 	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -21,6 +24,7 @@ const (
 	stressEnvEnabled    = "STRESS_BENCHMARK_ENABLED"
 	stressEnvConcurrent = "STRESS_CONCURRENT"
 	stressEnvCount      = "STRESS_COUNT"
+	stressEnvPoolSize   = "STRESS_POOL_SIZE"
 
 	defaultStressConcurrent    = 100
 	defaultStressCount         = 100
@@ -28,10 +32,13 @@ const (
 	stressRequestTimeout       = 10 * time.Second
 	stressMaxConcurrentWorkers = 100_000
 	stressMaxRequestsPerWorker = 1_000_000
+	stressPoolHealthInterval   = 5 * time.Second
+	stressPoolFailureThreshold = 3
 )
 
-// BenchmarkStressHashConcurrentConnections opens one client connection per
-// parallel worker and sends STRESS_COUNT hash requests on each connection.
+// BenchmarkStressHashConcurrentConnections sends STRESS_COUNT hash requests from each of STRESS_CONCURRENT
+// parallel workers through pool of STRESS_POOL_SIZE client connections, one connection per worker by default,
+// up to pool.MaxSize connections shared by workers beyond it.
 //
 // Run through Taskfile for a single stress wave:
 //
@@ -42,7 +49,11 @@ func BenchmarkStressHashConcurrentConnections(b *testing.B) {
 	}
 
 	concurrent := stressEnvInt(b, stressEnvConcurrent, defaultStressConcurrent, 1, stressMaxConcurrentWorkers)
-	requestsPerConnection := stressEnvInt(b, stressEnvCount, defaultStressCount, 1, stressMaxRequestsPerWorker)
+	requestsPerWorker := stressEnvInt(b, stressEnvCount, defaultStressCount, 1, stressMaxRequestsPerWorker)
+	poolSize := stressEnvInt(b, stressEnvPoolSize, min(concurrent, pool.MaxSize), 1, pool.MaxSize)
+	if os.Getenv(stressEnvPoolSize) == "" && concurrent > pool.MaxSize {
+		b.Logf("%s=%d exceeds pool size limit, %d workers share %d connections", stressEnvConcurrent, concurrent, concurrent, poolSize)
+	}
 
 	previousGOMAXPROCS := runtime.GOMAXPROCS(concurrent)
 	defer runtime.GOMAXPROCS(previousGOMAXPROCS)
@@ -53,15 +64,33 @@ func BenchmarkStressHashConcurrentConnections(b *testing.B) {
 	b.SetParallelism(parallelismMultiplier)
 	b.ReportAllocs()
 
+	connectCtx, cancelConnect := context.WithTimeout(context.Background(), stressConnectionTimeout)
+	libraries, err := pool.New(connectCtx, slog.New(slog.NewTextHandler(io.Discard, nil)), pool.Config{
+		Size:             poolSize,
+		HealthInterval:   stressPoolHealthInterval,
+		FailureThreshold: stressPoolFailureThreshold,
+	})
+	cancelConnect()
+	if err != nil {
+		b.Fatalf("stress benchmark failed: open client connections: %v", err)
+	}
+
 	var totalRequests uint64
 	var statusCounts [17]atomic.Uint64
 
 	startedAt := time.Now()
-	runStressHashWave(b, concurrent, requestsPerConnection, &totalRequests, &statusCounts)
+	runStressHashWave(b, libraries, concurrent, requestsPerWorker, &totalRequests, &statusCounts)
 	elapsed := time.Since(startedAt)
 
-	b.ReportMetric(float64(concurrent), "connections")
-	b.ReportMetric(float64(requestsPerConnection), "requests/connection")
+	stats := libraries.Stats()
+	if err := libraries.Shutdown(context.Background()); err != nil {
+		b.Fatalf("stress benchmark failed: close client connections: %v", err)
+	}
+
+	b.ReportMetric(float64(poolSize), "connections")
+	b.ReportMetric(float64(stats.Evictions), "evictions")
+	// requests/connection is requests per worker, name is kept for comparison with results of one connection per worker
+	b.ReportMetric(float64(requestsPerWorker), "requests/connection")
 	b.ReportMetric(float64(totalRequests), "requests")
 	if elapsed > 0 {
 		b.ReportMetric(float64(totalRequests)/elapsed.Seconds(), "requests/s")
@@ -77,59 +106,27 @@ func BenchmarkStressHashConcurrentConnections(b *testing.B) {
 
 func runStressHashWave(
 	b *testing.B,
+	libraries *pool.Pool,
 	concurrent int,
-	requestsPerConnection int,
+	requestsPerWorker int,
 	totalRequests *uint64,
 	statusCounts *[17]atomic.Uint64,
 ) {
-	var workerSequence atomic.Int64
 	var readyWorkers atomic.Int64
-	var firstErr error
-	var firstErrMu sync.Mutex
 
 	ctx := context.Background()
 	startRequests := make(chan struct{})
-
-	recordErr := func(format string, args ...any) {
-		firstErrMu.Lock()
-		defer firstErrMu.Unlock()
-
-		if firstErr == nil {
-			firstErr = fmt.Errorf(format, args...)
-		}
-	}
+	hashData := pool.Bind(libraries, (*cryptobrokerclientgo.Library).HashData)
 
 	b.RunParallel(func(pb *testing.PB) {
-		workerID := workerSequence.Add(1)
-
-		connectCtx, cancelConnect := context.WithTimeout(ctx, stressConnectionTimeout)
-		lib, err := newStressLibrary(connectCtx)
-		cancelConnect()
-		if err != nil {
-			recordErr("worker %d: open client connection: %w", workerID, err)
-		}
-		defer func() {
-			if lib != nil {
-				if err := lib.Close(); err != nil {
-					recordErr("worker %d: close client connection: %w", workerID, err)
-				}
-			}
-		}()
-
 		if readyWorkers.Add(1) == int64(concurrent) {
 			close(startRequests)
 		}
 		<-startRequests
 
-		if err != nil {
-			for pb.Next() {
-			}
-			return
-		}
-
 		var requestWg sync.WaitGroup
 
-		for i := 0; i < requestsPerConnection; i++ {
+		for i := 0; i < requestsPerWorker; i++ {
 			requestWg.Add(1)
 
 			// Fire each individual request into its own concurrent goroutine
@@ -137,7 +134,7 @@ func runStressHashWave(
 				defer requestWg.Done()
 
 				requestCtx, cancelRequest := context.WithTimeout(ctx, stressRequestTimeout)
-				_, requestErr := lib.HashData(requestCtx, cryptobrokerclientgo.HashDataPayload{
+				_, requestErr := hashData(requestCtx, cryptobrokerclientgo.HashDataPayload{
 					Profile: "Default",
 					Input:   []byte("stress-test"),
 					Metadata: &cryptobrokerclientgo.Metadata{
@@ -155,14 +152,6 @@ func runStressHashWave(
 		for pb.Next() {
 		}
 	})
-
-	if firstErr != nil {
-		b.Fatalf("stress benchmark failed: %v", firstErr)
-	}
-}
-
-func newStressLibrary(ctx context.Context) (*cryptobrokerclientgo.Library, error) {
-	return cryptobrokerclientgo.NewLibrary(ctx)
 }
 
 func stressEnvInt(b *testing.B, key string, fallback int, minValue int, maxValue int) int {
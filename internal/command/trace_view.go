@@ -0,0 +1,40 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/traceview"
+)
+
+// TraceView represents command that renders spans exported by the CLI as terminal waterfall
+type TraceView struct {
+	output io.Writer
+}
+
+// NewTraceView initializes trace view command writing waterfall to output
+func NewTraceView(output io.Writer) (*TraceView, error) {
+	return &TraceView{output: output}, nil
+}
+
+// Run reads spans from file at path, or from stdin if path is "-", and renders waterfall of every trace.
+func (command *TraceView) Run(path string, flagWidth int, color bool) error {
+	input := io.Reader(os.Stdin)
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open spans file, err: %w", err)
+		}
+		defer file.Close()
+
+		input = file
+	}
+
+	spans, err := traceview.Parse(input)
+	if err != nil {
+		return err
+	}
+
+	return traceview.Render(command.output, spans, traceview.Options{Width: flagWidth, Color: color})
+}
@@ -1,5 +1,7 @@
 package constant
 
+import "time"
+
 // constants that represents keywords behind the flags of the CLI.
 const (
 	KeywordFlagProfile            = "profile"
@@ -10,6 +12,104 @@ const (
 	KeywordFlagFilePathCSR        = "csr"
 	KeywordFlagFilePathCACert     = "caCert"
 	KeywordFlagFilePathSigningKey = "caKey"
+	KeywordFlagFailOnCircuitOpen  = "fail-on-circuit-open"
+	KeywordFlagWait               = "wait"
+	KeywordFlagTimeout            = "timeout"
+	KeywordFlagInterval           = "interval"
+	KeywordFlagListen             = "listen"
+	KeywordFlagCacheTTL           = "cache-ttl"
+	KeywordFlagFailureThreshold   = "failure-threshold"
+	KeywordFlagFormat             = "format"
+	KeywordFlagWarning            = "warning"
+	KeywordFlagCritical           = "critical"
+)
+
+// constants that represents keywords behind the sign certificate flags of the CLI.
+const (
+	KeywordFlagAllowInsecureKey = "allow-insecure-key"
+)
+
+// constants that represents keywords behind the benchmark flags of the CLI.
+const (
+	KeywordFlagCount     = "count"
+	KeywordFlagSave      = "save"
+	KeywordFlagThreshold = "threshold"
+	KeywordFlagAlpha     = "alpha"
+)
+
+// constants that represents keywords behind the client-side benchmark flags of the CLI.
+const (
+	KeywordFlagOperations    = "operations"
+	KeywordFlagProfiles      = "profiles"
+	KeywordFlagSizes         = "sizes"
+	KeywordFlagOutputFormats = "output-formats"
+	KeywordFlagKeyTypes      = "key-types"
+	KeywordFlagConcurrency   = "concurrency"
+	KeywordFlagRequests      = "requests"
+)
+
+// constants that represents keywords behind the audit flags of the CLI.
+const (
+	KeywordFlagHead   = "head"
+	KeywordFlagAnchor = "anchor"
+)
+
+// constants that represents keywords behind the shell flags of the CLI.
+const (
+	KeywordFlagHistoryFile = "history-file"
+)
+
+// constants that represents keywords behind the gateway flags of the CLI.
+const (
+	KeywordFlagMaxRequestSize       = "max-request-size"
+	KeywordFlagMaxBenchmarkRequests = "max-benchmark-requests"
+)
+
+// constants that represents keywords behind the library pool flags of the CLI.
+const (
+	KeywordFlagPoolSize             = "pool-size"
+	KeywordFlagPoolHealthInterval   = "pool-health-interval"
+	KeywordFlagPoolFailureThreshold = "pool-failure-threshold"
+)
+
+// constants that represents keywords behind the assert flags of the CLI.
+const (
+	KeywordFlagSLO   = "slo"
+	KeywordFlagJUnit = "junit"
+)
+
+// constants that represents keywords behind the trace view flags of the CLI.
+const (
+	KeywordFlagWidth   = "width"
+	KeywordFlagNoColor = "no-color"
+)
+
+// constants that represents keywords behind the retry flags of the CLI.
+const (
+	KeywordFlagRetries       = "retries"
+	KeywordFlagRetryBackoff  = "retry-backoff"
+	KeywordFlagRetryMaxDelay = "retry-max-delay"
+	KeywordFlagRetryOn       = "retry-on"
+)
+
+// constants that represents keywords behind the persistent telemetry flags of the CLI.
+const (
+	KeywordFlagMetricsListen = "metrics-listen"
+	KeywordFlagTraceParent   = "traceparent"
+	KeywordFlagCorrelationId = "correlation-id"
+)
+
+// constants that represents keywords behind the fault injection flags of the CLI.
+const (
+	KeywordFlagDropRate            = "drop-rate"
+	KeywordFlagLatency             = "latency"
+	KeywordFlagLatencyJitter       = "latency-jitter"
+	KeywordFlagLatencyDistribution = "latency-distribution"
+	KeywordFlagCancelRate          = "cancel-rate"
+	KeywordFlagCancelAfter         = "cancel-after"
+	KeywordFlagCorruptRate         = "corrupt-rate"
+	KeywordFlagCorruptSize         = "corrupt-size"
+	KeywordFlagChaosSeed           = "chaos-seed"
 )
 
 // constants that represents supported encodings.
@@ -18,6 +118,37 @@ const (
 	EncodingDER = "der"
 )
 
+// constants that represents supported health output formats.
+const (
+	HealthFormatText   = "text"
+	HealthFormatNagios = "nagios"
+)
+
+// constants that represents supported benchmark output formats.
+const (
+	BenchmarkFormatTable   = "table"
+	BenchmarkFormatJSON    = "json"
+	BenchmarkFormatCSV     = "csv"
+	BenchmarkFormatGoBench = "gobench"
+)
+
+// constants that represents operations supported by client-side benchmark.
+const (
+	BenchOperationHash = "hash"
+	BenchOperationSign = "sign"
+)
+
+// constants that represents key types of client-side sign certificate benchmark.
+const (
+	KeyTypeP256    = "P256"
+	KeyTypeP384    = "P384"
+	KeyTypeP521    = "P521"
+	KeyTypeRSA2048 = "RSA2048"
+	KeyTypeRSA3072 = "RSA3072"
+	KeyTypeRSA4096 = "RSA4096"
+	KeyTypeEd25519 = "Ed25519"
+)
+
 // constants that represents supported loop flag values.
 const (
 	MinLoopFlagValue = 1
@@ -25,4 +156,93 @@ const (
 	NoLoopFlagValue  = -1000001
 )
 
+// constants that represents process exit codes.
+const (
+	// ExitCodeCircuitOpen is returned when the final request was short-circuited by the circuit breaker (EX_TEMPFAIL).
+	ExitCodeCircuitOpen = 75
+
+	// ExitCodeHealthNotServing is returned when broker reports NOT_SERVING health status.
+	ExitCodeHealthNotServing = 1
+
+	// ExitCodeHealthUnknown is returned when broker health status is UNKNOWN or the broker cannot be reached.
+	ExitCodeHealthUnknown = 3
+
+	// ExitCodeBenchmarkRegression is returned when benchmark comparison detects significant regression above threshold.
+	ExitCodeBenchmarkRegression = 1
+
+	// ExitCodeSLOViolated is returned when benchmark results do not meet service level objectives.
+	ExitCodeSLOViolated = 1
+
+	// ExitCodeAuditChainBroken is returned when hash chain of audit log is broken.
+	ExitCodeAuditChainBroken = 1
+)
+
+// constants that represents exit codes of health command in monitoring plugin (Nagios/Icinga) format.
+const (
+	ExitCodeNagiosOK       = 0
+	ExitCodeNagiosWarning  = 1
+	ExitCodeNagiosCritical = 2
+	ExitCodeNagiosUnknown  = 3
+)
+
+// constants that represents defaults of health command flags.
+const (
+	DefaultHealthWaitTimeout  = 60 * time.Second
+	DefaultHealthWaitInterval = time.Second
+)
+
+// constants that represents defaults of health serve command flags.
+const (
+	DefaultHealthServeListen           = ":8086"
+	DefaultHealthServeInterval         = 5 * time.Second
+	DefaultHealthServeCacheTTL         = 15 * time.Second
+	DefaultHealthServeFailureThreshold = 3
+)
+
+// constants that represents defaults of benchmark compare command flags.
+const (
+	DefaultBenchmarkCompareThreshold = 10.0
+	DefaultBenchmarkCompareAlpha     = 0.05
+)
+
+// constants that represents defaults of client-side benchmark command flags.
+const (
+	DefaultBenchClientRequests = 100
+	MaxBenchClientSize         = 256 << 20
+	MaxBenchClientConcurrency  = 10_000
+)
+
+// constants that represents defaults of assert command flags.
+const (
+	DefaultAssertSLOFile = "slo.yaml"
+)
+
+// constants that represents defaults of shell command.
+const (
+	MaxShellHistory = 1000
+)
+
+// constants that represents defaults of gateway command flags.
+const (
+	DefaultGatewayListen               = "127.0.0.1:8080"
+	DefaultGatewayMaxRequestSize       = "1MiB"
+	DefaultGatewayMaxBenchmarkRequests = 10_000
+	// MaxGatewayBenchmarkSize is default gRPC message size limit, larger inputs cannot reach the broker
+	MaxGatewayBenchmarkSize = 4 << 20
+)
+
+// constants that represents defaults of library pool flags.
+const (
+	DefaultPoolSize             = 4
+	DefaultPoolHealthInterval   = 10 * time.Second
+	DefaultPoolFailureThreshold = 3
+	// PoolDrainTimeout bounds waiting for requests in flight when library pool is shut down
+	PoolDrainTimeout = 30 * time.Second
+)
+
+// constants that represents defaults of trace view command flags.
+const (
+	DefaultTraceViewWidth = 40
+)
+
 const ClientGoModulePath = "github.com/open-crypto-broker/crypto-broker-client-go"
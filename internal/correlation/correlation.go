@@ -0,0 +1,27 @@
+// Package correlation contains correlation id that identifies a single CLI invocation across client and server logs.
+package correlation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// contextKey is the key of correlation id in context
+type contextKey struct{}
+
+// NewID generates new correlation id.
+func NewID() string {
+	return uuid.New().String()
+}
+
+// ContextWithID returns ctx carrying given correlation id.
+func ContextWithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// IDFromContext returns correlation id carried by ctx or empty string if there is none.
+func IDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
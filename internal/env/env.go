@@ -3,11 +3,17 @@ package env
 
 const (
 	// OTEL_TRACES_EXPORTER is OpenTelemetry environment variable that specifies the trace exporter(s) to use.
-	// Can be "otlp", "console", "both", or comma-separated list like "console,otlp".
+	// Valid values: "console", "otlp", "otlphttp", "otlpgrpc", "file", or comma-separated list like "console,otlp".
+	// Transport of "otlp" is resolved from OTEL_EXPORTER_OTLP_PROTOCOL.
 	OTEL_TRACES_EXPORTER = "OTEL_TRACES_EXPORTER"
 
+	// OTEL_METRICS_EXPORTER is OpenTelemetry environment variable that specifies the metric exporter(s) to use.
+	// Valid values: "none", "console", "otlp", "otlphttp", "otlpgrpc", or comma-separated list like "console,otlpgrpc".
+	// If not set, metrics are not exported, unless exposed to Prometheus with --metrics-listen.
+	OTEL_METRICS_EXPORTER = "OTEL_METRICS_EXPORTER"
+
 	// OTEL_EXPORTER_OTLP_ENDPOINT is OpenTelemetry environment variable that specifies the OTLP endpoint.
-	// For gRPC OTLP, use format "host:port". For HTTP OTLP, use "http://host:port".
+	// For gRPC OTLP, use format "host:port". For HTTP OTLP, use "http://host:port", "/v1/<signal>" is appended to its path.
 	OTEL_EXPORTER_OTLP_ENDPOINT = "OTEL_EXPORTER_OTLP_ENDPOINT"
 
 	// OTEL_TRACES_SAMPLER is OpenTelemetry environment variable that specifies the sampling strategy.
@@ -29,13 +35,103 @@ const (
 	// Example: OTEL_EXPORTER_OTLP_HEADERS_AUTHORIZATION="Api-Token dt0c01.xxx..."
 	OTEL_EXPORTER_OTLP_HEADERS_AUTHORIZATION = "OTEL_EXPORTER_OTLP_HEADERS_AUTHORIZATION"
 
+	// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is OpenTelemetry environment variable that overrides OTLP endpoint of traces.
+	// Unlike OTEL_EXPORTER_OTLP_ENDPOINT, its path is used as is, e.g. "https://collector:4318/custom/traces".
+	OTEL_EXPORTER_OTLP_TRACES_ENDPOINT = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+
+	// OTEL_EXPORTER_OTLP_METRICS_ENDPOINT is OpenTelemetry environment variable that overrides OTLP endpoint of metrics.
+	OTEL_EXPORTER_OTLP_METRICS_ENDPOINT = "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT"
+
+	// OTEL_EXPORTER_OTLP_LOGS_ENDPOINT is OpenTelemetry environment variable that overrides OTLP endpoint of logs.
+	OTEL_EXPORTER_OTLP_LOGS_ENDPOINT = "OTEL_EXPORTER_OTLP_LOGS_ENDPOINT"
+
+	// OTEL_EXPORTER_OTLP_HEADERS is OpenTelemetry environment variable with headers sent with every OTLP export,
+	// as comma-separated list of URL-encoded key=value pairs, e.g. "Authorization=Api-Token%20xxx,x-tenant=dev".
+	OTEL_EXPORTER_OTLP_HEADERS = "OTEL_EXPORTER_OTLP_HEADERS"
+
+	// OTEL_EXPORTER_OTLP_PROTOCOL is OpenTelemetry environment variable that specifies OTLP transport
+	// of "otlp" exporters. Valid values: "grpc", "http/protobuf".
+	// If not set, HTTP is used for endpoints with scheme and gRPC for "host:port" endpoints.
+	OTEL_EXPORTER_OTLP_PROTOCOL = "OTEL_EXPORTER_OTLP_PROTOCOL"
+
+	// OTEL_EXPORTER_OTLP_INSECURE is OpenTelemetry environment variable that disables TLS for endpoints without scheme.
+	// If not set, gRPC endpoints without scheme are insecure unless TLS certificates are configured.
+	OTEL_EXPORTER_OTLP_INSECURE = "OTEL_EXPORTER_OTLP_INSECURE"
+
+	// OTEL_EXPORTER_OTLP_CERTIFICATE is OpenTelemetry environment variable with path to PEM encoded
+	// CA certificates used to verify OTLP endpoint certificate.
+	OTEL_EXPORTER_OTLP_CERTIFICATE = "OTEL_EXPORTER_OTLP_CERTIFICATE"
+
+	// OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE is OpenTelemetry environment variable with path to PEM encoded
+	// client certificate used for mTLS. It must be set together with OTEL_EXPORTER_OTLP_CLIENT_KEY.
+	OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE = "OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE"
+
+	// OTEL_EXPORTER_OTLP_CLIENT_KEY is OpenTelemetry environment variable with path to PEM encoded
+	// private key of client certificate used for mTLS.
+	OTEL_EXPORTER_OTLP_CLIENT_KEY = "OTEL_EXPORTER_OTLP_CLIENT_KEY"
+
+	// OTEL_EXPORTER_OTLP_COMPRESSION is OpenTelemetry environment variable that specifies compression of OTLP exports.
+	// Valid values: "gzip", "none".
+	OTEL_EXPORTER_OTLP_COMPRESSION = "OTEL_EXPORTER_OTLP_COMPRESSION"
+
+	// OTEL_EXPORTER_OTLP_TIMEOUT is OpenTelemetry environment variable that specifies
+	// timeout of single OTLP export in milliseconds.
+	OTEL_EXPORTER_OTLP_TIMEOUT = "OTEL_EXPORTER_OTLP_TIMEOUT"
+
+	// TRACEPARENT is W3C trace context environment variable with the parent span of the caller,
+	// e.g. "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01". CLI spans become its children.
+	TRACEPARENT = "TRACEPARENT"
+
+	// TRACESTATE is W3C trace context environment variable with vendor specific trace state of the caller.
+	TRACESTATE = "TRACESTATE"
+
+	// BAGGAGE is W3C baggage environment variable, e.g. "ci.job=42,team=crypto".
+	// Its entries are forwarded to the broker as "baggage" gRPC metadata.
+	BAGGAGE = "BAGGAGE"
+
 	// OTEL_LOGS_EXPORTER is OpenTelemetry environment variable that specifies the log exporter(s) to use.
 	// Supports comma-separated values for multiple exporters.
-	// Valid values: "console", "otlp", "otlphttp", "otlpgrpc", or combinations like "otlp,console".
+	// Valid values: "console", "otlp", "otlphttp", "otlpgrpc", "file", or combinations like "otlp,console".
 	// If not set or empty, console logging will be used as default.
 	// Examples: "console", "otlp", "otlphttp", "otlpgrpc", "otlphttp,console"
 	OTEL_LOGS_EXPORTER = "OTEL_LOGS_EXPORTER"
 
+	// OTEL_TRACES_FILE is environment variable with path of the file "file" trace exporter appends OTLP-JSON lines to.
+	// If not set, "crypto-broker-traces.jsonl" in working directory is used.
+	OTEL_TRACES_FILE = "CRYPTO_BROKER_OTEL_TRACES_FILE"
+
+	// OTEL_LOGS_FILE is environment variable with path of the file "file" log exporter appends OTLP-JSON lines to.
+	// If not set, "crypto-broker-logs.jsonl" in working directory is used.
+	OTEL_LOGS_FILE = "CRYPTO_BROKER_OTEL_LOGS_FILE"
+
+	// OTEL_FILE_MAX_SIZE_MB is environment variable with size in megabytes after which telemetry file is rotated.
+	// If not set, files are rotated after 100 MB, 0 disables rotation.
+	OTEL_FILE_MAX_SIZE_MB = "CRYPTO_BROKER_OTEL_FILE_MAX_SIZE_MB"
+
+	// OTEL_FILE_MAX_BACKUPS is environment variable with number of rotated telemetry files kept next to the file,
+	// named "<path>.1" (newest) to "<path>.N". If not set, 5 rotated files are kept.
+	OTEL_FILE_MAX_BACKUPS = "CRYPTO_BROKER_OTEL_FILE_MAX_BACKUPS"
+
+	// BENCHMARK_DIR is environment variable with path of the directory benchmark results are saved to with --save
+	// and loaded from by benchmark compare. If not set, ".benchmarks" in working directory is used.
+	BENCHMARK_DIR = "CRYPTO_BROKER_BENCHMARK_DIR"
+
+	// AUDIT_LOG is environment variable with path of JSONL file every certificate signing request is appended to,
+	// or "syslog" to send entries to local syslog (authpriv facility). If not set, signing requests are not audited.
+	AUDIT_LOG = "CRYPTO_BROKER_AUDIT_LOG"
+
+	// AUDIT_HEAD_FILE is environment variable with path of the file tracking the last entry of audit chain sent to syslog.
+	// If not set, "crypto-broker-cli/audit.head" in user configuration directory is used.
+	AUDIT_HEAD_FILE = "CRYPTO_BROKER_AUDIT_HEAD_FILE"
+
+	// GATEWAY_TOKEN is environment variable with bearer token required by gateway endpoints, except health
+	// and OpenAPI spec. If not set, gateway does not authenticate requests.
+	GATEWAY_TOKEN = "CRYPTO_BROKER_GATEWAY_TOKEN"
+
+	// CORRELATION_ID is environment variable that should contain correlation id of the CLI invocation.
+	// It is sent with every request and added to spans and log records. If not set, a random id is generated.
+	CORRELATION_ID = "CRYPTO_BROKER_CORRELATION_ID"
+
 	// LOG_LEVEL is environment variable that should contain log level.
 	// Valid values are denoted in internal/clog package
 	LOG_LEVEL = "CRYPTO_BROKER_LOG_LEVEL"
@@ -44,6 +140,11 @@ const (
 	// Valid values are denoted in internal/clog package
 	LOG_FORMAT = "CRYPTO_BROKER_LOG_FORMAT"
 
+	// LOG_REDACT is environment variable that should contain redaction policy of sensitive log attributes,
+	// e.g. hash input or CA private key: "full" replaces them with "[REDACTED]" (default),
+	// "digest" with their SHA-256 digest and "length" with their length.
+	LOG_REDACT = "CRYPTO_BROKER_LOG_REDACT"
+
 	// LOG_OUTPUT is environment variable that should contain log output.
 	// Valid values are denoted in internal/clog package
 	LOG_OUTPUT = "CRYPTO_BROKER_LOG_OUTPUT"
@@ -1,5 +1,7 @@
 package flags
 
+import "time"
+
 // flags that represents CLI flags.
 var (
 	Loop               int
@@ -10,4 +12,110 @@ var (
 	FilePathCSR        string
 	FilePathCACert     string
 	FilePathSigningKey string
+	AllowInsecureKey   bool
+	FailOnCircuitOpen  bool
+	Wait               bool
+	Timeout            time.Duration
+	Interval           time.Duration
+)
+
+// flags that represents health output format CLI flags.
+var (
+	HealthFormat   string
+	HealthWarning  time.Duration
+	HealthCritical time.Duration
+)
+
+// flags that represents health serve CLI flags.
+var (
+	HealthServeListen           string
+	HealthServeInterval         time.Duration
+	HealthServeCacheTTL         time.Duration
+	HealthServeFailureThreshold int
+)
+
+// flags that represents benchmark CLI flags.
+var (
+	BenchmarkFormat    string
+	BenchmarkCount     int
+	BenchmarkSave      string
+	BenchmarkThreshold float64
+	BenchmarkAlpha     float64
+)
+
+// flags that represents client-side benchmark CLI flags.
+var (
+	BenchClientOperations    []string
+	BenchClientProfiles      []string
+	BenchClientSizes         []string
+	BenchClientOutputFormats []string
+	BenchClientKeyTypes      []string
+	BenchClientConcurrency   []int
+	BenchClientRequests      int
+)
+
+// flags that represents assert CLI flags.
+var (
+	AssertSLOFile   string
+	AssertJUnitFile string
+)
+
+// flags that represents audit verify CLI flags.
+var (
+	AuditVerifyHead   string
+	AuditVerifyAnchor string
+)
+
+// flags that represents shell CLI flags.
+var (
+	ShellProfiles    []string
+	ShellHistoryFile string
+)
+
+// flags that represents gateway CLI flags.
+var (
+	GatewayListen               string
+	GatewayMaxRequestSize       string
+	GatewayMaxBenchmarkRequests int
+)
+
+// flags that represents library pool CLI flags of commands issuing concurrent requests.
+var (
+	PoolSize             int
+	PoolHealthInterval   time.Duration
+	PoolFailureThreshold int
+)
+
+// flags that represents trace view CLI flags.
+var (
+	TraceViewWidth   int
+	TraceViewNoColor bool
+)
+
+// flags that represents fault injection CLI flags.
+var (
+	DropRate            float64
+	Latency             time.Duration
+	LatencyJitter       time.Duration
+	LatencyDistribution string
+	CancelRate          float64
+	CancelAfter         time.Duration
+	CorruptRate         float64
+	CorruptSize         int
+	ChaosSeed           uint64
+)
+
+// flags that represents persistent retry CLI flags.
+var (
+	Retries       int
+	RetryBackoff  time.Duration
+	RetryMaxDelay time.Duration
+	RetryOn       []string
+)
+
+// flags that represents persistent telemetry CLI flags.
+var (
+	MetricsListen string
+	TraceParent   string
+	CorrelationId string
 )
@@ -2,8 +2,15 @@ package flags
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/chaos"
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/constant"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/pool"
 )
 
 // ValidateFlagLoop validates loop flag value.
@@ -27,3 +34,183 @@ func ValidateFlagEncoding(val string) error {
 
 	return nil
 }
+
+// ValidateFlagPositiveDuration validates that duration flag of given name is positive.
+func ValidateFlagPositiveDuration(name string, val time.Duration) error {
+	if val <= 0 {
+		return fmt.Errorf("'%s' flag value must be positive", name)
+	}
+
+	return nil
+}
+
+// ValidateFlagHealthServeIntervals validates health check interval and cache TTL of health probe server.
+// Interval must not exceed cache TTL, otherwise cached result goes stale before next check completes.
+func ValidateFlagHealthServeIntervals(interval time.Duration, cacheTTL time.Duration) error {
+	if err := ValidateFlagPositiveDuration(constant.KeywordFlagInterval, interval); err != nil {
+		return err
+	}
+
+	if err := ValidateFlagPositiveDuration(constant.KeywordFlagCacheTTL, cacheTTL); err != nil {
+		return err
+	}
+
+	if interval > cacheTTL {
+		return fmt.Errorf("'%s' flag value must not be greater than '%s' flag value", constant.KeywordFlagInterval, constant.KeywordFlagCacheTTL)
+	}
+
+	return nil
+}
+
+// ValidateFlagHealthFormat validates health output format flag value.
+func ValidateFlagHealthFormat(val string) error {
+	if val != constant.HealthFormatText && val != constant.HealthFormatNagios {
+		return fmt.Errorf("'%s' flag value must be %s or %s", constant.KeywordFlagFormat, constant.HealthFormatText, constant.HealthFormatNagios)
+	}
+
+	return nil
+}
+
+// ValidateFlagBenchmarkFormat validates benchmark output format flag value.
+func ValidateFlagBenchmarkFormat(val string) error {
+	switch val {
+	case constant.BenchmarkFormatTable, constant.BenchmarkFormatJSON, constant.BenchmarkFormatCSV, constant.BenchmarkFormatGoBench:
+		return nil
+	}
+
+	return fmt.Errorf("'%s' flag value must be %s, %s, %s or %s", constant.KeywordFlagFormat,
+		constant.BenchmarkFormatTable, constant.BenchmarkFormatJSON, constant.BenchmarkFormatCSV, constant.BenchmarkFormatGoBench)
+}
+
+// benchmarkNamePattern matches names benchmark results can be saved under, as they are used as file names.
+var benchmarkNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateFlagBenchmarkSave validates name of saved benchmark results, empty name disables saving.
+func ValidateFlagBenchmarkSave(val string) error {
+	if val != "" && !benchmarkNamePattern.MatchString(val) {
+		return fmt.Errorf("'%s' flag value must start with letter or digit and contain only letters, digits, '.', '_' or '-'", constant.KeywordFlagSave)
+	}
+
+	return nil
+}
+
+// ValidateFlagBenchmarkCompare validates regression threshold in percent and significance level of benchmark comparison.
+func ValidateFlagBenchmarkCompare(threshold float64, alpha float64) error {
+	if threshold < 0 {
+		return fmt.Errorf("'%s' flag value must not be negative", constant.KeywordFlagThreshold)
+	}
+
+	if alpha <= 0 || alpha > 1 {
+		return fmt.Errorf("'%s' flag value must be greater than 0 and at most 1", constant.KeywordFlagAlpha)
+	}
+
+	return nil
+}
+
+// ValidateFlagLatencyThresholds validates warning and critical latency thresholds, zero disables a threshold.
+func ValidateFlagLatencyThresholds(warning time.Duration, critical time.Duration) error {
+	if warning < 0 || critical < 0 {
+		return fmt.Errorf("'%s' and '%s' flag values must not be negative", constant.KeywordFlagWarning, constant.KeywordFlagCritical)
+	}
+
+	if warning > 0 && critical > 0 && critical < warning {
+		return fmt.Errorf("'%s' flag value must not be lower than '%s' flag value", constant.KeywordFlagCritical, constant.KeywordFlagWarning)
+	}
+
+	return nil
+}
+
+// ValidateFlagPositiveInt validates that integer flag of given name is positive.
+func ValidateFlagPositiveInt(name string, val int) error {
+	if val <= 0 {
+		return fmt.Errorf("'%s' flag value must be positive", name)
+	}
+
+	return nil
+}
+
+// ValidateFlagRate validates percentage flag value of given name.
+func ValidateFlagRate(name string, val float64) error {
+	if err := chaos.ValidateRate(val); err != nil {
+		return fmt.Errorf("'%s' flag value is invalid: %w", name, err)
+	}
+
+	return nil
+}
+
+// ValidateFlagLatencyDistribution validates latency distribution flag value.
+func ValidateFlagLatencyDistribution(val string) error {
+	if err := chaos.ValidateDistribution(val); err != nil {
+		return fmt.Errorf("'%s' flag value is invalid: %w", constant.KeywordFlagLatencyDistribution, err)
+	}
+
+	return nil
+}
+
+// ValidateFlagBenchClient validates client-side benchmark flag values, sizes are validated by ParseFlagSizes.
+func ValidateFlagBenchClient(operations []string, outputFormats []string, keyTypes []string, concurrency []int, requests int) error {
+	for _, operation := range operations {
+		if operation != constant.BenchOperationHash && operation != constant.BenchOperationSign {
+			return fmt.Errorf("'%s' flag values must be %s or %s", constant.KeywordFlagOperations, constant.BenchOperationHash, constant.BenchOperationSign)
+		}
+	}
+
+	for _, outputFormat := range outputFormats {
+		if outputFormat != "hex" && outputFormat != "raw" {
+			return fmt.Errorf("'%s' flag values must be hex or raw", constant.KeywordFlagOutputFormats)
+		}
+	}
+
+	keyTypeValues := []string{constant.KeyTypeP256, constant.KeyTypeP384, constant.KeyTypeP521,
+		constant.KeyTypeRSA2048, constant.KeyTypeRSA3072, constant.KeyTypeRSA4096, constant.KeyTypeEd25519}
+	for _, keyType := range keyTypes {
+		if !slices.Contains(keyTypeValues, keyType) {
+			return fmt.Errorf("'%s' flag values must be one of %s", constant.KeywordFlagKeyTypes, strings.Join(keyTypeValues, ", "))
+		}
+	}
+
+	for _, val := range concurrency {
+		if val < 1 || val > constant.MaxBenchClientConcurrency {
+			return fmt.Errorf("'%s' flag values must be between 1 and %d", constant.KeywordFlagConcurrency, constant.MaxBenchClientConcurrency)
+		}
+	}
+
+	return ValidateFlagPositiveInt(constant.KeywordFlagRequests, requests)
+}
+
+// ValidateFlagPool validates library pool size, health check interval and failure threshold flag values.
+func ValidateFlagPool(size int, healthInterval time.Duration, failureThreshold int) error {
+	if size < 1 || size > pool.MaxSize {
+		return fmt.Errorf("'%s' flag value must be between 1 and %d", constant.KeywordFlagPoolSize, pool.MaxSize)
+	}
+
+	if healthInterval < 0 {
+		return fmt.Errorf("'%s' flag value must not be negative", constant.KeywordFlagPoolHealthInterval)
+	}
+
+	return ValidateFlagPositiveInt(constant.KeywordFlagPoolFailureThreshold, failureThreshold)
+}
+
+// sizePattern matches size with optional binary unit, e.g. 16B, 64KiB or 1MiB
+var sizePattern = regexp.MustCompile(`^(\d+)(B|KiB|MiB|GiB)?$`)
+
+// ParseFlagSizes parses sizes with optional binary unit, e.g. 16B, 64KiB or 1MiB, to number of bytes.
+func ParseFlagSizes(vals []string) ([]int, error) {
+	units := map[string]int{"": 1, "B": 1, "KiB": 1 << 10, "MiB": 1 << 20, "GiB": 1 << 30}
+	sizes := make([]int, 0, len(vals))
+	for _, val := range vals {
+		matches := sizePattern.FindStringSubmatch(strings.TrimSpace(val))
+		if matches == nil {
+			return nil, fmt.Errorf("'%s' flag value %q must be number of bytes with optional B, KiB, MiB or GiB unit", constant.KeywordFlagSizes, val)
+		}
+
+		size, err := strconv.Atoi(matches[1])
+		if err != nil || size < 1 || size > constant.MaxBenchClientSize/units[matches[2]] {
+			return nil, fmt.Errorf("'%s' flag value %q must be between 1B and %dMiB", constant.KeywordFlagSizes, val, constant.MaxBenchClientSize>>20)
+		}
+
+		sizes = append(sizes, size*units[matches[2]])
+	}
+
+	return sizes, nil
+}
@@ -3,16 +3,40 @@ package otel
 import "go.opentelemetry.io/otel/attribute"
 
 var (
-	AttributeRpcMethod                  = attribute.Key("rpc.method")
-	AttributeCryptoProfile              = attribute.Key("crypto.profile")
-	AttributeCryptoInputSize            = attribute.Key("crypto.input_size")
-	AttributeCryptoHashAlgorithm        = attribute.Key("crypto.hash_algorithm")
-	AttributeCryptoHashOutputSize       = attribute.Key("crypto.hash_output_size")
-	AttributeCryptoHashOutputFormat     = attribute.Key("crypto.hash_output_format")
-	AttributeCryptoSignedCertSize       = attribute.Key("crypto.signed_cert_size")
-	AttributeCryptoBenchmarkResultsSize = attribute.Key("crypto.benchmark_results_size")
-	AttributeCryptoCsrSize              = attribute.Key("crypto.csr_size")
-	AttributeCryptoCaCertSize           = attribute.Key("crypto.ca_cert_size")
-	AttributeCryptoCaKeySize            = attribute.Key("crypto.ca_key_size")
-	AttributeCorrelationId              = attribute.Key("correlation_id")
+	AttributeRpcMethod              = attribute.Key("rpc.method")
+	AttributeCryptoProfile          = attribute.Key("crypto.profile")
+	AttributeCryptoInputSize        = attribute.Key("crypto.input_size")
+	AttributeCryptoHashAlgorithm    = attribute.Key("crypto.hash_algorithm")
+	AttributeCryptoHashOutputSize   = attribute.Key("crypto.hash_output_size")
+	AttributeCryptoHashOutputFormat = attribute.Key("crypto.hash_output_format")
+	AttributeCryptoSignedCertSize   = attribute.Key("crypto.signed_cert_size")
+	AttributeCryptoCsrSize          = attribute.Key("crypto.csr_size")
+	AttributeCryptoCaCertSize       = attribute.Key("crypto.ca_cert_size")
+	AttributeCryptoCaKeySize        = attribute.Key("crypto.ca_key_size")
+	AttributeCorrelationId          = attribute.Key("correlation_id")
+	AttributeHealthStatus           = attribute.Key("health.status")
+	AttributeChaosDropped           = attribute.Key("chaos.dropped")
+	AttributeChaosDelayMs           = attribute.Key("chaos.delay_ms")
+	AttributeChaosCancelAfterMs     = attribute.Key("chaos.cancel_after_ms")
+	AttributeChaosCorruptSize       = attribute.Key("chaos.corrupt_size")
+	AttributeRequestResult          = attribute.Key("request.result")
+	AttributeGrpcCode               = attribute.Key("rpc.grpc.code")
+)
+
+// attributes of client-side benchmark spans
+var (
+	AttributeBenchKeyType     = attribute.Key("bench.key_type")
+	AttributeBenchConcurrency = attribute.Key("bench.concurrency")
+	AttributeBenchRequests    = attribute.Key("bench.requests")
+	AttributeBenchErrors      = attribute.Key("bench.errors")
+	AttributeBenchThroughput  = attribute.Key("bench.throughput_rps")
+	AttributeBenchLatencyP99  = attribute.Key("bench.latency_p99_us")
+)
+
+// attributes of gateway spans
+var (
+	AttributeHttpRequestMethod      = attribute.Key("http.request.method")
+	AttributeHttpRoute              = attribute.Key("http.route")
+	AttributeHttpResponseStatusCode = attribute.Key("http.response.status_code")
+	AttributeHttpRequestBodySize    = attribute.Key("http.request.body.size")
 )
@@ -2,9 +2,10 @@ package otel
 
 // default values for OTEL configurations
 const (
-	defaultServiceName    = "crypto-broker-cli-go"
-	defaultServiceVersion = "unknown service version"
-	defaultTracesExporter = "console"
+	defaultServiceName     = "crypto-broker-cli-go"
+	defaultServiceVersion  = "unknown service version"
+	defaultTracesExporter  = "console"
+	defaultMetricsExporter = "none"
 )
 
 // keys representing OTEL exporters
@@ -12,6 +13,8 @@ const (
 	keyExporterOTLPGRPC = "otlpgrpc"
 	keyExporterConsole  = "console"
 	keyExporterOTLPHTTP = "otlphttp"
+	keyExporterOTLP     = "otlp"
+	keyExporterFile     = "file"
 )
 
 // sampler names
@@ -1,21 +1,24 @@
 package otel
 
 import (
+	"io"
 	"os"
 
 	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
 )
 
 var (
-	serviceName    = defaultServiceName
-	serviceVersion = defaultServiceVersion
-	tracesExporter = defaultTracesExporter
-	otlpEndpoint   = ""
-	apiToken       = ""
-	samplerName    = samplerAlwaysOn
-	samplingRatio  = 1.0
+	serviceName     = defaultServiceName
+	serviceVersion  = defaultServiceVersion
+	tracesExporter  = defaultTracesExporter
+	metricsExporter = defaultMetricsExporter
+	samplerName     = samplerAlwaysOn
+	samplingRatio   = 1.0
 )
 
+// consoleOutput is writer of console span and metric exporters
+var consoleOutput io.Writer = os.Stdout
+
 func init() {
 	if customServiceName := os.Getenv(env.OTEL_SERVICE_NAME); customServiceName != "" {
 		serviceName = customServiceName
@@ -29,6 +32,10 @@ func init() {
 		tracesExporter = customTracesExporter
 	}
 
+	if customMetricsExporter := os.Getenv(env.OTEL_METRICS_EXPORTER); customMetricsExporter != "" {
+		metricsExporter = customMetricsExporter
+	}
+
 	if customSamplerName := os.Getenv(env.OTEL_TRACES_SAMPLER); customSamplerName != "" {
 		samplerName = customSamplerName
 	}
@@ -38,7 +45,4 @@ func init() {
 			samplingRatio = parsedRatio
 		}
 	}
-
-	apiToken = os.Getenv(env.OTEL_EXPORTER_OTLP_HEADERS_AUTHORIZATION)
-	otlpEndpoint = os.Getenv(env.OTEL_EXPORTER_OTLP_ENDPOINT)
 }
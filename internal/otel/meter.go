@@ -0,0 +1,250 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otlpconfig"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"google.golang.org/grpc/credentials"
+)
+
+// metricsPath is the path Prometheus metrics are served on
+const metricsPath = "/metrics"
+
+// MeterProvider holds the OpenTelemetry meter provider and the Prometheus metrics server, if any
+type MeterProvider struct {
+	mp     *sdkmetric.MeterProvider
+	server *http.Server
+}
+
+// NewMeterProvider creates and initializes a new OpenTelemetry meter provider and sets it as global.
+// Push exporters are configured by OTEL_METRICS_EXPORTER, their export interval by OTEL_METRIC_EXPORT_INTERVAL.
+// If metricsListen is not empty, metrics are also exposed in Prometheus format on metricsListen address.
+func NewMeterProvider(ctx context.Context, logger *slog.Logger, metricsListen string) (*MeterProvider, error) {
+	exporterNames := strings.Split(strings.ToLower(metricsExporter), ",")
+	for i, name := range exporterNames {
+		exporterNames[i] = strings.TrimSpace(name)
+	}
+
+	var readers []sdkmetric.Option
+	for _, protocol := range otlpProtocols(exporterNames) {
+		config, err := otlpconfig.Load(otlpconfig.SignalMetrics, protocol)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTLP metric exporter configuration: %w", err)
+		}
+
+		readerOTLP, err := getReaderOTLP(ctx, logger, config)
+		if err != nil {
+			return nil, err
+		}
+
+		readers = append(readers, readerOTLP)
+	}
+
+	if slices.Contains(exporterNames, keyExporterConsole) {
+		readerConsole, err := getReaderConsole(logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create console metric exporter: %w", err)
+		}
+
+		readers = append(readers, readerConsole)
+	}
+
+	var server *http.Server
+	if metricsListen != "" {
+		readerPrometheus, prometheusServer, err := getReaderPrometheus(logger, metricsListen)
+		if err != nil {
+			return nil, err
+		}
+
+		readers = append(readers, readerPrometheus)
+		server = prometheusServer
+	}
+
+	if len(readers) == 0 {
+		mp := sdkmetric.NewMeterProvider()
+		otel.SetMeterProvider(mp)
+		return &MeterProvider{mp: mp}, nil
+	}
+
+	res, err := newResource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := sdkmetric.NewMeterProvider(append(readers, sdkmetric.WithResource(res))...)
+	otel.SetMeterProvider(mp)
+
+	logger.Info("OpenTelemetry meter provider initialized",
+		"exporters", metricsExporter,
+		"prometheus_listen", metricsListen)
+
+	return &MeterProvider{mp: mp, server: server}, nil
+}
+
+// getReaderOTLP creates a periodic reader with OTLP exporter of the configured protocol
+func getReaderOTLP(ctx context.Context, logger *slog.Logger, config *otlpconfig.Config) (sdkmetric.Option, error) {
+	if config.Protocol == otlpconfig.ProtocolHTTPProtobuf {
+		reader, err := getReaderHTTP(ctx, logger, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HTTP OTLP metric exporter: %w", err)
+		}
+
+		return reader, nil
+	}
+
+	reader, err := getReaderGRPC(ctx, logger, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC OTLP metric exporter: %w", err)
+	}
+
+	return reader, nil
+}
+
+// getReaderHTTP creates a periodic reader with HTTP exporter
+func getReaderHTTP(ctx context.Context, logger *slog.Logger, config *otlpconfig.Config) (sdkmetric.Option, error) {
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(config.Host),
+		otlpmetrichttp.WithHeaders(config.Headers),
+	}
+
+	if config.Insecure {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	} else {
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(config.TLSConfig))
+	}
+
+	if config.URLPath != "" {
+		opts = append(opts, otlpmetrichttp.WithURLPath(config.URLPath))
+	}
+
+	if config.Compression == otlpconfig.CompressionGzip {
+		opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	}
+
+	if config.Timeout > 0 {
+		opts = append(opts, otlpmetrichttp.WithTimeout(config.Timeout))
+	}
+
+	otlpExporter, err := otlpmetrichttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("HTTP OTLP metric exporter configured", "endpoint", config.Host, "path", config.URLPath,
+		"insecure", config.Insecure, "compression", config.Compression)
+
+	return sdkmetric.WithReader(sdkmetric.NewPeriodicReader(otlpExporter)), nil
+}
+
+// getReaderGRPC creates a periodic reader with gRPC exporter
+func getReaderGRPC(ctx context.Context, logger *slog.Logger, config *otlpconfig.Config) (sdkmetric.Option, error) {
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(config.Host),
+		otlpmetricgrpc.WithHeaders(config.Headers),
+	}
+
+	if config.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(config.TLSConfig)))
+	}
+
+	if config.Compression == otlpconfig.CompressionGzip {
+		opts = append(opts, otlpmetricgrpc.WithCompressor(otlpconfig.CompressionGzip))
+	}
+
+	if config.Timeout > 0 {
+		opts = append(opts, otlpmetricgrpc.WithTimeout(config.Timeout))
+	}
+
+	otlpExporter, err := otlpmetricgrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("gRPC OTLP metric exporter configured", "endpoint", config.Host,
+		"insecure", config.Insecure, "compression", config.Compression)
+
+	return sdkmetric.WithReader(sdkmetric.NewPeriodicReader(otlpExporter)), nil
+}
+
+// getReaderConsole creates a periodic reader with console exporter
+func getReaderConsole(logger *slog.Logger) (sdkmetric.Option, error) {
+	consoleExporter, err := stdoutmetric.New(stdoutmetric.WithPrettyPrint(), stdoutmetric.WithWriter(consoleOutput))
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Console metric exporter configured")
+
+	return sdkmetric.WithReader(sdkmetric.NewPeriodicReader(consoleExporter)), nil
+}
+
+// getReaderPrometheus creates a Prometheus reader and starts the server exposing it on metricsListen address
+func getReaderPrometheus(logger *slog.Logger, metricsListen string) (sdkmetric.Option, *http.Server, error) {
+	reader, handler, err := newPrometheusHandler()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Listen synchronously, so that address errors are reported before any request is sent
+	listener, err := net.Listen("tcp", metricsListen)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to listen on metrics address %s: %w", metricsListen, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, handler)
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Warn("Prometheus metrics server failed", "error", err)
+		}
+	}()
+
+	logger.Info("Prometheus metrics endpoint started", "listen", listener.Addr().String(), "path", metricsPath)
+
+	return reader, server, nil
+}
+
+// newPrometheusHandler creates a Prometheus reader and the handler serving metrics collected by it
+func newPrometheusHandler() (sdkmetric.Option, http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Prometheus exporter: %w", err)
+	}
+
+	return sdkmetric.WithReader(exporter), promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
+
+// Shutdown stops the metrics server and gracefully shuts down the meter provider, flushing pending metrics
+func (mp *MeterProvider) Shutdown(ctx context.Context) error {
+	var errs []error
+	if mp.server != nil {
+		errs = append(errs, mp.server.Shutdown(ctx))
+	}
+
+	if mp.mp != nil {
+		errs = append(errs, mp.mp.Shutdown(ctx))
+	}
+
+	return errors.Join(errs...)
+}
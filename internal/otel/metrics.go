@@ -0,0 +1,173 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// results of broker requests recorded in metrics
+const (
+	RequestResultSuccess     = "success"
+	RequestResultError       = "error"
+	RequestResultCircuitOpen = "circuit_open"
+)
+
+// gRPC services of the broker
+const (
+	rpcServiceCrypto    = "CryptoBroker.CryptoGrpc"
+	rpcServiceCryptoDev = "CryptoBroker.CryptoGrpcDev"
+	rpcServiceHealth    = "grpc.health.v1.Health"
+)
+
+// Operation describes broker request sent by a command and the gRPC method serving it
+type Operation struct {
+	// Name of the operation used in CLI metrics and logs
+	Name string
+
+	// RPCService full name of the gRPC service
+	RPCService string
+
+	// RPCMethod name of the gRPC method
+	RPCMethod string
+}
+
+// operations of broker requests sent by commands
+var (
+	OperationHashData        = Operation{Name: "HashData", RPCService: rpcServiceCrypto, RPCMethod: "HashData"}
+	OperationSignCertificate = Operation{Name: "SignCertificate", RPCService: rpcServiceCrypto, RPCMethod: "SignCertificate"}
+	OperationBenchmark       = Operation{Name: "Benchmark", RPCService: rpcServiceCryptoDev, RPCMethod: "Benchmark"}
+	OperationFakeEndpoint    = Operation{Name: "FakeEndpoint", RPCService: rpcServiceCryptoDev, RPCMethod: "FakeEndpoint"}
+	OperationHealth          = Operation{Name: "Health", RPCService: rpcServiceHealth, RPCMethod: "Check"}
+)
+
+// requestDurationBuckets are histogram bucket boundaries of request duration in seconds
+var requestDurationBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// rpcDurationBuckets are histogram bucket boundaries of rpc.client.duration in milliseconds
+var rpcDurationBuckets = []float64{0.5, 1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// sizeBuckets are histogram bucket boundaries of request and response sizes in bytes
+var sizeBuckets = []float64{0, 64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304}
+
+// requestMetrics holds instruments describing broker requests sent by the CLI.
+// Instruments are created from the global meter provider, so they delegate to the provider set by NewMeterProvider.
+var requestMetrics = newRequestMetrics(otel.Meter(defaultServiceName))
+
+// requestInstruments groups instruments describing broker requests
+type requestInstruments struct {
+	requests    metric.Int64Counter
+	duration    metric.Float64Histogram
+	circuitOpen metric.Int64Counter
+	inFlight    metric.Int64UpDownCounter
+
+	// semantic convention RPC client metrics
+	rpcDuration     metric.Float64Histogram
+	rpcRequestSize  metric.Int64Histogram
+	rpcResponseSize metric.Int64Histogram
+}
+
+// newRequestMetrics creates request instruments. Creating instruments fails only for invalid names,
+// in which case the returned no-op instruments are used.
+func newRequestMetrics(meter metric.Meter) requestInstruments {
+	requests, _ := meter.Int64Counter("crypto_broker_cli.requests",
+		metric.WithDescription("Number of broker request attempts by operation, profile, result and gRPC code"),
+		metric.WithUnit("{request}"))
+	duration, _ := meter.Float64Histogram("crypto_broker_cli.request.duration",
+		metric.WithDescription("Duration of single broker request attempts"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(requestDurationBuckets...))
+	circuitOpen, _ := meter.Int64Counter("crypto_broker_cli.circuit_breaker.open",
+		metric.WithDescription("Number of broker requests short-circuited by the circuit breaker"),
+		metric.WithUnit("{request}"))
+	inFlight, _ := meter.Int64UpDownCounter("crypto_broker_cli.requests.in_flight",
+		metric.WithDescription("Number of broker requests in flight"),
+		metric.WithUnit("{request}"))
+	rpcDuration, _ := meter.Float64Histogram("rpc.client.duration",
+		metric.WithDescription("Measures the duration of outbound RPC"),
+		metric.WithUnit("ms"),
+		metric.WithExplicitBucketBoundaries(rpcDurationBuckets...))
+	rpcRequestSize, _ := meter.Int64Histogram("rpc.client.request.size",
+		metric.WithDescription("Measures the size of RPC request payload"),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(sizeBuckets...))
+	rpcResponseSize, _ := meter.Int64Histogram("rpc.client.response.size",
+		metric.WithDescription("Measures the size of RPC response messages"),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(sizeBuckets...))
+
+	return requestInstruments{
+		requests:        requests,
+		duration:        duration,
+		circuitOpen:     circuitOpen,
+		inFlight:        inFlight,
+		rpcDuration:     rpcDuration,
+		rpcRequestSize:  rpcRequestSize,
+		rpcResponseSize: rpcResponseSize,
+	}
+}
+
+// StartRequest records single attempt of broker request of given operation as in flight. The requestSize is size
+// of the request payload sent to the broker in bytes.
+// The returned function must be called with the response and error once the request finishes, it records
+// the request count, result, gRPC code, duration and, for protobuf responses, the response size.
+func StartRequest(ctx context.Context, operation Operation, profile string, requestSize int) func(response any, err error) {
+	operationAttrs := metric.WithAttributes(AttributeRpcMethod.String(operation.Name))
+	requestMetrics.inFlight.Add(ctx, 1, operationAttrs)
+	timestampStart := time.Now()
+
+	return func(response any, err error) {
+		durationElapsed := time.Since(timestampStart)
+		requestMetrics.inFlight.Add(ctx, -1, operationAttrs)
+
+		result := RequestResultSuccess
+		switch {
+		case errors.Is(err, cryptobrokerclientgo.ErrCircuitOpen):
+			result = RequestResultCircuitOpen
+			requestMetrics.circuitOpen.Add(ctx, 1, operationAttrs)
+		case err != nil:
+			result = RequestResultError
+		}
+
+		code := status.Code(err)
+		attrs := metric.WithAttributeSet(attribute.NewSet(
+			AttributeRpcMethod.String(operation.Name),
+			AttributeCryptoProfile.String(profile),
+			AttributeRequestResult.String(result),
+			AttributeGrpcCode.String(code.String()),
+		))
+		requestMetrics.requests.Add(ctx, 1, attrs)
+		requestMetrics.duration.Record(ctx, durationElapsed.Seconds(), attrs)
+
+		rpcAttrs := metric.WithAttributeSet(attribute.NewSet(
+			semconv.RPCSystemGRPC,
+			semconv.RPCService(operation.RPCService),
+			semconv.RPCMethod(operation.RPCMethod),
+			semconv.RPCGRPCStatusCodeKey.Int(int(code)),
+		))
+		requestMetrics.rpcDuration.Record(ctx, float64(durationElapsed.Nanoseconds())/1e6, rpcAttrs)
+		requestMetrics.rpcRequestSize.Record(ctx, int64(requestSize), rpcAttrs)
+		if message, ok := response.(proto.Message); ok && err == nil {
+			requestMetrics.rpcResponseSize.Record(ctx, int64(proto.Size(message)), rpcAttrs)
+		}
+	}
+}
+
+// MeasureAttempts wraps call sending broker request of given operation, so that every call is recorded by StartRequest.
+// Wrapped function is meant to be passed to retry.Do, so that every attempt is recorded separately.
+func MeasureAttempts[P, R any](operation Operation, profile string, requestSize int, call func(context.Context, P) (R, error)) func(context.Context, P) (R, error) {
+	return func(ctx context.Context, payload P) (R, error) {
+		finishRequest := StartRequest(ctx, operation, profile, requestSize)
+		response, err := call(ctx, payload)
+		finishRequest(response, err)
+		return response, err
+	}
+}
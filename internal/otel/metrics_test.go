@@ -0,0 +1,177 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestStartRequest(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	StartRequest(ctx, OperationHashData, "Default", 10)(wrapperspb.String("response"), nil)
+	StartRequest(ctx, OperationHashData, "Default", 10)(nil, cryptobrokerclientgo.ErrCircuitOpen)
+	inFlight := StartRequest(ctx, OperationHealth, "", 0)
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &data); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, scopeMetrics := range data.ScopeMetrics {
+		for _, m := range scopeMetrics.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	requests, ok := metrics["crypto_broker_cli.requests"].(metricdata.Sum[int64])
+	if !ok || len(requests.DataPoints) != 2 {
+		t.Fatalf("expected request counter with success and circuit_open data points, got %+v", metrics["crypto_broker_cli.requests"])
+	}
+
+	circuitOpen, ok := metrics["crypto_broker_cli.circuit_breaker.open"].(metricdata.Sum[int64])
+	if !ok || len(circuitOpen.DataPoints) != 1 || circuitOpen.DataPoints[0].Value != 1 {
+		t.Fatalf("expected single circuit open request, got %+v", metrics["crypto_broker_cli.circuit_breaker.open"])
+	}
+
+	inFlightSum, ok := metrics["crypto_broker_cli.requests.in_flight"].(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("expected in flight gauge, got %+v", metrics["crypto_broker_cli.requests.in_flight"])
+	}
+
+	for _, point := range inFlightSum.DataPoints {
+		method, _ := point.Attributes.Value(AttributeRpcMethod)
+		if want := map[string]int64{"HashData": 0, "Health": 1}[method.AsString()]; point.Value != want {
+			t.Fatalf("expected %d %s requests in flight, got %d", want, method.AsString(), point.Value)
+		}
+	}
+
+	responseSize, ok := metrics["rpc.client.response.size"].(metricdata.Histogram[int64])
+	if !ok || len(responseSize.DataPoints) != 1 || responseSize.DataPoints[0].Count != 1 {
+		t.Fatalf("expected response size of the successful request only, got %+v", metrics["rpc.client.response.size"])
+	}
+
+	if _, ok := metrics["rpc.client.duration"].(metricdata.Histogram[float64]); !ok {
+		t.Fatalf("expected rpc.client.duration histogram, got %+v", metrics["rpc.client.duration"])
+	}
+
+	inFlight(nil, nil)
+}
+
+func TestPrometheusHandler(t *testing.T) {
+	ctx := context.Background()
+	reader, handler, err := newPrometheusHandler()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	// Global meter delegates only to the first meter provider set, so instruments are created from this one directly
+	defer func(instruments requestInstruments) { requestMetrics = instruments }(requestMetrics)
+	requestMetrics = newRequestMetrics(sdkmetric.NewMeterProvider(reader).Meter(defaultServiceName))
+
+	StartRequest(ctx, OperationHashData, "Default", 10)(wrapperspb.String("response"), nil)
+	StartRequest(ctx, OperationHashData, "Default", 10)(nil, cryptobrokerclientgo.ErrCircuitOpen)
+	inFlight := StartRequest(ctx, OperationHealth, "", 0)
+	defer inFlight(nil, nil)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	// Scope labels added by the exporter vary between its versions, so series are matched by name, the labels
+	// recorded by StartRequest and the value
+	lines := strings.Split(recorder.Body.String(), "\n")
+	for _, series := range []struct {
+		name   string
+		labels []string
+		value  string
+	}{
+		{"crypto_broker_cli_requests_total", []string{`request_result="success"`, `rpc_method="HashData"`, `crypto_profile="Default"`}, "1"},
+		{"crypto_broker_cli_requests_total", []string{`request_result="circuit_open"`, `rpc_method="HashData"`}, "1"},
+		{"crypto_broker_cli_request_duration_seconds_count", []string{`request_result="success"`, `rpc_method="HashData"`}, "1"},
+		{"crypto_broker_cli_request_duration_seconds_bucket", []string{`request_result="success"`, `le="+Inf"`}, "1"},
+		{"crypto_broker_cli_circuit_breaker_open_total", []string{`rpc_method="HashData"`}, "1"},
+		{"crypto_broker_cli_requests_in_flight", []string{`rpc_method="Health"`}, "1"},
+		{"crypto_broker_cli_requests_in_flight", []string{`rpc_method="HashData"`}, "0"},
+	} {
+		found := slices.ContainsFunc(lines, func(line string) bool {
+			rest, ok := strings.CutPrefix(line, series.name+"{")
+			if !ok {
+				return false
+			}
+
+			labels, value, _ := strings.Cut(rest, "} ")
+			if value != series.value {
+				return false
+			}
+
+			return !slices.ContainsFunc(series.labels, func(label string) bool { return !strings.Contains(labels, label) })
+		})
+		if !found {
+			t.Errorf("expected series %s%v with value %s, got:\n%s", series.name, series.labels, series.value, recorder.Body.String())
+		}
+	}
+}
+
+func TestMeasureAttempts(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	defer func(instruments requestInstruments) { requestMetrics = instruments }(requestMetrics)
+	requestMetrics = newRequestMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter(defaultServiceName))
+
+	errUnavailable := status.Error(grpccodes.Unavailable, "unavailable")
+	attempts := 0
+	call := MeasureAttempts(OperationHashData, "Default", 10, func(context.Context, string) (*wrapperspb.StringValue, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errUnavailable
+		}
+
+		return wrapperspb.String("response"), nil
+	})
+
+	// Every call stands for one attempt of retry.Do
+	for range 2 {
+		_, _ = call(ctx, "input")
+	}
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &data); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	counts := make(map[string]int64)
+	for _, scopeMetrics := range data.ScopeMetrics {
+		for _, m := range scopeMetrics.Metrics {
+			requests, ok := m.Data.(metricdata.Sum[int64])
+			if m.Name != "crypto_broker_cli.requests" || !ok {
+				continue
+			}
+
+			for _, point := range requests.DataPoints {
+				code, _ := point.Attributes.Value(AttributeGrpcCode)
+				counts[code.AsString()] += point.Value
+			}
+		}
+	}
+
+	if counts["Unavailable"] != 1 || counts["OK"] != 1 {
+		t.Fatalf("expected one failed and one successful attempt, got %v", counts)
+	}
+}
@@ -0,0 +1,77 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+// W3C propagation header names
+const (
+	headerTraceParent = "traceparent"
+	headerTraceState  = "tracestate"
+	headerBaggage     = "baggage"
+)
+
+// propagator extracts W3C trace context and baggage
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// ContextWithRemoteParent returns ctx carrying span context and baggage of the caller given in W3C format by
+// TRACEPARENT, TRACESTATE and BAGGAGE environment variables, so CLI spans join the caller's trace.
+// Non-empty traceParent, e.g. from a flag, takes precedence over TRACEPARENT. Invalid traceParent is returned
+// as error, while invalid TRACEPARENT, which may be inherited from an unrelated parent process, is logged
+// as warning and a new trace is started.
+// Baggage is also added to outgoing gRPC metadata, so it is forwarded to the broker with every request.
+func ContextWithRemoteParent(ctx context.Context, logger *slog.Logger, traceParent string) (context.Context, error) {
+	fromEnv := traceParent == ""
+	if fromEnv {
+		traceParent = os.Getenv(env.TRACEPARENT)
+	}
+
+	carrier := propagation.MapCarrier{
+		headerTraceParent: traceParent,
+		headerTraceState:  os.Getenv(env.TRACESTATE),
+		headerBaggage:     os.Getenv(env.BAGGAGE),
+	}
+
+	ctx = propagator.Extract(ctx, carrier)
+	switch {
+	case traceParent == "" || trace.SpanContextFromContext(ctx).IsRemote():
+	case fromEnv:
+		logger.Warn("Ignoring invalid W3C traceparent, starting new trace", "variable", env.TRACEPARENT, "traceparent", traceParent)
+	default:
+		return nil, fmt.Errorf("invalid W3C traceparent %q", traceParent)
+	}
+
+	if bag := baggage.FromContext(ctx); bag.Len() > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, headerBaggage, bag.String())
+	}
+
+	return ctx, nil
+}
+
+// ContextWithHTTPParent returns ctx carrying span context and baggage of the HTTP caller given by W3C traceparent,
+// tracestate and baggage headers, so spans of the request join the caller's trace. Invalid traceparent is ignored
+// and the request starts a new trace. Baggage is also added to outgoing gRPC metadata, so it is forwarded to the broker.
+func ContextWithHTTPParent(ctx context.Context, header http.Header) context.Context {
+	ctx = propagator.Extract(ctx, propagation.HeaderCarrier(header))
+	if bag := baggage.FromContext(ctx); bag.Len() > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, headerBaggage, bag.String())
+	}
+
+	return ctx
+}
+
+// InjectHTTP sets W3C traceparent and tracestate headers of span carried by ctx, e.g. on HTTP response,
+// so the caller can look up the trace of its request.
+func InjectHTTP(ctx context.Context, header http.Header) {
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(header))
+}
@@ -0,0 +1,94 @@
+package otel
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestContextWithRemoteParent(t *testing.T) {
+	const (
+		envTraceParent  = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		flagTraceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00"
+	)
+
+	t.Setenv(env.TRACEPARENT, envTraceParent)
+	t.Setenv(env.TRACESTATE, "vendor=value")
+	t.Setenv(env.BAGGAGE, "ci.job=42,team=crypto")
+
+	t.Run("environment", func(t *testing.T) {
+		ctx, err := ContextWithRemoteParent(context.Background(), slog.New(slog.DiscardHandler), "")
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		spanContext := trace.SpanContextFromContext(ctx)
+		if spanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || !spanContext.IsSampled() {
+			t.Fatalf("expected sampled span context from TRACEPARENT, got %v", spanContext)
+		}
+
+		if spanContext.TraceState().Get("vendor") != "value" {
+			t.Fatalf("expected trace state from TRACESTATE, got %q", spanContext.TraceState().String())
+		}
+
+		if member := baggage.FromContext(ctx).Member("ci.job"); member.Value() != "42" {
+			t.Fatalf("expected baggage from BAGGAGE, got %q", baggage.FromContext(ctx).String())
+		}
+
+		md, _ := metadata.FromOutgoingContext(ctx)
+		values := md.Get(headerBaggage)
+		if len(values) != 1 {
+			t.Fatalf("expected baggage in outgoing gRPC metadata, got %v", values)
+		}
+
+		// Order of baggage members is not preserved, so the forwarded header is compared by its members
+		if forwarded, err := baggage.Parse(values[0]); err != nil || forwarded.Len() != 2 || forwarded.Member("team").Value() != "crypto" {
+			t.Fatalf("expected forwarded baggage to match BAGGAGE, got %q, err: %v", values[0], err)
+		}
+	})
+
+	t.Run("flag_takes_precedence", func(t *testing.T) {
+		ctx, err := ContextWithRemoteParent(context.Background(), slog.New(slog.DiscardHandler), flagTraceParent)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if traceID := trace.SpanContextFromContext(ctx).TraceID().String(); traceID != "0af7651916cd43dd8448eb211c80319c" {
+			t.Fatalf("expected trace id from flag, got %s", traceID)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := ContextWithRemoteParent(context.Background(), slog.New(slog.DiscardHandler), "00-not-a-traceparent-01"); err == nil {
+			t.Fatalf("expected error for invalid traceparent")
+		}
+	})
+
+	t.Run("invalid_environment_starts_new_trace", func(t *testing.T) {
+		t.Setenv(env.TRACEPARENT, "00-not-a-traceparent-01")
+
+		var logs strings.Builder
+		ctx, err := ContextWithRemoteParent(context.Background(), slog.New(slog.NewTextHandler(&logs, nil)), "")
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+			t.Fatalf("expected no parent span context, got %v", spanContext)
+		}
+
+		if member := baggage.FromContext(ctx).Member("team"); member.Value() != "crypto" {
+			t.Fatalf("expected baggage from BAGGAGE, got %q", baggage.FromContext(ctx).String())
+		}
+
+		if !strings.Contains(logs.String(), "level=WARN") {
+			t.Fatalf("expected warning about invalid TRACEPARENT, got %q", logs.String())
+		}
+	})
+}
@@ -3,12 +3,14 @@ package otel
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"slices"
 	"strconv"
 	"strings"
 
-	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otlpconfig"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otlpfile"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
@@ -17,6 +19,7 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/credentials"
 )
 
 // TracerProvider holds the OpenTelemetry tracer provider
@@ -24,6 +27,12 @@ type TracerProvider struct {
 	tp *sdktrace.TracerProvider
 }
 
+// SetConsoleOutput overrides output of console span and metric exporters, which is stdout by default.
+// It must be called before NewTracerProvider and NewMeterProvider, e.g. to keep stdout free for command output.
+func SetConsoleOutput(output io.Writer) {
+	consoleOutput = output
+}
+
 // GetGlobalTracer returns the global tracer for the service
 func GetGlobalTracer() trace.Tracer {
 	return otel.Tracer(serviceName)
@@ -37,30 +46,18 @@ func NewTracerProvider(ctx context.Context, logger *slog.Logger) (*TracerProvide
 	}
 
 	var batchers []sdktrace.TracerProviderOption
-	if slices.Contains(exporterNames, keyExporterOTLPHTTP) {
-		if otlpEndpoint == "" {
-			return nil, fmt.Errorf("%s is not set", env.OTEL_EXPORTER_OTLP_ENDPOINT)
-		}
-
-		batcherHTTP, err := getBatchersHTTP(ctx, logger)
+	for _, protocol := range otlpProtocols(exporterNames) {
+		config, err := otlpconfig.Load(otlpconfig.SignalTraces, protocol)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create HTTP OTLP exporter: %w", err)
-		}
-
-		batchers = append(batchers, batcherHTTP...)
-	}
-
-	if slices.Contains(exporterNames, keyExporterOTLPGRPC) {
-		if otlpEndpoint == "" {
-			return nil, fmt.Errorf("%s is not set", env.OTEL_EXPORTER_OTLP_ENDPOINT)
+			return nil, fmt.Errorf("invalid OTLP trace exporter configuration: %w", err)
 		}
 
-		batcherGRPC, err := getBatchersGRPC(ctx, logger)
+		batcherOTLP, err := getBatchersOTLP(ctx, logger, config)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create gRPC OTLP exporter: %w", err)
+			return nil, err
 		}
 
-		batchers = append(batchers, batcherGRPC...)
+		batchers = append(batchers, batcherOTLP...)
 	}
 
 	if slices.Contains(exporterNames, keyExporterConsole) {
@@ -71,6 +68,14 @@ func NewTracerProvider(ctx context.Context, logger *slog.Logger) (*TracerProvide
 		batchers = append(batchers, batcherConsole...)
 	}
 
+	if slices.Contains(exporterNames, keyExporterFile) {
+		batcherFile, err := getBatchersFile(ctx, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create file exporter: %w", err)
+		}
+		batchers = append(batchers, batcherFile...)
+	}
+
 	if len(batchers) == 0 {
 		logger.Info("No valid exporters configured, using no-op tracer provider",
 			"requested_exporters", tracesExporter)
@@ -79,15 +84,9 @@ func NewTracerProvider(ctx context.Context, logger *slog.Logger) (*TracerProvide
 		return &TracerProvider{tp: tp}, nil
 	}
 
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String(serviceName),
-			semconv.ServiceVersionKey.String(serviceVersion),
-			semconv.ServiceNamespaceKey.String("crypto-broker"),
-		),
-	)
+	res, err := newResource(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create resource: %w", err)
+		return nil, err
 	}
 
 	sampler := defineSampler(logger)
@@ -103,86 +102,120 @@ func NewTracerProvider(ctx context.Context, logger *slog.Logger) (*TracerProvide
 	return &TracerProvider{tp: tp}, nil
 }
 
-// getBatchersHTTP creates a HTTP exporter
-func getBatchersHTTP(ctx context.Context, logger *slog.Logger) ([]sdktrace.TracerProviderOption, error) {
-	// Parse the endpoint URL to extract host:port and path
-	// For Dynatrace: "https://trc17344.live.dynatrace.com/api/v2/otlp"
-	// Endpoint should only be "trc17344.live.dynatrace.com"
-	// Path should be "/api/v2/otlp"
-	var endpointHost string
-	var urlPath string
-	useSecure := true
-
-	if strings.HasPrefix(otlpEndpoint, "http://") {
-		otlpEndpoint = strings.TrimPrefix(otlpEndpoint, "http://")
-		useSecure = false
-	} else if strings.HasPrefix(otlpEndpoint, "https://") {
-		otlpEndpoint = strings.TrimPrefix(otlpEndpoint, "https://")
-		useSecure = true
+// newResource creates resource describing the service, shared by all signals
+func newResource(ctx context.Context) (*resource.Resource, error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(serviceVersion),
+			semconv.ServiceNamespaceKey.String("crypto-broker"),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
 
-	// Split endpoint and path
-	parts := strings.SplitN(otlpEndpoint, "/", 2)
-	endpointHost = parts[0]
-	if len(parts) > 1 {
-		urlPath = "/" + parts[1]
-	}
+	return res, nil
+}
 
-	// Remove /v1/traces suffix if present in the path, as otlptracehttp will add it automatically
-	urlPath = strings.TrimSuffix(urlPath, "/v1/traces")
+// otlpProtocols returns OTLP protocols requested by exporter names.
+// Empty protocol stands for "otlp" exporter, whose protocol is resolved by otlpconfig.Load.
+func otlpProtocols(exporterNames []string) []string {
+	var protocols []string
+	for _, name := range exporterNames {
+		switch name {
+		case keyExporterOTLPHTTP:
+			protocols = append(protocols, otlpconfig.ProtocolHTTPProtobuf)
+		case keyExporterOTLPGRPC:
+			protocols = append(protocols, otlpconfig.ProtocolGRPC)
+		case keyExporterOTLP:
+			protocols = append(protocols, "")
+		}
+	}
 
-	// Build headers for HTTP OTLP exporter
-	headers := make(map[string]string)
+	return protocols
+}
 
-	// Check for API token
-	if apiToken != "" {
-		headers["Authorization"] = apiToken
-		logger.Info("Dynatrace API token configured for OTLP HTTP exporter")
+// getBatchersOTLP creates an OTLP exporter of the configured protocol
+func getBatchersOTLP(ctx context.Context, logger *slog.Logger, config *otlpconfig.Config) ([]sdktrace.TracerProviderOption, error) {
+	if config.Protocol == otlpconfig.ProtocolHTTPProtobuf {
+		return getBatchersHTTP(ctx, logger, config)
 	}
 
+	return getBatchersGRPC(ctx, logger, config)
+}
+
+// getBatchersHTTP creates a HTTP exporter
+func getBatchersHTTP(ctx context.Context, logger *slog.Logger, config *otlpconfig.Config) ([]sdktrace.TracerProviderOption, error) {
 	opts := []otlptracehttp.Option{
-		otlptracehttp.WithEndpoint(endpointHost),
-		otlptracehttp.WithHeaders(headers),
+		otlptracehttp.WithEndpoint(config.Host),
+		otlptracehttp.WithHeaders(config.Headers),
 	}
 
-	// Use insecure (HTTP) only if explicitly specified with http:// scheme
-	// For HTTPS (recommended for production like Dynatrace), don't add WithInsecure()
-	if !useSecure {
-		// For HTTP endpoints (typically for local development only)
+	// Use insecure (HTTP) only if explicitly specified with http:// scheme or OTEL_EXPORTER_OTLP_INSECURE
+	if config.Insecure {
 		opts = append(opts, otlptracehttp.WithInsecure())
+	} else {
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(config.TLSConfig))
+	}
+
+	// Add custom URL path if present, e.g. "/api/v2/otlp/v1/traces" for Dynatrace
+	if config.URLPath != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(config.URLPath))
+	}
+
+	if config.Compression == otlpconfig.CompressionGzip {
+		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
 	}
 
-	// Add custom URL path if present
-	if urlPath != "" {
-		opts = append(opts, otlptracehttp.WithURLPath(urlPath+"/v1/traces"))
+	if config.Timeout > 0 {
+		opts = append(opts, otlptracehttp.WithTimeout(config.Timeout))
 	}
 
 	otlpExporter, err := otlptracehttp.New(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP OTLP exporter: %w", err)
 	}
-	logger.Info("HTTP OTLP exporter configured", "endpoint", endpointHost, "path", urlPath)
+	logger.Info("HTTP OTLP exporter configured", "endpoint", config.Host, "path", config.URLPath,
+		"insecure", config.Insecure, "compression", config.Compression)
 
 	return []sdktrace.TracerProviderOption{sdktrace.WithBatcher(otlpExporter)}, nil
 }
 
 // getBatchersGRPC creates a gRPC exporter
-func getBatchersGRPC(ctx context.Context, logger *slog.Logger) ([]sdktrace.TracerProviderOption, error) {
-	otlpExporter, err := otlptracegrpc.New(ctx,
-		otlptracegrpc.WithEndpoint(otlpEndpoint),
-		otlptracegrpc.WithInsecure(),
-	)
+func getBatchersGRPC(ctx context.Context, logger *slog.Logger, config *otlpconfig.Config) ([]sdktrace.TracerProviderOption, error) {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(config.Host),
+		otlptracegrpc.WithHeaders(config.Headers),
+	}
+
+	if config.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(config.TLSConfig)))
+	}
+
+	if config.Compression == otlpconfig.CompressionGzip {
+		opts = append(opts, otlptracegrpc.WithCompressor(otlpconfig.CompressionGzip))
+	}
+
+	if config.Timeout > 0 {
+		opts = append(opts, otlptracegrpc.WithTimeout(config.Timeout))
+	}
+
+	otlpExporter, err := otlptracegrpc.New(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC OTLP exporter: %w", err)
 	}
-	logger.Info("gRPC OTLP exporter configured", "endpoint", otlpEndpoint)
+	logger.Info("gRPC OTLP exporter configured", "endpoint", config.Host,
+		"insecure", config.Insecure, "compression", config.Compression)
 
 	return []sdktrace.TracerProviderOption{sdktrace.WithBatcher(otlpExporter)}, nil
 }
 
 // getBatchersConsole creates a console exporter
 func getBatchersConsole(logger *slog.Logger) ([]sdktrace.TracerProviderOption, error) {
-	consoleExporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+	consoleExporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint(), stdouttrace.WithWriter(consoleOutput))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create console exporter: %w", err)
 	}
@@ -191,6 +224,17 @@ func getBatchersConsole(logger *slog.Logger) ([]sdktrace.TracerProviderOption, e
 	return []sdktrace.TracerProviderOption{sdktrace.WithBatcher(consoleExporter)}, nil
 }
 
+// getBatchersFile creates a file exporter writing OTLP-JSON lines
+func getBatchersFile(ctx context.Context, logger *slog.Logger) ([]sdktrace.TracerProviderOption, error) {
+	fileExporter, err := otlpfile.NewTraceExporter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("File exporter configured")
+
+	return []sdktrace.TracerProviderOption{sdktrace.WithBatcher(fileExporter)}, nil
+}
+
 // defineSampler defines the sampler for the tracer provider
 func defineSampler(logger *slog.Logger) sdktrace.Sampler {
 	var sampler sdktrace.Sampler
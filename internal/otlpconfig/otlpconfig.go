@@ -0,0 +1,322 @@
+// Package otlpconfig resolves configuration of OTLP exporters from standard OpenTelemetry environment variables.
+// It is shared by trace, metric and log exporters, so that all signals parse endpoints the same way.
+package otlpconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+)
+
+// Signal represents telemetry signal exported through OTLP.
+type Signal string
+
+// supported signals
+const (
+	SignalTraces  Signal = "traces"
+	SignalMetrics Signal = "metrics"
+	SignalLogs    Signal = "logs"
+)
+
+// supported OTLP transport protocols
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+)
+
+// supported OTLP compressions
+const (
+	CompressionGzip = "gzip"
+	CompressionNone = "none"
+)
+
+// ErrEndpointNotSet is returned when neither generic nor signal specific OTLP endpoint is set.
+var ErrEndpointNotSet = errors.New("OTLP endpoint is not set")
+
+// signalEndpoints maps signals to environment variables overriding OTEL_EXPORTER_OTLP_ENDPOINT.
+var signalEndpoints = map[Signal]string{
+	SignalTraces:  env.OTEL_EXPORTER_OTLP_TRACES_ENDPOINT,
+	SignalMetrics: env.OTEL_EXPORTER_OTLP_METRICS_ENDPOINT,
+	SignalLogs:    env.OTEL_EXPORTER_OTLP_LOGS_ENDPOINT,
+}
+
+// Config represents OTLP exporter configuration of single signal.
+type Config struct {
+	// Protocol is resolved transport protocol, ProtocolGRPC or ProtocolHTTPProtobuf.
+	Protocol string
+	// Host is "host:port" of the endpoint, without scheme and path.
+	Host string
+	// URLPath is full HTTP path of the signal, e.g. "/api/v2/otlp/v1/traces".
+	// Empty path means the default path of the exporter.
+	URLPath string
+	// Insecure reports whether TLS is disabled.
+	Insecure bool
+	// TLSConfig is TLS configuration of secure endpoints, nil if Insecure is true.
+	TLSConfig *tls.Config
+	// Headers are sent with every export.
+	Headers map[string]string
+	// Compression is CompressionGzip or CompressionNone.
+	Compression string
+	// Timeout of single export, zero means the default timeout of the exporter.
+	Timeout time.Duration
+}
+
+// Load resolves OTLP exporter configuration of signal from environment variables.
+// Protocol is the transport requested by exporter name, empty protocol is resolved from OTEL_EXPORTER_OTLP_PROTOCOL,
+// or detected from the endpoint: HTTP for endpoints with scheme and gRPC for "host:port" endpoints.
+func Load(signal Signal, protocol string) (*Config, error) {
+	return load(signal, protocol, os.Getenv)
+}
+
+// load resolves configuration using getenv, so that it can be tested without modifying process environment.
+func load(signal Signal, protocol string, getenv func(string) string) (*Config, error) {
+	signalEndpointKey := signalEndpoints[signal]
+	rawEndpoint, perSignal := getenv(signalEndpointKey), true
+	if rawEndpoint == "" {
+		rawEndpoint, perSignal = getenv(env.OTEL_EXPORTER_OTLP_ENDPOINT), false
+	}
+
+	if rawEndpoint == "" {
+		return nil, fmt.Errorf("%w, set %s or %s", ErrEndpointNotSet, env.OTEL_EXPORTER_OTLP_ENDPOINT, signalEndpointKey)
+	}
+
+	endpoint, err := parseEndpoint(rawEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{Host: endpoint.host}
+	config.Protocol, err = resolveProtocol(protocol, getenv(env.OTEL_EXPORTER_OTLP_PROTOCOL), endpoint.scheme != "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Signal specific endpoints are used as is, generic endpoint is a base URL of all signals
+	config.URLPath = endpoint.path
+	signalPath := "/v1/" + string(signal)
+	if !perSignal && config.URLPath != "" {
+		config.URLPath = strings.TrimSuffix(strings.TrimSuffix(config.URLPath, signalPath), "/") + signalPath
+	}
+
+	tlsConfig, err := loadTLSConfig(getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Insecure, err = resolveInsecure(endpoint.scheme, getenv(env.OTEL_EXPORTER_OTLP_INSECURE), config.Protocol, tlsConfig != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.Insecure {
+		config.TLSConfig = tlsConfig
+		if config.TLSConfig == nil {
+			config.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+	}
+
+	config.Headers, err = parseHeaders(getenv(env.OTEL_EXPORTER_OTLP_HEADERS))
+	if err != nil {
+		return nil, err
+	}
+
+	// Legacy authorization variable does not override the header set explicitly
+	if apiToken := getenv(env.OTEL_EXPORTER_OTLP_HEADERS_AUTHORIZATION); apiToken != "" && !hasHeader(config.Headers, "Authorization") {
+		config.Headers["Authorization"] = apiToken
+	}
+
+	config.Compression, err = parseCompression(getenv(env.OTEL_EXPORTER_OTLP_COMPRESSION))
+	if err != nil {
+		return nil, err
+	}
+
+	config.Timeout, err = parseTimeout(getenv(env.OTEL_EXPORTER_OTLP_TIMEOUT))
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// endpoint represents parsed OTLP endpoint
+type endpoint struct {
+	scheme string
+	host   string
+	path   string
+}
+
+// parseEndpoint parses endpoint given either as URL, e.g. "https://collector:4318/otlp", or as "host:port[/path]".
+func parseEndpoint(rawEndpoint string) (endpoint, error) {
+	if !strings.Contains(rawEndpoint, "://") {
+		host, path, _ := strings.Cut(rawEndpoint, "/")
+		if path != "" {
+			path = "/" + path
+		}
+
+		return endpoint{host: host, path: path}, nil
+	}
+
+	u, err := url.Parse(rawEndpoint)
+	if err != nil {
+		return endpoint{}, fmt.Errorf("invalid OTLP endpoint %s: %w", rawEndpoint, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return endpoint{}, fmt.Errorf("invalid OTLP endpoint %s: unsupported scheme %s, available schemes: http, https", rawEndpoint, u.Scheme)
+	}
+
+	if u.Host == "" {
+		return endpoint{}, fmt.Errorf("invalid OTLP endpoint %s: missing host", rawEndpoint)
+	}
+
+	return endpoint{scheme: u.Scheme, host: u.Host, path: strings.TrimSuffix(u.Path, "/")}, nil
+}
+
+// resolveProtocol returns protocol requested by exporter name, otherwise the configured one or the detected one.
+func resolveProtocol(requested string, configured string, hasScheme bool) (string, error) {
+	protocol := requested
+	if protocol == "" {
+		protocol = strings.ToLower(strings.TrimSpace(configured))
+	}
+
+	switch protocol {
+	case ProtocolGRPC, ProtocolHTTPProtobuf:
+		return protocol, nil
+	case "":
+		if hasScheme {
+			return ProtocolHTTPProtobuf, nil
+		}
+
+		return ProtocolGRPC, nil
+	default:
+		return "", fmt.Errorf("invalid %s value %s, available protocols: %s, %s",
+			env.OTEL_EXPORTER_OTLP_PROTOCOL, protocol, ProtocolGRPC, ProtocolHTTPProtobuf)
+	}
+}
+
+// resolveInsecure reports whether TLS is disabled. Scheme of the endpoint takes precedence,
+// then OTEL_EXPORTER_OTLP_INSECURE. Otherwise gRPC endpoints are insecure unless TLS certificates are configured.
+func resolveInsecure(scheme string, configured string, protocol string, hasTLSConfig bool) (bool, error) {
+	switch {
+	case scheme == "http":
+		return true, nil
+	case scheme == "https":
+		return false, nil
+	case configured != "":
+		insecure, err := strconv.ParseBool(strings.TrimSpace(configured))
+		if err != nil {
+			return false, fmt.Errorf("invalid %s value %s: %w", env.OTEL_EXPORTER_OTLP_INSECURE, configured, err)
+		}
+
+		return insecure, nil
+	default:
+		return protocol == ProtocolGRPC && !hasTLSConfig, nil
+	}
+}
+
+// loadTLSConfig loads CA and client certificates configured for the endpoint.
+// It returns nil config if no certificate is configured.
+func loadTLSConfig(getenv func(string) string) (*tls.Config, error) {
+	caFile := getenv(env.OTEL_EXPORTER_OTLP_CERTIFICATE)
+	certFile := getenv(env.OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE)
+	keyFile := getenv(env.OTEL_EXPORTER_OTLP_CLIENT_KEY)
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s file: %w", env.OTEL_EXPORTER_OTLP_CERTIFICATE, err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("%s file %s does not contain any PEM encoded certificate", env.OTEL_EXPORTER_OTLP_CERTIFICATE, caFile)
+		}
+	}
+
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("%s and %s must be set together",
+			env.OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE, env.OTEL_EXPORTER_OTLP_CLIENT_KEY)
+	}
+
+	if certFile != "" {
+		clientCert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load OTLP client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return tlsConfig, nil
+}
+
+// parseHeaders parses comma-separated list of URL-encoded key=value pairs.
+func parseHeaders(rawHeaders string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(rawHeaders, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		rawKey, rawValue, found := strings.Cut(pair, "=")
+		key, errKey := url.PathUnescape(strings.TrimSpace(rawKey))
+		value, errValue := url.PathUnescape(strings.TrimSpace(rawValue))
+		if !found || key == "" || errKey != nil || errValue != nil {
+			return nil, fmt.Errorf("invalid %s entry %q, expected URL-encoded key=value", env.OTEL_EXPORTER_OTLP_HEADERS, pair)
+		}
+
+		headers[key] = value
+	}
+
+	return headers, nil
+}
+
+// hasHeader reports whether headers contain key, compared case-insensitively.
+func hasHeader(headers map[string]string, key string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseCompression parses compression, empty value means no compression.
+func parseCompression(rawCompression string) (string, error) {
+	switch compression := strings.ToLower(strings.TrimSpace(rawCompression)); compression {
+	case "", CompressionNone:
+		return CompressionNone, nil
+	case CompressionGzip:
+		return CompressionGzip, nil
+	default:
+		return "", fmt.Errorf("invalid %s value %s, available compressions: %s, %s",
+			env.OTEL_EXPORTER_OTLP_COMPRESSION, rawCompression, CompressionGzip, CompressionNone)
+	}
+}
+
+// parseTimeout parses timeout in milliseconds, empty value means the default timeout.
+func parseTimeout(rawTimeout string) (time.Duration, error) {
+	if rawTimeout == "" {
+		return 0, nil
+	}
+
+	milliseconds, err := strconv.Atoi(strings.TrimSpace(rawTimeout))
+	if err != nil || milliseconds <= 0 {
+		return 0, fmt.Errorf("invalid %s value %s, expected positive number of milliseconds", env.OTEL_EXPORTER_OTLP_TIMEOUT, rawTimeout)
+	}
+
+	return time.Duration(milliseconds) * time.Millisecond, nil
+}
@@ -0,0 +1,186 @@
+package otlpconfig
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name         string
+		signal       Signal
+		protocol     string
+		environment  map[string]string
+		wantProtocol string
+		wantHost     string
+		wantURLPath  string
+		wantInsecure bool
+	}{
+		{
+			name:         "http_generic_endpoint_with_path",
+			signal:       SignalTraces,
+			environment:  map[string]string{env.OTEL_EXPORTER_OTLP_ENDPOINT: "https://abc123.live.dynatrace.com/api/v2/otlp"},
+			wantProtocol: ProtocolHTTPProtobuf,
+			wantHost:     "abc123.live.dynatrace.com",
+			wantURLPath:  "/api/v2/otlp/v1/traces",
+		},
+		{
+			name:         "http_generic_endpoint_with_signal_path",
+			signal:       SignalLogs,
+			environment:  map[string]string{env.OTEL_EXPORTER_OTLP_ENDPOINT: "http://collector:4318/otlp/v1/logs"},
+			wantProtocol: ProtocolHTTPProtobuf,
+			wantHost:     "collector:4318",
+			wantURLPath:  "/otlp/v1/logs",
+			wantInsecure: true,
+		},
+		{
+			name:         "http_generic_endpoint_without_path",
+			signal:       SignalMetrics,
+			environment:  map[string]string{env.OTEL_EXPORTER_OTLP_ENDPOINT: "http://collector:4318/"},
+			wantProtocol: ProtocolHTTPProtobuf,
+			wantHost:     "collector:4318",
+			wantInsecure: true,
+		},
+		{
+			name:     "signal_endpoint_overrides_generic",
+			signal:   SignalTraces,
+			protocol: ProtocolHTTPProtobuf,
+			environment: map[string]string{
+				env.OTEL_EXPORTER_OTLP_ENDPOINT:        "http://collector:4318",
+				env.OTEL_EXPORTER_OTLP_TRACES_ENDPOINT: "https://traces:443/custom",
+			},
+			wantProtocol: ProtocolHTTPProtobuf,
+			wantHost:     "traces:443",
+			wantURLPath:  "/custom",
+		},
+		{
+			name:         "grpc_host_port_is_insecure",
+			signal:       SignalTraces,
+			environment:  map[string]string{env.OTEL_EXPORTER_OTLP_ENDPOINT: "localhost:4317"},
+			wantProtocol: ProtocolGRPC,
+			wantHost:     "localhost:4317",
+			wantInsecure: true,
+		},
+		{
+			name:     "grpc_host_port_with_insecure_disabled",
+			signal:   SignalLogs,
+			protocol: ProtocolGRPC,
+			environment: map[string]string{
+				env.OTEL_EXPORTER_OTLP_ENDPOINT: "collector:4317",
+				env.OTEL_EXPORTER_OTLP_INSECURE: "false",
+			},
+			wantProtocol: ProtocolGRPC,
+			wantHost:     "collector:4317",
+		},
+		{
+			name:     "protocol_from_environment",
+			signal:   SignalMetrics,
+			protocol: "",
+			environment: map[string]string{
+				env.OTEL_EXPORTER_OTLP_ENDPOINT: "https://collector:4317",
+				env.OTEL_EXPORTER_OTLP_PROTOCOL: "grpc",
+			},
+			wantProtocol: ProtocolGRPC,
+			wantHost:     "collector:4317",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := load(tt.signal, tt.protocol, getenvOf(tt.environment))
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+
+			if config.Protocol != tt.wantProtocol || config.Host != tt.wantHost || config.URLPath != tt.wantURLPath {
+				t.Fatalf("expected protocol %q, host %q and path %q, got %q, %q and %q",
+					tt.wantProtocol, tt.wantHost, tt.wantURLPath, config.Protocol, config.Host, config.URLPath)
+			}
+
+			if config.Insecure != tt.wantInsecure || (config.TLSConfig == nil) != tt.wantInsecure {
+				t.Fatalf("expected insecure %t, got %t with TLS config %v", tt.wantInsecure, config.Insecure, config.TLSConfig)
+			}
+		})
+	}
+}
+
+func TestLoadOptions(t *testing.T) {
+	config, err := load(SignalTraces, "", getenvOf(map[string]string{
+		env.OTEL_EXPORTER_OTLP_ENDPOINT:              "http://collector:4318",
+		env.OTEL_EXPORTER_OTLP_HEADERS:               "x-tenant=dev, x-scope=a%3Db%2Cc",
+		env.OTEL_EXPORTER_OTLP_HEADERS_AUTHORIZATION: "Api-Token legacy",
+		env.OTEL_EXPORTER_OTLP_COMPRESSION:           "GZIP",
+		env.OTEL_EXPORTER_OTLP_TIMEOUT:               "2500",
+	}))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if config.Headers["x-tenant"] != "dev" || config.Headers["x-scope"] != "a=b,c" || config.Headers["Authorization"] != "Api-Token legacy" {
+		t.Fatalf("unexpected headers %v", config.Headers)
+	}
+
+	if config.Compression != CompressionGzip || config.Timeout != 2500*time.Millisecond {
+		t.Fatalf("expected gzip compression and 2.5s timeout, got %s and %s", config.Compression, config.Timeout)
+	}
+
+	t.Run("explicit_authorization_header_wins", func(t *testing.T) {
+		config, err := load(SignalTraces, "", getenvOf(map[string]string{
+			env.OTEL_EXPORTER_OTLP_ENDPOINT:              "http://collector:4318",
+			env.OTEL_EXPORTER_OTLP_HEADERS:               "authorization=Bearer%20token",
+			env.OTEL_EXPORTER_OTLP_HEADERS_AUTHORIZATION: "Api-Token legacy",
+		}))
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		if len(config.Headers) != 1 || config.Headers["authorization"] != "Bearer token" {
+			t.Fatalf("expected only explicit authorization header, got %v", config.Headers)
+		}
+	})
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment map[string]string
+	}{
+		{name: "unsupported_scheme", environment: map[string]string{env.OTEL_EXPORTER_OTLP_ENDPOINT: "ftp://collector"}},
+		{name: "unsupported_protocol", environment: map[string]string{env.OTEL_EXPORTER_OTLP_PROTOCOL: "http/json"}},
+		{name: "invalid_header", environment: map[string]string{env.OTEL_EXPORTER_OTLP_HEADERS: "x-tenant"}},
+		{name: "invalid_compression", environment: map[string]string{env.OTEL_EXPORTER_OTLP_COMPRESSION: "zstd"}},
+		{name: "invalid_timeout", environment: map[string]string{env.OTEL_EXPORTER_OTLP_TIMEOUT: "10s"}},
+		{name: "invalid_insecure", environment: map[string]string{env.OTEL_EXPORTER_OTLP_INSECURE: "maybe"}},
+		{name: "missing_ca_file", environment: map[string]string{env.OTEL_EXPORTER_OTLP_CERTIFICATE: "/nonexistent/ca.pem"}},
+		{name: "client_certificate_without_key", environment: map[string]string{env.OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE: "/nonexistent/client.pem"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			environment := map[string]string{env.OTEL_EXPORTER_OTLP_ENDPOINT: "collector:4317"}
+			for key, value := range tt.environment {
+				environment[key] = value
+			}
+
+			if _, err := load(SignalTraces, "", getenvOf(environment)); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+
+	t.Run("endpoint_not_set", func(t *testing.T) {
+		if _, err := load(SignalLogs, "", getenvOf(nil)); !errors.Is(err, ErrEndpointNotSet) {
+			t.Fatalf("expected ErrEndpointNotSet, got %v", err)
+		}
+	})
+}
+
+// getenvOf returns getenv function reading from environment map
+func getenvOf(environment map[string]string) func(string) string {
+	return func(key string) string {
+		return environment[key]
+	}
+}
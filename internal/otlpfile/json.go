@@ -0,0 +1,132 @@
+package otlpfile
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// idKeys are JSON keys of trace and span ids, which OTLP-JSON encodes as hex strings instead of base64.
+var idKeys = map[string]bool{
+	"traceId":      true,
+	"spanId":       true,
+	"parentSpanId": true,
+}
+
+// marshalLine encodes message as single OTLP-JSON line.
+// Enums are encoded as integers and trace and span ids as hex strings, as required by OTLP-JSON.
+func marshalLine(message proto.Message) ([]byte, error) {
+	protoJSON, err := protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OTLP message: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(protoJSON))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode OTLP message: %w", err)
+	}
+
+	if err := hexIds(document); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(document)
+}
+
+// hexIds re-encodes base64 trace and span ids found anywhere in document as hex strings.
+func hexIds(document any) error {
+	switch node := document.(type) {
+	case map[string]any:
+		for key, value := range node {
+			if encoded, ok := value.(string); ok && idKeys[key] {
+				id, err := base64.StdEncoding.DecodeString(encoded)
+				if err != nil {
+					return fmt.Errorf("failed to decode %s: %w", key, err)
+				}
+
+				node[key] = hex.EncodeToString(id)
+				continue
+			}
+
+			if err := hexIds(value); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, value := range node {
+			if err := hexIds(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resourceProto converts resource to its OTLP representation.
+func resourceProto(res *resource.Resource) *resourcepb.Resource {
+	if res == nil {
+		return &resourcepb.Resource{}
+	}
+
+	return &resourcepb.Resource{Attributes: attributesProto(res.Attributes())}
+}
+
+// scopeProto converts instrumentation scope to its OTLP representation.
+func scopeProto(scope instrumentation.Scope) *commonpb.InstrumentationScope {
+	return &commonpb.InstrumentationScope{
+		Name:       scope.Name,
+		Version:    scope.Version,
+		Attributes: attributesProto(scope.Attributes.ToSlice()),
+	}
+}
+
+// attributesProto converts attributes to their OTLP representation.
+func attributesProto(attributes []attribute.KeyValue) []*commonpb.KeyValue {
+	keyValues := make([]*commonpb.KeyValue, 0, len(attributes))
+	for _, kv := range attributes {
+		keyValues = append(keyValues, &commonpb.KeyValue{Key: string(kv.Key), Value: attributeValueProto(kv.Value)})
+	}
+
+	return keyValues
+}
+
+// attributeValueProto converts attribute value to its OTLP representation.
+func attributeValueProto(value attribute.Value) *commonpb.AnyValue {
+	switch value.Type() {
+	case attribute.BOOL:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: value.AsBool()}}
+	case attribute.INT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: value.AsInt64()}}
+	case attribute.FLOAT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: value.AsFloat64()}}
+	case attribute.STRING:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value.AsString()}}
+	case attribute.BYTESLICE:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: value.AsByteSlice()}}
+	case attribute.BOOLSLICE, attribute.INT64SLICE, attribute.FLOAT64SLICE, attribute.STRINGSLICE, attribute.SLICE:
+		values := value.AsSlice()
+		array := make([]*commonpb.AnyValue, 0, len(values))
+		for _, v := range values {
+			array = append(array, attributeValueProto(v))
+		}
+
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: array}}}
+	case attribute.EMPTY:
+		return &commonpb.AnyValue{}
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value.Emit()}}
+	}
+}
@@ -0,0 +1,157 @@
+package otlpfile
+
+import (
+	"context"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otlpconfig"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+// LogExporter is log record exporter writing every export as OTLP-JSON line.
+type LogExporter struct {
+	writer *Writer
+}
+
+// NewLogExporter creates log record exporter appending OTLP-JSON lines to the file configured
+// by CRYPTO_BROKER_OTEL_LOGS_FILE, rotated according to CRYPTO_BROKER_OTEL_FILE_MAX_SIZE_MB
+// and CRYPTO_BROKER_OTEL_FILE_MAX_BACKUPS.
+func NewLogExporter() (*LogExporter, error) {
+	writer, err := newWriterFromEnv(otlpconfig.SignalLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LogExporter{writer: writer}, nil
+}
+
+// Export writes records as single ExportLogsServiceRequest line.
+func (e *LogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	line, err := marshalLine(&collogspb.ExportLogsServiceRequest{ResourceLogs: resourceLogsProto(records)})
+	if err != nil {
+		return err
+	}
+
+	return e.writer.WriteLine(line)
+}
+
+// Shutdown closes the file.
+func (e *LogExporter) Shutdown(ctx context.Context) error {
+	return e.writer.Close()
+}
+
+// ForceFlush does nothing, records are written unbuffered.
+func (e *LogExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+// resourceLogsProto groups records by resource and instrumentation scope.
+func resourceLogsProto(records []sdklog.Record) []*logspb.ResourceLogs {
+	type scopeKey struct {
+		resource *resource.Resource
+		scope    instrumentation.Scope
+	}
+
+	var resourceLogs []*logspb.ResourceLogs
+	resourceIndex := make(map[*resource.Resource]*logspb.ResourceLogs)
+	scopeIndex := make(map[scopeKey]*logspb.ScopeLogs)
+	for i := range records {
+		record := &records[i]
+		res := record.Resource()
+		rl, ok := resourceIndex[res]
+		if !ok {
+			rl = &logspb.ResourceLogs{Resource: resourceProto(res), SchemaUrl: res.SchemaURL()}
+			resourceIndex[res] = rl
+			resourceLogs = append(resourceLogs, rl)
+		}
+
+		key := scopeKey{resource: res, scope: record.InstrumentationScope()}
+		sl, ok := scopeIndex[key]
+		if !ok {
+			sl = &logspb.ScopeLogs{Scope: scopeProto(key.scope), SchemaUrl: key.scope.SchemaURL}
+			scopeIndex[key] = sl
+			rl.ScopeLogs = append(rl.ScopeLogs, sl)
+		}
+
+		sl.LogRecords = append(sl.LogRecords, logRecordProto(record))
+	}
+
+	return resourceLogs
+}
+
+// logRecordProto converts log record to its OTLP representation.
+func logRecordProto(record *sdklog.Record) *logspb.LogRecord {
+	var attributes []*commonpb.KeyValue
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		attributes = append(attributes, &commonpb.KeyValue{Key: kv.Key, Value: logValueProto(kv.Value)})
+		return true
+	})
+
+	logRecord := &logspb.LogRecord{
+		ObservedTimeUnixNano:   uint64(record.ObservedTimestamp().UnixNano()),
+		SeverityNumber:         logspb.SeverityNumber(record.Severity()),
+		SeverityText:           record.SeverityText(),
+		Body:                   logValueProto(record.Body()),
+		Attributes:             attributes,
+		DroppedAttributesCount: uint32(record.DroppedAttributes()),
+		Flags:                  uint32(record.TraceFlags()),
+		EventName:              record.EventName(),
+	}
+
+	if !record.Timestamp().IsZero() {
+		logRecord.TimeUnixNano = uint64(record.Timestamp().UnixNano())
+	}
+
+	if traceId := record.TraceID(); traceId.IsValid() {
+		logRecord.TraceId = traceId[:]
+	}
+
+	if spanId := record.SpanID(); spanId.IsValid() {
+		logRecord.SpanId = spanId[:]
+	}
+
+	return logRecord
+}
+
+// logValueProto converts log value to its OTLP representation.
+func logValueProto(value log.Value) *commonpb.AnyValue {
+	switch value.Kind() {
+	case log.KindBool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: value.AsBool()}}
+	case log.KindInt64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: value.AsInt64()}}
+	case log.KindFloat64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: value.AsFloat64()}}
+	case log.KindString:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value.AsString()}}
+	case log.KindBytes:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: value.AsBytes()}}
+	case log.KindSlice:
+		values := value.AsSlice()
+		array := make([]*commonpb.AnyValue, 0, len(values))
+		for _, v := range values {
+			array = append(array, logValueProto(v))
+		}
+
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: array}}}
+	case log.KindMap:
+		kvs := value.AsMap()
+		keyValues := make([]*commonpb.KeyValue, 0, len(kvs))
+		for _, kv := range kvs {
+			keyValues = append(keyValues, &commonpb.KeyValue{Key: kv.Key, Value: logValueProto(kv.Value)})
+		}
+
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{Values: keyValues}}}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,125 @@
+package otlpfile
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWriterRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	writer, err := NewWriter(path, 10, 2)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	defer writer.Close()
+
+	for _, line := range []string{"first", "second", "third", "fourth"} {
+		if err := writer.WriteLine([]byte(line)); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+
+	expected := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for file, content := range expected {
+		data, err := os.ReadFile(file)
+		if err != nil || string(data) != content {
+			t.Fatalf("expected %s to contain %q, got %q, err: %v", file, content, data, err)
+		}
+	}
+
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected only 2 rotated files to be kept, got err: %v", err)
+	}
+}
+
+func TestTraceExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	t.Setenv(env.OTEL_TRACES_FILE, path)
+
+	ctx := context.Background()
+	exporter, err := NewTraceExporter(ctx)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	_, span := tp.Tracer("test").Start(ctx, "CLI.Hash")
+	span.End()
+	if err := tp.Shutdown(ctx); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	request := readLine(t, path)
+	spanJSON := request["resourceSpans"].([]any)[0].(map[string]any)["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)[0].(map[string]any)
+	if spanJSON["traceId"] != span.SpanContext().TraceID().String() || spanJSON["spanId"] != span.SpanContext().SpanID().String() {
+		t.Fatalf("expected hex encoded trace and span ids of %v, got %v", span.SpanContext(), spanJSON)
+	}
+}
+
+func TestLogExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.jsonl")
+	t.Setenv(env.OTEL_LOGS_FILE, path)
+
+	exporter, err := NewLogExporter()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	ctx := context.Background()
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	var record log.Record
+	record.SetSeverity(log.SeverityError)
+	record.SetBody(log.StringValue("Failed to hash data"))
+	record.AddAttributes(log.Int("attempt", 2))
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}})
+	lp.Logger("test").Emit(trace.ContextWithSpanContext(ctx, spanContext), record)
+	if err := lp.Shutdown(ctx); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	request := readLine(t, path)
+	logJSON := request["resourceLogs"].([]any)[0].(map[string]any)["scopeLogs"].([]any)[0].(map[string]any)["logRecords"].([]any)[0].(map[string]any)
+	if logJSON["traceId"] != spanContext.TraceID().String() || logJSON["severityNumber"] != float64(log.SeverityError) {
+		t.Fatalf("expected error record with hex encoded trace id, got %v", logJSON)
+	}
+
+	if body := logJSON["body"].(map[string]any)["stringValue"]; body != "Failed to hash data" {
+		t.Fatalf("expected string body, got %v", body)
+	}
+}
+
+// readLine reads the only line of OTLP-JSON file at path
+func readLine(t *testing.T, path string) map[string]any {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected single line, got %d", len(lines))
+	}
+
+	var request map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &request); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+
+	return request
+}
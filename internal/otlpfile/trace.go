@@ -0,0 +1,47 @@
+package otlpfile
+
+import (
+	"context"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otlpconfig"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// traceClient is OTLP trace client writing every export request as OTLP-JSON line.
+type traceClient struct {
+	writer *Writer
+}
+
+// NewTraceExporter creates span exporter appending OTLP-JSON lines to the file configured
+// by CRYPTO_BROKER_OTEL_TRACES_FILE, rotated according to CRYPTO_BROKER_OTEL_FILE_MAX_SIZE_MB
+// and CRYPTO_BROKER_OTEL_FILE_MAX_BACKUPS.
+func NewTraceExporter(ctx context.Context) (*otlptrace.Exporter, error) {
+	writer, err := newWriterFromEnv(otlpconfig.SignalTraces)
+	if err != nil {
+		return nil, err
+	}
+
+	return otlptrace.New(ctx, &traceClient{writer: writer})
+}
+
+// Start does nothing, the file is opened when the exporter is created.
+func (c *traceClient) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop closes the file.
+func (c *traceClient) Stop(ctx context.Context) error {
+	return c.writer.Close()
+}
+
+// UploadTraces writes spans as single ExportTraceServiceRequest line.
+func (c *traceClient) UploadTraces(ctx context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	line, err := marshalLine(&coltracepb.ExportTraceServiceRequest{ResourceSpans: protoSpans})
+	if err != nil {
+		return err
+	}
+
+	return c.writer.WriteLine(line)
+}
@@ -0,0 +1,176 @@
+// Package otlpfile contains file exporters writing telemetry as OTLP-JSON lines,
+// the format read by the OpenTelemetry Collector file receiver, so that telemetry captured
+// without a collector can be shipped after the fact.
+package otlpfile
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/env"
+	"github.com/open-crypto-broker/crypto-broker-cli-go/internal/otlpconfig"
+)
+
+// default values of file exporter configuration
+const (
+	defaultTracesFile = "crypto-broker-traces.jsonl"
+	defaultLogsFile   = "crypto-broker-logs.jsonl"
+	defaultMaxSizeMB  = 100
+	defaultMaxBackups = 5
+)
+
+// Writer appends lines to a file and rotates it once it would exceed the maximum size.
+// Rotated files are named "<path>.1" (newest) to "<path>.<maxBackups>", older files are removed.
+type Writer struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewWriter opens file at path for appending, creating it if it does not exist.
+func NewWriter(path string, maxSize int64, maxBackups int) (*Writer, error) {
+	w := &Writer{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// newWriterFromEnv opens writer of signal configured by environment variables.
+func newWriterFromEnv(signal otlpconfig.Signal) (*Writer, error) {
+	path := defaultTracesFile
+	pathKey := env.OTEL_TRACES_FILE
+	if signal == otlpconfig.SignalLogs {
+		path, pathKey = defaultLogsFile, env.OTEL_LOGS_FILE
+	}
+
+	if customPath := os.Getenv(pathKey); customPath != "" {
+		path = customPath
+	}
+
+	maxSizeMB, err := parseNonNegativeInt(env.OTEL_FILE_MAX_SIZE_MB, defaultMaxSizeMB)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBackups, err := parseNonNegativeInt(env.OTEL_FILE_MAX_BACKUPS, defaultMaxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWriter(path, int64(maxSizeMB)<<20, maxBackups)
+}
+
+// parseNonNegativeInt parses environment variable key as non-negative integer, returning defaultValue if it is not set.
+func parseNonNegativeInt(key string, defaultValue int) (int, error) {
+	rawValue := os.Getenv(key)
+	if rawValue == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(rawValue)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s value %s, expected non-negative integer", key, rawValue)
+	}
+
+	return value, nil
+}
+
+// WriteLine writes line followed by newline, rotating the file first if the line would not fit.
+// A line larger than the maximum size is written to an empty file.
+func (w *Writer) WriteLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return fmt.Errorf("telemetry file %s is closed", w.path)
+	}
+
+	lineSize := int64(len(line)) + 1
+	if w.maxSize > 0 && w.size > 0 && w.size+lineSize > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.file.Write(append(line, '\n'))
+	w.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write telemetry file %s: %w", w.path, err)
+	}
+
+	return nil
+}
+
+// Close closes the file, following writes fail.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+
+	return err
+}
+
+// open opens the file for appending and reads its current size.
+func (w *Writer) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open telemetry file %s: %w", w.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat telemetry file %s: %w", w.path, err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+
+	return nil
+}
+
+// rotate shifts rotated files by one, moves the current file to "<path>.1" and reopens an empty file.
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close telemetry file %s: %w", w.path, err)
+	}
+	w.file = nil
+
+	if w.maxBackups == 0 {
+		if err := os.Remove(w.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove telemetry file %s: %w", w.path, err)
+		}
+
+		return w.open()
+	}
+
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(w.backupPath(i), w.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate telemetry file %s: %w", w.backupPath(i), err)
+		}
+	}
+
+	if err := os.Rename(w.path, w.backupPath(1)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate telemetry file %s: %w", w.path, err)
+	}
+
+	return w.open()
+}
+
+// backupPath returns path of i-th rotated file.
+func (w *Writer) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", w.path, i)
+}
@@ -0,0 +1,425 @@
+// Package pool contains pool of crypto broker library connections shared by concurrent requests.
+package pool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MaxSize is the highest supported number of libraries in pool.
+const MaxSize = 1024
+
+// Errors returned by Acquire.
+var (
+	ErrClosed    = errors.New("library pool is shut down")
+	ErrNoLibrary = errors.New("library pool has no healthy library, evicted libraries are reconnecting")
+)
+
+// attribute keys of pool metrics in spans
+var (
+	attributeSize            = attribute.Key("pool.size")
+	attributeHealthy         = attribute.Key("pool.healthy")
+	attributeInFlight        = attribute.Key("pool.in_flight")
+	attributeEvictions       = attribute.Key("pool.evictions")
+	attributeLibrary         = attribute.Key("pool.library")
+	attributeLibraryInFlight = attribute.Key("pool.library.in_flight")
+)
+
+// Config defines size of pool and health checks of its libraries.
+type Config struct {
+	// Size is number of library connections.
+	Size int
+	// HealthInterval is delay between health checks of libraries, zero disables health checks.
+	HealthInterval time.Duration
+	// HealthTimeout bounds single health check, HealthInterval is used when zero.
+	HealthTimeout time.Duration
+	// FailureThreshold is number of consecutive failed health checks after which library is evicted
+	// and replaced by new connection, 1 when zero.
+	FailureThreshold int
+}
+
+// Stats is snapshot of pool usage.
+type Stats struct {
+	// Size is configured number of libraries.
+	Size int
+	// Healthy is number of libraries requests are sent through, evicted libraries are not counted.
+	Healthy int
+	// InFlight is number of requests in flight across libraries.
+	InFlight int
+	// Requests is number of requests sent through pool since it was created.
+	Requests int64
+	// Evictions is number of libraries evicted after failed health checks.
+	Evictions int
+}
+
+// Attributes returns stats as span attributes.
+func (stats Stats) Attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attributeSize.Int(stats.Size),
+		attributeHealthy.Int(stats.Healthy),
+		attributeInFlight.Int(stats.InFlight),
+		attributeEvictions.Int(stats.Evictions),
+	}
+}
+
+// library is library connection of pool together with its load and health.
+type library struct {
+	id       int
+	lib      *cryptobrokerclientgo.Library
+	inFlight int
+	requests int64
+	failures int
+	evicted  bool
+}
+
+// Pool distributes requests across multiple library connections. Every request is sent through
+// the least loaded library, i.e. the one with fewest requests in flight. Libraries failing consecutive
+// health checks are evicted and replaced, so that requests are not sent through broken connections.
+type Pool struct {
+	logger     *slog.Logger
+	config     Config
+	connection connection
+
+	mu        sync.Mutex
+	libraries []*library
+	// connecting is number of replacements of evicted libraries being connected
+	connecting int
+	nextId     int
+	requests   int64
+	evictions  int
+	closed     bool
+
+	// inFlight tracks acquired libraries, so that shutdown waits until they are released
+	inFlight sync.WaitGroup
+	// background tracks health checks and reconnections, which are cancelled on shutdown
+	background sync.WaitGroup
+	cancel     context.CancelFunc
+}
+
+// connection connects, health checks and closes libraries of pool.
+type connection struct {
+	connect     func(ctx context.Context) (*cryptobrokerclientgo.Library, error)
+	checkHealth func(ctx context.Context, lib *cryptobrokerclientgo.Library) bool
+	close       func(lib *cryptobrokerclientgo.Library) error
+}
+
+// brokerConnection connects libraries to the broker.
+var brokerConnection = connection{
+	connect: func(ctx context.Context) (*cryptobrokerclientgo.Library, error) {
+		return cryptobrokerclientgo.NewLibrary(ctx)
+	},
+	checkHealth: func(ctx context.Context, lib *cryptobrokerclientgo.Library) bool {
+		return lib.HealthData(ctx).Status == cryptobrokerclientgo.StatusServing
+	},
+	close: (*cryptobrokerclientgo.Library).Close,
+}
+
+// New connects config.Size libraries to the broker and starts health checks of them.
+func New(ctx context.Context, logger *slog.Logger, config Config) (*Pool, error) {
+	return newPool(ctx, logger, config, brokerConnection)
+}
+
+// newPool creates pool of libraries of given connection.
+func newPool(ctx context.Context, logger *slog.Logger, config Config, connection connection) (*Pool, error) {
+	if config.Size < 1 || config.Size > MaxSize {
+		return nil, fmt.Errorf("pool size must be between 1 and %d", MaxSize)
+	}
+
+	if config.HealthInterval < 0 || config.HealthTimeout < 0 || config.FailureThreshold < 0 {
+		return nil, errors.New("pool health interval, timeout and failure threshold must not be negative")
+	}
+
+	config.HealthTimeout = cmp.Or(config.HealthTimeout, config.HealthInterval)
+	config.FailureThreshold = max(config.FailureThreshold, 1)
+
+	pool := &Pool{
+		logger:     logger,
+		config:     config,
+		connection: connection,
+	}
+
+	libs := make([]*cryptobrokerclientgo.Library, config.Size)
+	errs := make([]error, config.Size)
+	var wg sync.WaitGroup
+	for i := range config.Size {
+		wg.Go(func() { libs[i], errs[i] = connection.connect(ctx) })
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		for _, lib := range libs {
+			if lib != nil {
+				_ = connection.close(lib)
+			}
+		}
+
+		return nil, fmt.Errorf("could not connect library pool, err: %w", err)
+	}
+
+	for _, lib := range libs {
+		pool.add(lib)
+	}
+
+	// background work outlives ctx of the caller, but keeps its values, e.g. correlation id
+	backgroundCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	pool.cancel = cancel
+	if config.HealthInterval > 0 {
+		pool.background.Go(func() { pool.monitor(backgroundCtx) })
+	}
+
+	return pool, nil
+}
+
+// add adds connected library to pool, caller must hold lock unless pool is being created.
+func (pool *Pool) add(lib *cryptobrokerclientgo.Library) {
+	pool.nextId++
+	pool.libraries = append(pool.libraries, &library{id: pool.nextId, lib: lib})
+}
+
+// Acquire returns the least loaded library of pool and function releasing it, which must be called
+// once the request completes. Pool metrics are added to span of ctx.
+func (pool *Pool) Acquire(ctx context.Context) (*cryptobrokerclientgo.Library, func(), error) {
+	pool.mu.Lock()
+	if pool.closed {
+		pool.mu.Unlock()
+		return nil, nil, ErrClosed
+	}
+
+	var selected *library
+	for _, candidate := range pool.libraries {
+		// ties are broken by total requests, so that idle pool rotates through its libraries
+		if selected == nil || candidate.inFlight < selected.inFlight ||
+			candidate.inFlight == selected.inFlight && candidate.requests < selected.requests {
+			selected = candidate
+		}
+	}
+
+	if selected == nil {
+		pool.mu.Unlock()
+		return nil, nil, ErrNoLibrary
+	}
+
+	selected.inFlight++
+	selected.requests++
+	pool.requests++
+	pool.inFlight.Add(1)
+	stats := pool.stats()
+	libraryInFlight := selected.inFlight
+	pool.mu.Unlock()
+
+	trace.SpanFromContext(ctx).SetAttributes(append(stats.Attributes(),
+		attributeLibrary.Int(selected.id),
+		attributeLibraryInFlight.Int(libraryInFlight),
+	)...)
+
+	var once sync.Once
+	release := func() { once.Do(func() { pool.release(selected) }) }
+	return selected.lib, release, nil
+}
+
+// Bind returns function sending request by library method, e.g. (*cryptobrokerclientgo.Library).HashData,
+// through the least loaded library of pool. It matches call of retry.Do, so every attempt acquires library anew.
+func Bind[P any, R any](pool *Pool, method func(*cryptobrokerclientgo.Library, context.Context, P) (R, error)) func(context.Context, P) (R, error) {
+	return func(ctx context.Context, payload P) (R, error) {
+		lib, release, err := pool.Acquire(ctx)
+		if err != nil {
+			var response R
+			return response, err
+		}
+		defer release()
+
+		return method(lib, ctx, payload)
+	}
+}
+
+// release returns library acquired for request. Evicted library is closed once its last request is released.
+func (pool *Pool) release(selected *library) {
+	pool.mu.Lock()
+	selected.inFlight--
+	closeEvicted := selected.evicted && selected.inFlight == 0
+	pool.mu.Unlock()
+
+	if closeEvicted {
+		pool.closeLibrary(selected)
+	}
+
+	pool.inFlight.Done()
+}
+
+// Stats returns snapshot of pool usage.
+func (pool *Pool) Stats() Stats {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	return pool.stats()
+}
+
+// stats returns snapshot of pool usage, caller must hold lock.
+func (pool *Pool) stats() Stats {
+	stats := Stats{
+		Size:      pool.config.Size,
+		Healthy:   len(pool.libraries),
+		Requests:  pool.requests,
+		Evictions: pool.evictions,
+	}
+
+	for _, library := range pool.libraries {
+		stats.InFlight += library.inFlight
+	}
+
+	return stats
+}
+
+// monitor checks health of libraries every health interval until ctx is cancelled.
+func (pool *Pool) monitor(ctx context.Context) {
+	ticker := time.NewTicker(pool.config.HealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pool.checkLibraries(ctx)
+		}
+	}
+}
+
+// checkLibraries checks health of every library concurrently, evicts libraries that reached failure threshold
+// and starts reconnection of libraries missing in pool.
+func (pool *Pool) checkLibraries(ctx context.Context) {
+	pool.mu.Lock()
+	libraries := append([]*library(nil), pool.libraries...)
+	pool.mu.Unlock()
+
+	healthy := make([]bool, len(libraries))
+	var wg sync.WaitGroup
+	for i, library := range libraries {
+		wg.Go(func() {
+			checkCtx, cancel := context.WithTimeout(ctx, pool.config.HealthTimeout)
+			defer cancel()
+			healthy[i] = pool.connection.checkHealth(checkCtx, library.lib)
+		})
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	for i, library := range libraries {
+		if healthy[i] {
+			library.failures = 0
+			continue
+		}
+
+		library.failures++
+		if library.failures >= pool.config.FailureThreshold && !library.evicted {
+			pool.evict(ctx, library)
+		}
+	}
+
+	for range pool.config.Size - len(pool.libraries) - pool.connecting {
+		pool.connecting++
+		pool.background.Go(func() { pool.reconnect(ctx) })
+	}
+}
+
+// evict removes library from pool, caller must hold lock. Library is closed once requests in flight complete.
+func (pool *Pool) evict(ctx context.Context, evicted *library) {
+	evicted.evicted = true
+	pool.evictions++
+	for i, library := range pool.libraries {
+		if library == evicted {
+			pool.libraries = append(pool.libraries[:i], pool.libraries[i+1:]...)
+			break
+		}
+	}
+
+	pool.logger.WarnContext(ctx, "Evicted unhealthy library from pool", "library", evicted.id,
+		"failed_checks", evicted.failures, "in_flight", evicted.inFlight)
+	if evicted.inFlight == 0 {
+		pool.background.Go(func() { pool.closeLibrary(evicted) })
+	}
+}
+
+// reconnect connects library replacing evicted one. Failed connection is retried by next health check.
+func (pool *Pool) reconnect(ctx context.Context) {
+	lib, err := pool.connection.connect(ctx)
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.connecting--
+	if err != nil {
+		if ctx.Err() == nil {
+			pool.logger.WarnContext(ctx, "Failed to reconnect library of pool", "error", err)
+		}
+
+		return
+	}
+
+	if pool.closed {
+		_ = pool.connection.close(lib)
+		return
+	}
+
+	pool.add(lib)
+	pool.logger.InfoContext(ctx, "Reconnected library of pool", "library", pool.nextId)
+}
+
+// closeLibrary closes connection of evicted library.
+func (pool *Pool) closeLibrary(evicted *library) {
+	if err := pool.connection.close(evicted.lib); err != nil {
+		pool.logger.Warn("Failed to close evicted library", "library", evicted.id, "error", err)
+	}
+}
+
+// Shutdown stops handing out libraries and waits until requests in flight are released or ctx is done,
+// then it closes every library of pool. Subsequent calls return nil.
+func (pool *Pool) Shutdown(ctx context.Context) error {
+	pool.mu.Lock()
+	if pool.closed {
+		pool.mu.Unlock()
+		return nil
+	}
+
+	pool.closed = true
+	pool.mu.Unlock()
+
+	pool.cancel()
+	pool.background.Wait()
+
+	drained := make(chan struct{})
+	go func() {
+		pool.inFlight.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = fmt.Errorf("library pool was not drained, %d requests in flight, err: %w", pool.Stats().InFlight, ctx.Err())
+	}
+
+	pool.mu.Lock()
+	libraries := pool.libraries
+	pool.libraries = nil
+	pool.mu.Unlock()
+
+	for _, library := range libraries {
+		err = errors.Join(err, pool.connection.close(library.lib))
+	}
+
+	return err
+}
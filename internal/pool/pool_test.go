@@ -0,0 +1,290 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	cryptobrokerclientgo "github.com/open-crypto-broker/crypto-broker-client-go"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// testConnection connects libraries without broker, health of every library is controlled by test.
+type testConnection struct {
+	mu        sync.Mutex
+	connected int
+	fail      bool
+	unhealthy map[*cryptobrokerclientgo.Library]bool
+	closed    map[*cryptobrokerclientgo.Library]bool
+}
+
+func newTestConnection() *testConnection {
+	return &testConnection{
+		unhealthy: make(map[*cryptobrokerclientgo.Library]bool),
+		closed:    make(map[*cryptobrokerclientgo.Library]bool),
+	}
+}
+
+func (test *testConnection) connection() connection {
+	return connection{
+		connect: func(ctx context.Context) (*cryptobrokerclientgo.Library, error) {
+			test.mu.Lock()
+			defer test.mu.Unlock()
+			test.connected++
+			if test.fail && test.connected > 1 {
+				return nil, errors.New("connection refused")
+			}
+
+			return &cryptobrokerclientgo.Library{}, nil
+		},
+		checkHealth: func(ctx context.Context, lib *cryptobrokerclientgo.Library) bool {
+			test.mu.Lock()
+			defer test.mu.Unlock()
+			return !test.unhealthy[lib]
+		},
+		close: func(lib *cryptobrokerclientgo.Library) error {
+			test.mu.Lock()
+			defer test.mu.Unlock()
+			test.closed[lib] = true
+			return nil
+		},
+	}
+}
+
+func (test *testConnection) setUnhealthy(lib *cryptobrokerclientgo.Library) {
+	test.mu.Lock()
+	defer test.mu.Unlock()
+	test.unhealthy[lib] = true
+}
+
+func (test *testConnection) isClosed(lib *cryptobrokerclientgo.Library) bool {
+	test.mu.Lock()
+	defer test.mu.Unlock()
+	return test.closed[lib]
+}
+
+func newTestPool(t *testing.T, config Config, connection *testConnection) *Pool {
+	t.Helper()
+	pool, err := newPool(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), config, connection.connection())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	return pool
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	for name, config := range map[string]Config{
+		"zero_size":          {},
+		"too_large":          {Size: MaxSize + 1},
+		"negative_interval":  {Size: 1, HealthInterval: -time.Second},
+		"negative_threshold": {Size: 1, FailureThreshold: -1},
+	} {
+		if _, err := newPool(context.Background(), slog.Default(), config, newTestConnection().connection()); err == nil {
+			t.Fatalf("%s: expected error, got nil", name)
+		}
+	}
+
+	// libraries connected before failure are closed
+	connection := newTestConnection()
+	connection.fail = true
+	if _, err := newPool(context.Background(), slog.Default(), Config{Size: 3}, connection.connection()); err == nil {
+		t.Fatalf("expected connection error, got nil")
+	}
+
+	if len(connection.closed) != 1 {
+		t.Fatalf("expected connected library closed, got %d closed", len(connection.closed))
+	}
+}
+
+func TestAcquire_LeastLoaded(t *testing.T) {
+	t.Parallel()
+
+	pool := newTestPool(t, Config{Size: 3}, newTestConnection())
+	acquired := make(map[*cryptobrokerclientgo.Library]func())
+	for range 3 {
+		lib, release, err := pool.Acquire(context.Background())
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		acquired[lib] = release
+	}
+
+	if len(acquired) != 3 {
+		t.Fatalf("expected requests spread across 3 libraries, got %d", len(acquired))
+	}
+
+	var released *cryptobrokerclientgo.Library
+	for lib, release := range acquired {
+		released = lib
+		release()
+		// releasing twice has no effect
+		release()
+		break
+	}
+
+	lib, release, err := pool.Acquire(context.Background())
+	if err != nil || lib != released {
+		t.Fatalf("expected released library, got %v", err)
+	}
+	release()
+
+	if stats := pool.Stats(); stats.InFlight != 2 || stats.Requests != 4 || stats.Healthy != 3 {
+		t.Fatalf("expected 2 requests in flight of 4 across 3 libraries, got %+v", stats)
+	}
+}
+
+func TestPool_Eviction(t *testing.T) {
+	t.Parallel()
+
+	connection := newTestConnection()
+	pool := newTestPool(t, Config{Size: 2, HealthTimeout: time.Second, FailureThreshold: 2}, connection)
+	lib, release, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	connection.setUnhealthy(lib)
+	ctx := context.Background()
+	pool.checkLibraries(ctx)
+	if stats := pool.Stats(); stats.Evictions != 0 {
+		t.Fatalf("expected no eviction below failure threshold, got %+v", stats)
+	}
+
+	pool.checkLibraries(ctx)
+	pool.background.Wait()
+	if stats := pool.Stats(); stats.Evictions != 1 || stats.Healthy != 2 || stats.InFlight != 0 {
+		t.Fatalf("expected evicted library replaced, got %+v", stats)
+	}
+
+	// evicted library is not handed out and it is closed once its request is released
+	for range 4 {
+		other, releaseOther, err := pool.Acquire(ctx)
+		if err != nil || other == lib {
+			t.Fatalf("expected healthy library, got %v", err)
+		}
+		releaseOther()
+	}
+
+	if connection.isClosed(lib) {
+		t.Fatalf("expected evicted library open until its request is released")
+	}
+
+	release()
+	if !connection.isClosed(lib) {
+		t.Fatalf("expected evicted library closed")
+	}
+}
+
+func TestPool_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	connection := newTestConnection()
+	pool := newTestPool(t, Config{Size: 2, HealthInterval: time.Hour}, connection)
+	lib, release, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	done := make(chan error)
+	go func() { done <- pool.Shutdown(context.Background()) }()
+
+	// requests in flight are drained, while new requests are refused
+	for {
+		_, releaseOther, err := pool.Acquire(context.Background())
+		if errors.Is(err, ErrClosed) {
+			break
+		}
+
+		releaseOther()
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case err := <-done:
+		t.Fatalf("expected shutdown waiting for request in flight, got %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	release()
+	if err := <-done; err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if !connection.isClosed(lib) || len(connection.closed) != 2 {
+		t.Fatalf("expected every library closed, got %d closed", len(connection.closed))
+	}
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected nil error of repeated shutdown, got %v", err)
+	}
+}
+
+func TestPool_ShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	connection := newTestConnection()
+	pool := newTestPool(t, Config{Size: 1}, connection)
+	_, release, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded error, got %v", err)
+	}
+
+	if len(connection.closed) != 1 {
+		t.Fatalf("expected library closed after drain timeout, got %d closed", len(connection.closed))
+	}
+}
+
+func TestBind(t *testing.T) {
+	t.Parallel()
+
+	pool := newTestPool(t, Config{Size: 2}, newTestConnection())
+	recorder := tracetest.NewSpanRecorder()
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "call")
+
+	call := Bind(pool, func(lib *cryptobrokerclientgo.Library, ctx context.Context, payload string) (int, error) {
+		if stats := pool.Stats(); stats.InFlight != 1 {
+			t.Errorf("expected library acquired during call, got %+v", stats)
+		}
+
+		return len(payload), nil
+	})
+
+	if response, err := call(ctx, "abc"); err != nil || response != 3 {
+		t.Fatalf("expected response 3, got %d, %v", response, err)
+	}
+	span.End()
+
+	attributes := make(map[string]int64)
+	for _, attribute := range recorder.Ended()[0].Attributes() {
+		attributes[string(attribute.Key)] = attribute.Value.AsInt64()
+	}
+
+	if attributes["pool.size"] != 2 || attributes["pool.in_flight"] != 1 || attributes["pool.library"] == 0 {
+		t.Fatalf("expected pool metrics in span, got %v", attributes)
+	}
+
+	if stats := pool.Stats(); stats.InFlight != 0 || stats.Requests != 1 {
+		t.Fatalf("expected library released after call, got %+v", stats)
+	}
+
+	_ = pool.Shutdown(context.Background())
+	if _, err := call(ctx, "abc"); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected closed pool error, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+// Package retry contains client-side retry policy applied to crypto broker library calls.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxRetries is the highest supported number of retries.
+const MaxRetries = 100
+
+// attribute keys of attempt spans and retry events
+var (
+	attributeAttempt     = attribute.Key("retry.attempt")
+	attributeMaxAttempts = attribute.Key("retry.max_attempts")
+	attributeDelayMs     = attribute.Key("retry.delay_ms")
+	attributeGrpcCode    = attribute.Key("rpc.grpc.status_code")
+)
+
+// Policy defines how failed library calls are retried.
+// Zero value performs a single attempt without retries.
+type Policy struct {
+	// MaxRetries number of retries after the first attempt
+	MaxRetries int
+
+	// Backoff delay before the first retry, doubled for every next retry
+	Backoff time.Duration
+
+	// MaxDelay upper bound of a single delay
+	MaxDelay time.Duration
+
+	// RetryOn gRPC status codes that are retried
+	RetryOn []codes.Code
+}
+
+// NewPolicy builds policy from flag values. Codes may be given by name (UNAVAILABLE, unavailable) or number (14).
+func NewPolicy(maxRetries int, backoff time.Duration, maxDelay time.Duration, retryOn []string) (Policy, error) {
+	if maxRetries < 0 || maxRetries > MaxRetries {
+		return Policy{}, fmt.Errorf("retries must be between 0 and %d, got %d", MaxRetries, maxRetries)
+	}
+
+	if backoff <= 0 {
+		return Policy{}, fmt.Errorf("retry backoff must be positive, got %s", backoff)
+	}
+
+	if maxDelay < backoff {
+		return Policy{}, fmt.Errorf("retry max delay (%s) must not be lower than retry backoff (%s)", maxDelay, backoff)
+	}
+
+	retryCodes, err := ParseCodes(retryOn)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return Policy{
+		MaxRetries: maxRetries,
+		Backoff:    backoff,
+		MaxDelay:   maxDelay,
+		RetryOn:    retryCodes,
+	}, nil
+}
+
+// ParseCodes converts names or numbers of gRPC status codes into codes.
+func ParseCodes(names []string) ([]codes.Code, error) {
+	parsed := make([]codes.Code, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		raw := name
+		if _, err := strconv.ParseUint(name, 10, 32); err != nil {
+			raw = strconv.Quote(strings.ToUpper(strings.ReplaceAll(name, "-", "_")))
+		}
+
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(raw)); err != nil {
+			return nil, fmt.Errorf("invalid gRPC status code %q: %w", name, err)
+		}
+
+		parsed = append(parsed, code)
+	}
+
+	return parsed, nil
+}
+
+// Retryable reports whether the error is worth another attempt.
+func (policy Policy) Retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	return slices.Contains(policy.RetryOn, status.Code(err))
+}
+
+// Delay returns jittered delay before given retry (1-based).
+// The upper bound grows exponentially from Backoff and is capped at MaxDelay, the delay is drawn uniformly below it.
+func (policy Policy) Delay(retry int) time.Duration {
+	ceiling := policy.Backoff
+	for i := 1; i < retry && ceiling < policy.MaxDelay; i++ {
+		ceiling *= 2
+	}
+
+	ceiling = min(ceiling, policy.MaxDelay)
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+// Do invokes call with payload until it succeeds, returns error that is not retryable or retries are exhausted.
+// Every attempt runs in its own child span named spanName. The prepare function, if not nil, is called with
+// attempt context before every attempt, e.g. to inject trace context of the attempt span into the payload.
+// Payload is passed unchanged to every attempt, so the request metadata is the same across attempts.
+func Do[P any, R any](
+	ctx context.Context,
+	policy Policy,
+	tracer trace.Tracer,
+	spanName string,
+	payload P,
+	prepare func(ctx context.Context),
+	call func(context.Context, P) (R, error),
+) (R, error) {
+	maxAttempts := policy.MaxRetries + 1
+	parentSpan := trace.SpanFromContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		response, err := attemptCall(ctx, tracer, spanName, attempt, maxAttempts, payload, prepare, call)
+		if err == nil || attempt >= maxAttempts || !policy.Retryable(err) {
+			return response, err
+		}
+
+		delay := policy.Delay(attempt)
+		parentSpan.AddEvent("retry", trace.WithAttributes(
+			attributeAttempt.Int(attempt+1),
+			attributeDelayMs.Int64(delay.Milliseconds()),
+			attributeGrpcCode.Int(int(status.Code(err))),
+		))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return response, errors.Join(err, ctx.Err())
+		}
+	}
+}
+
+// attemptCall performs a single attempt within its own span.
+func attemptCall[P any, R any](
+	ctx context.Context,
+	tracer trace.Tracer,
+	spanName string,
+	attempt int,
+	maxAttempts int,
+	payload P,
+	prepare func(ctx context.Context),
+	call func(context.Context, P) (R, error),
+) (R, error) {
+	ctx, span := tracer.Start(ctx, spanName, trace.WithAttributes(
+		attributeAttempt.Int(attempt),
+		attributeMaxAttempts.Int(maxAttempts),
+	))
+	defer span.End()
+
+	if prepare != nil {
+		prepare(ctx)
+	}
+
+	response, err := call(ctx, payload)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		span.SetAttributes(attributeGrpcCode.Int(int(status.Code(err))))
+	}
+
+	return response, err
+}
@@ -0,0 +1,158 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseCodes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("names_and_numbers", func(t *testing.T) {
+		t.Parallel()
+		got, err := ParseCodes([]string{"UNAVAILABLE", "resource_exhausted", "deadline-exceeded", "10", " "})
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+
+		want := []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.DeadlineExceeded, codes.Aborted}
+		if len(got) != len(want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("expected %v, got %v", want, got)
+			}
+		}
+	})
+
+	t.Run("invalid_code", func(t *testing.T) {
+		t.Parallel()
+		if _, err := ParseCodes([]string{"NOT_A_CODE"}); err == nil {
+			t.Fatalf("expected error for unknown code name")
+		}
+
+		if _, err := ParseCodes([]string{"99"}); err == nil {
+			t.Fatalf("expected error for out of range code")
+		}
+	})
+}
+
+func TestNewPolicy(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewPolicy(-1, time.Millisecond, time.Second, nil); err == nil {
+		t.Fatalf("expected error for negative retries")
+	}
+
+	if _, err := NewPolicy(1, 0, time.Second, nil); err == nil {
+		t.Fatalf("expected error for zero backoff")
+	}
+
+	if _, err := NewPolicy(1, time.Second, time.Millisecond, nil); err == nil {
+		t.Fatalf("expected error for max delay lower than backoff")
+	}
+}
+
+func TestPolicy_Delay(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{Backoff: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	for retry, ceiling := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 3: 40 * time.Millisecond, 10: 50 * time.Millisecond} {
+		for range 100 {
+			if delay := policy.Delay(retry); delay < 0 || delay > ceiling {
+				t.Fatalf("retry %d: delay %v out of range [0, %v]", retry, delay, ceiling)
+			}
+		}
+	}
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	tracer := noop.NewTracerProvider().Tracer("test")
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+
+	t.Run("retries_until_success_with_same_payload", func(t *testing.T) {
+		t.Parallel()
+		policy := Policy{MaxRetries: 3, Backoff: time.Microsecond, MaxDelay: time.Microsecond, RetryOn: []codes.Code{codes.Unavailable}}
+
+		var payloads []string
+		prepared := 0
+		got, err := Do(context.Background(), policy, tracer, "attempt", "metadata-id",
+			func(ctx context.Context) { prepared++ },
+			func(ctx context.Context, payload string) (int, error) {
+				payloads = append(payloads, payload)
+				if len(payloads) < 3 {
+					return 0, unavailable
+				}
+
+				return 42, nil
+			})
+		if err != nil || got != 42 {
+			t.Fatalf("expected 42 and nil error, got %d and %v", got, err)
+		}
+
+		if len(payloads) != 3 || prepared != 3 {
+			t.Fatalf("expected 3 attempts and 3 prepare calls, got %d and %d", len(payloads), prepared)
+		}
+
+		for _, payload := range payloads {
+			if payload != "metadata-id" {
+				t.Fatalf("expected payload to be reused, got %q", payload)
+			}
+		}
+	})
+
+	t.Run("gives_up_after_max_retries", func(t *testing.T) {
+		t.Parallel()
+		policy := Policy{MaxRetries: 2, Backoff: time.Microsecond, MaxDelay: time.Microsecond, RetryOn: []codes.Code{codes.Unavailable}}
+
+		attempts := 0
+		_, err := Do(context.Background(), policy, tracer, "attempt", struct{}{}, nil,
+			func(ctx context.Context, _ struct{}) (int, error) {
+				attempts++
+				return 0, unavailable
+			})
+		if !errors.Is(err, unavailable) || attempts != 3 {
+			t.Fatalf("expected 3 attempts ending with unavailable, got %d and %v", attempts, err)
+		}
+	})
+
+	t.Run("does_not_retry_other_codes", func(t *testing.T) {
+		t.Parallel()
+		policy := Policy{MaxRetries: 5, Backoff: time.Microsecond, MaxDelay: time.Microsecond, RetryOn: []codes.Code{codes.Unavailable}}
+
+		attempts := 0
+		_, err := Do(context.Background(), policy, tracer, "attempt", struct{}{}, nil,
+			func(ctx context.Context, _ struct{}) (int, error) {
+				attempts++
+				return 0, status.Error(codes.InvalidArgument, "invalid")
+			})
+		if err == nil || attempts != 1 {
+			t.Fatalf("expected single failed attempt, got %d and %v", attempts, err)
+		}
+	})
+
+	t.Run("stops_waiting_on_cancelled_context", func(t *testing.T) {
+		t.Parallel()
+		policy := Policy{MaxRetries: 1, Backoff: time.Hour, MaxDelay: time.Hour, RetryOn: []codes.Code{codes.Unavailable}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		_, err := Do(ctx, policy, tracer, "attempt", struct{}{}, nil,
+			func(ctx context.Context, _ struct{}) (int, error) {
+				cancel()
+				return 0, unavailable
+			})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context cancellation, got %v", err)
+		}
+	})
+}
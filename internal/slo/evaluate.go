@@ -0,0 +1,175 @@
+package slo
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Case represents evaluation of single SLO against results of single benchmark.
+type Case struct {
+	SLO    SLO
+	Result *Result
+	// Measured values of the limits set by SLO, formatted for reports
+	Measured []string
+	// Failures describe violated limits, SLO without matching results fails as well
+	Failures []string
+	// Skipped describes why results were not evaluated, e.g. because benchmark itself failed
+	Skipped string
+}
+
+// Name returns name of the evaluated benchmark, or benchmark pattern or name of the SLO if no benchmark matched it.
+func (c Case) Name() string {
+	if c.Result == nil && c.SLO.Benchmark != "" {
+		return c.SLO.Benchmark
+	}
+
+	if c.Result == nil {
+		return c.SLO.Name()
+	}
+
+	return c.Result.Name
+}
+
+// Passed reports whether all limits are met.
+func (c Case) Passed() bool {
+	return len(c.Failures) == 0 && c.Skipped == ""
+}
+
+// Evaluate evaluates every SLO against every result it matches, see SLO.Matches.
+func Evaluate(slos []SLO, results []*Result) []Case {
+	var cases []Case
+	for _, slo := range slos {
+		matched := false
+		for _, result := range results {
+			if !slo.Matches(result) {
+				continue
+			}
+
+			matched = true
+			cases = append(cases, evaluate(slo, result))
+		}
+
+		if !matched {
+			cases = append(cases, Case{SLO: slo, Failures: []string{"no benchmark results found"}})
+		}
+	}
+
+	return cases
+}
+
+// Matches reports whether SLO applies to result. Operations and profiles are matched case-insensitively.
+func (slo SLO) Matches(result *Result) bool {
+	if slo.Operation != "" && !strings.EqualFold(slo.Operation, result.Operation) {
+		return false
+	}
+
+	if slo.Profile != "" && !strings.EqualFold(slo.Profile, result.Profile) {
+		return false
+	}
+
+	if slo.Benchmark != "" {
+		matched, err := path.Match(slo.Benchmark, result.Name)
+		return err == nil && matched
+	}
+
+	return true
+}
+
+// evaluate checks limits of slo against samples of result.
+func evaluate(slo SLO, result *Result) Case {
+	c := Case{SLO: slo, Result: result}
+	if result.Failed {
+		c.Skipped = "benchmark failed"
+		return c
+	}
+
+	if slo.MaxMeanLatency > 0 {
+		if len(result.Latencies) == 0 {
+			c.Failures = append(c.Failures, "latency not reported")
+		} else {
+			latency := mean(result.Latencies)
+			c.Measured = append(c.Measured, fmt.Sprintf("mean=%s", latency))
+			if latency > slo.MaxMeanLatency {
+				c.Failures = append(c.Failures, fmt.Sprintf("mean latency %s exceeds %s", latency, slo.MaxMeanLatency))
+			}
+		}
+	}
+
+	if slo.MinThroughput > 0 {
+		throughput, ok := result.throughput()
+		if !ok {
+			c.Failures = append(c.Failures, "throughput not reported")
+		} else {
+			c.Measured = append(c.Measured, fmt.Sprintf("throughput=%.2f/s", throughput))
+			if throughput < slo.MinThroughput {
+				c.Failures = append(c.Failures, fmt.Sprintf("throughput %.2f/s is below %.2f/s", throughput, slo.MinThroughput))
+			}
+		}
+	}
+
+	if slo.MaxErrorRate != nil {
+		errorRate := 0.0
+		if result.Requests > 0 {
+			errorRate = result.Errors / result.Requests
+		}
+
+		c.Measured = append(c.Measured, fmt.Sprintf("error_rate=%.4f", errorRate))
+		if errorRate > *slo.MaxErrorRate {
+			c.Failures = append(c.Failures, fmt.Sprintf("error rate %.4f exceeds %.4f", errorRate, *slo.MaxErrorRate))
+		}
+	}
+
+	if slo.MaxAllocs != nil {
+		if len(result.Allocs) == 0 {
+			c.Failures = append(c.Failures, "allocations not reported")
+		} else {
+			allocs := slices.Max(result.Allocs)
+			c.Measured = append(c.Measured, fmt.Sprintf("allocs=%.0f", allocs))
+			if allocs > float64(*slo.MaxAllocs) {
+				c.Failures = append(c.Failures, fmt.Sprintf("allocations %.0f/op exceed %d/op", allocs, *slo.MaxAllocs))
+			}
+		}
+	}
+
+	return c
+}
+
+// throughput returns median of reported throughput samples, or throughput derived from median latency.
+func (result *Result) throughput() (float64, bool) {
+	if len(result.Throughputs) > 0 {
+		sorted := slices.Clone(result.Throughputs)
+		slices.Sort(sorted)
+		return sorted[len(sorted)/2], true
+	}
+
+	if len(result.Latencies) > 0 {
+		latency := percentile(result.Latencies, 50)
+		if latency > 0 {
+			return float64(time.Second) / float64(latency), true
+		}
+	}
+
+	return 0, false
+}
+
+// mean returns arithmetic mean of non-empty samples.
+func mean(samples []time.Duration) time.Duration {
+	var sum time.Duration
+	for _, sample := range samples {
+		sum += sample
+	}
+
+	return sum / time.Duration(len(samples))
+}
+
+// percentile returns nearest-rank percentile of non-empty samples.
+func percentile(samples []time.Duration, p float64) time.Duration {
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
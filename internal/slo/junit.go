@@ -0,0 +1,75 @@
+package slo
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// junitSuiteName is name of the test suite of JUnit report
+const junitSuiteName = "crypto-broker-slo"
+
+// junitTestSuites is root element of JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+// junitSuite is JUnit test suite
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+// junitCase is JUnit test case, SLO is used as class name and benchmark as test name
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage is failure or skip reason of JUnit test case
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes cases to w as JUnit XML report, so that CI can display violated SLOs.
+func WriteJUnit(w io.Writer, cases []Case) error {
+	suite := junitSuite{Name: junitSuiteName, Tests: len(cases)}
+	for _, c := range cases {
+		testCase := junitCase{Name: c.Name(), ClassName: c.SLO.Name(), SystemOut: strings.Join(c.Measured, " ")}
+		switch {
+		case c.Skipped != "":
+			suite.Skipped++
+			testCase.Skipped = &junitMessage{Message: c.Skipped}
+		case len(c.Failures) > 0:
+			suite.Failures++
+			testCase.Failure = &junitMessage{Message: strings.Join(c.Failures, "; "), Text: strings.Join(c.Failures, "\n")}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	report := junitTestSuites{Tests: suite.Tests, Failures: suite.Failures, Skipped: suite.Skipped, Suites: []junitSuite{suite}}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,242 @@
+package slo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Result represents samples of single benchmark collected from one or more benchmark runs.
+type Result struct {
+	// Name is benchmark name without GOMAXPROCS suffix.
+	Name      string
+	Operation string
+	Profile   string
+	// Latencies are time per operation samples.
+	Latencies []time.Duration
+	// Throughputs are reported requests per second samples, e.g. of the stress benchmark.
+	Throughputs []float64
+	// Allocs are allocations per operation samples.
+	Allocs []float64
+	// Requests and Errors count requests reported by their gRPC status codes.
+	Requests float64
+	Errors   float64
+	// Failed is set when benchmark itself failed, e.g. because broker was not reachable.
+	Failed bool
+}
+
+// Results collects benchmark results by name in order of their first appearance.
+type Results struct {
+	byName map[string]*Result
+	order  []string
+}
+
+// NewResults initializes empty results.
+func NewResults() *Results {
+	return &Results{byName: make(map[string]*Result)}
+}
+
+// List returns collected results.
+func (results *Results) List() []*Result {
+	list := make([]*Result, 0, len(results.order))
+	for _, name := range results.order {
+		list = append(list, results.byName[name])
+	}
+
+	return list
+}
+
+// get returns result of benchmark with given name, operation is derived from the name when parseName is set.
+func (results *Results) get(name string, parseName bool) *Result {
+	if result, ok := results.byName[name]; ok {
+		return result
+	}
+
+	result := &Result{Name: name, Operation: name}
+	if parseName {
+		result.Operation, result.Profile = parseBenchmarkName(name)
+	}
+
+	results.byName[name] = result
+	results.order = append(results.order, name)
+	return result
+}
+
+// Parse reads benchmark results from r and adds them to results. Supported inputs are Go benchmark format,
+// e.g. of "go test -bench" or "benchmark --format gobench", "go test -json" events,
+// and server-side results of "benchmark --format json" or saved with "benchmark --save".
+func (results *Results) Parse(r io.Reader) error {
+	reader := bufio.NewReader(r)
+	for {
+		b, err := reader.Peek(1)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if b[0] == ' ' || b[0] == '\t' || b[0] == '\r' || b[0] == '\n' {
+			_, _ = reader.ReadByte()
+			continue
+		}
+
+		if b[0] == '{' {
+			return results.parseJSON(reader)
+		}
+
+		return results.parseGoBench(reader)
+	}
+}
+
+// parseJSON reads stream of JSON objects.
+func (results *Results) parseJSON(r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	var goTestOutput bytes.Buffer
+	for {
+		var object struct {
+			// go test -json event
+			Action string
+			Test   string
+			Output string
+			// benchmark --format json run
+			Run     *int               `json:"run"`
+			Results []serverSideResult `json:"results"`
+			// benchmark --save record
+			Runs []struct {
+				Results []serverSideResult `json:"results"`
+			} `json:"runs"`
+		}
+
+		err := decoder.Decode(&object)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to decode benchmark results, err: %w", err)
+		}
+
+		switch {
+		case object.Action != "":
+			// Benchmark lines may be split across output events, therefore output is parsed as a whole
+			goTestOutput.WriteString(object.Output)
+			if object.Action == "fail" && strings.HasPrefix(object.Test, benchmarkPrefix) {
+				results.get(trimBenchmarkName(object.Test), true).Failed = true
+			}
+		case object.Run != nil:
+			results.addServerSide(object.Results)
+		default:
+			for _, run := range object.Runs {
+				results.addServerSide(run.Results)
+			}
+		}
+	}
+
+	if goTestOutput.Len() == 0 {
+		return nil
+	}
+
+	return results.parseGoBench(&goTestOutput)
+}
+
+// serverSideResult represents single server-side benchmark result written by the benchmark command
+type serverSideResult struct {
+	Name    string `json:"name"`
+	AvgTime int64  `json:"avg_time_ns"`
+}
+
+// addServerSide adds results of single server-side benchmark run, names are used as operations as they are.
+func (results *Results) addServerSide(serverSideResults []serverSideResult) {
+	for _, serverSide := range serverSideResults {
+		result := results.get(serverSide.Name, false)
+		result.Latencies = append(result.Latencies, time.Duration(serverSide.AvgTime))
+	}
+}
+
+// benchmarkPrefix starts every Go benchmark name
+const benchmarkPrefix = "Benchmark"
+
+// failedBenchmarkPattern matches line reporting failed benchmark
+var failedBenchmarkPattern = regexp.MustCompile(`^\s*--- FAIL: (Benchmark\S+)`)
+
+// parseGoBench reads Go benchmark format lines: name, iterations and value-unit pairs.
+// Other lines, e.g. goos, pkg or PASS, are skipped.
+func (results *Results) parseGoBench(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if matches := failedBenchmarkPattern.FindStringSubmatch(line); matches != nil {
+			results.get(trimBenchmarkName(matches[1]), true).Failed = true
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 4 || len(fields)%2 != 0 || !strings.HasPrefix(fields[0], benchmarkPrefix) {
+			continue
+		}
+
+		if _, err := strconv.ParseInt(fields[1], 10, 64); err != nil {
+			continue
+		}
+
+		result := results.get(trimBenchmarkName(fields[0]), true)
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return fmt.Errorf("invalid value %q of benchmark %s", fields[i], fields[0])
+			}
+
+			unit := fields[i+1]
+			switch {
+			case unit == "ns/op":
+				result.Latencies = append(result.Latencies, time.Duration(value))
+			case unit == "allocs/op":
+				result.Allocs = append(result.Allocs, value)
+			case unit == "requests/s":
+				result.Throughputs = append(result.Throughputs, value)
+			case strings.HasPrefix(unit, "grpc_"):
+				result.Requests += value
+				if unit != "grpc_OK" {
+					result.Errors += value
+				}
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+// gomaxprocsSuffix matches GOMAXPROCS suffix go test appends to benchmark names
+var gomaxprocsSuffix = regexp.MustCompile(`-\d+$`)
+
+// trimBenchmarkName removes GOMAXPROCS suffix from benchmark name, so that results of different hosts are merged.
+func trimBenchmarkName(name string) string {
+	return gomaxprocsSuffix.ReplaceAllString(name, "")
+}
+
+// parseBenchmarkName derives operation and profile from Go benchmark name, e.g. operation "HashData"
+// and profile "Default" from "BenchmarkHashData_profile_Default_Sequential". Execution mode suffix is removed
+// from names without profile, e.g. operation of "BenchmarkHealth_Parallel" is "Health".
+func parseBenchmarkName(name string) (string, string) {
+	name = strings.TrimPrefix(name, benchmarkPrefix)
+	if operation, rest, ok := strings.Cut(name, "_profile_"); ok {
+		profile, _, _ := strings.Cut(rest, "_")
+		return operation, profile
+	}
+
+	for _, mode := range []string{"_Sequential", "_Parallel"} {
+		name = strings.TrimSuffix(name, mode)
+	}
+
+	return name, ""
+}
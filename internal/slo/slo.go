@@ -0,0 +1,104 @@
+// Package slo evaluates service level objectives of broker operations against benchmark results produced by the CLI.
+package slo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File represents YAML file with service level objectives.
+type File struct {
+	SLOs []SLO `yaml:"slos"`
+}
+
+// SLO represents limits of single operation, optionally narrowed to single profile or benchmarks matching pattern.
+// Zero latency and throughput limits are disabled, error rate and allocation limits are disabled when not set.
+type SLO struct {
+	// Operation is name of benchmarked operation, e.g. "HashData" of BenchmarkHashData_profile_Default_Sequential,
+	// or name of server-side benchmark.
+	Operation string `yaml:"operation"`
+	// Profile narrows SLO to benchmarks of single profile, e.g. "Default", empty profile matches every profile.
+	Profile string `yaml:"profile"`
+	// Benchmark narrows SLO to benchmarks whose name matches glob pattern, e.g. "*_Parallel".
+	// SLO without operation applies to every benchmark matching the pattern.
+	Benchmark string `yaml:"benchmark"`
+	// MaxMeanLatency is maximum mean operation latency. Samples, e.g. ns/op of Go benchmarks, are mean
+	// latencies of single runs, therefore they are averaged.
+	MaxMeanLatency time.Duration `yaml:"max_mean_latency"`
+	// MinThroughput is minimum median throughput in operations per second.
+	MinThroughput float64 `yaml:"min_throughput"`
+	// MaxErrorRate is maximum ratio of failed requests (0-1).
+	MaxErrorRate *float64 `yaml:"max_error_rate"`
+	// MaxAllocs is maximum number of allocations per operation across all samples.
+	MaxAllocs *int64 `yaml:"max_allocs"`
+}
+
+// Name returns name of the SLO used in reports, operation and profile or benchmark pattern if operation is not set.
+func (slo SLO) Name() string {
+	switch {
+	case slo.Operation == "":
+		return slo.Benchmark
+	case slo.Profile == "":
+		return slo.Operation
+	default:
+		return slo.Operation + "/" + slo.Profile
+	}
+}
+
+// Load reads and validates SLO file at sloPath.
+func Load(sloPath string) (*File, error) {
+	data, err := os.ReadFile(sloPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SLO file, err: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var file File
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode SLO file %s, err: %w", sloPath, err)
+	}
+
+	if err := file.validate(); err != nil {
+		return nil, fmt.Errorf("invalid SLO file %s, err: %w", sloPath, err)
+	}
+
+	return &file, nil
+}
+
+// validate checks that every SLO selects benchmarks and has at least one valid limit.
+func (file *File) validate() error {
+	if len(file.SLOs) == 0 {
+		return errors.New("no SLOs defined")
+	}
+
+	for i, slo := range file.SLOs {
+		if slo.Operation == "" && slo.Benchmark == "" {
+			return fmt.Errorf("SLO %d: operation or benchmark is required", i+1)
+		}
+
+		if _, err := path.Match(slo.Benchmark, ""); err != nil {
+			return fmt.Errorf("SLO %s: invalid benchmark pattern: %w", slo.Name(), err)
+		}
+
+		if slo.MaxMeanLatency < 0 || slo.MinThroughput < 0 || (slo.MaxAllocs != nil && *slo.MaxAllocs < 0) {
+			return fmt.Errorf("SLO %s: limits must not be negative", slo.Name())
+		}
+
+		if slo.MaxErrorRate != nil && (*slo.MaxErrorRate < 0 || *slo.MaxErrorRate > 1) {
+			return fmt.Errorf("SLO %s: max_error_rate must be between 0 and 1", slo.Name())
+		}
+
+		if slo.MaxMeanLatency == 0 && slo.MinThroughput == 0 && slo.MaxErrorRate == nil && slo.MaxAllocs == nil {
+			return fmt.Errorf("SLO %s: at least one limit is required", slo.Name())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package slo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sloFile = `slos:
+  - operation: HashData
+    profile: Default
+    benchmark: "*_Sequential"
+    max_mean_latency: 2.5ms
+    max_allocs: 176
+  - operation: Health
+    min_throughput: 400
+  - operation: StressHashConcurrentConnections
+    min_throughput: 1000
+    max_error_rate: 0.01
+  - operation: SHA-512
+    max_mean_latency: 900ns
+  - operation: SignCertificate
+    max_mean_latency: 10ms
+`
+
+// goTestJSON contains go test -json events, with benchmark line split across output events
+const goTestJSON = `{"Action":"start","Package":"cli/internal/command"}
+{"Action":"output","Package":"cli/internal/command","Test":"BenchmarkHashData_profile_Default_Sequential","Output":"BenchmarkHashData_profile_Default_Sequential-8   \t"}
+{"Action":"output","Package":"cli/internal/command","Test":"BenchmarkHashData_profile_Default_Sequential","Output":"     500\t   2600000 ns/op\t    4000 B/op\t     170 allocs/op\n"}
+{"Action":"output","Package":"cli/internal/command","Test":"BenchmarkHealth_Parallel","Output":"BenchmarkHealth_Parallel-8   \t     900\t   2000000 ns/op\t    5000 B/op\t     190 allocs/op\n"}
+{"Action":"output","Package":"cli/internal/command","Test":"BenchmarkSignCertificate_profile_Default_CSR_SECP256R1_CA_RSA4096_Sequential","Output":"--- FAIL: BenchmarkSignCertificate_profile_Default_CSR_SECP256R1_CA_RSA4096_Sequential\n"}
+{"Action":"fail","Package":"cli/internal/command","Test":"BenchmarkSignCertificate_profile_Default_CSR_SECP256R1_CA_RSA4096_Sequential"}
+`
+
+// stressBench contains go test -bench output of the stress benchmark
+const stressBench = `goos: linux
+BenchmarkStressHashConcurrentConnections-8   	       1	1200000000 ns/op	       100.0 connections	    1000 requests	  1250 requests/s	 980.0 grpc_OK	  20.00 grpc_Unavailable	 3000 B/op	  40 allocs/op
+PASS
+`
+
+// serverSide contains results of benchmark --format json
+const serverSide = `{"run":1,"duration_ns":1500000,"results":[{"name":"SHA-512","avg_time_ns":800}]}
+{"run":2,"duration_ns":1500000,"results":[{"name":"SHA-512","avg_time_ns":1200}]}
+`
+
+func TestAssert(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slo.yaml")
+	if err := os.WriteFile(path, []byte(sloFile), 0o644); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	file, err := Load(path)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if file.SLOs[0].MaxMeanLatency != 2500*time.Microsecond {
+		t.Fatalf("expected duration to be decoded, got %v", file.SLOs[0].MaxMeanLatency)
+	}
+
+	results := NewResults()
+	for _, input := range []string{goTestJSON, stressBench, serverSide} {
+		if err := results.Parse(strings.NewReader(input)); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+
+	expected := map[string]string{
+		"HashData/Default BenchmarkHashData_profile_Default_Sequential":            "mean latency 2.6ms exceeds 2.5ms",
+		"Health BenchmarkHealth_Parallel":                                          "",
+		"StressHashConcurrentConnections BenchmarkStressHashConcurrentConnections": "error rate 0.0200 exceeds 0.0100",
+		"SHA-512 SHA-512": "mean latency 1µs exceeds 900ns",
+		"SignCertificate BenchmarkSignCertificate_profile_Default_CSR_SECP256R1_CA_RSA4096_Sequential": "skipped",
+	}
+
+	cases := Evaluate(file.SLOs, results.List())
+	if len(cases) != len(expected) {
+		t.Fatalf("expected %d cases, got %d: %+v", len(expected), len(cases), cases)
+	}
+
+	for _, c := range cases {
+		failures := strings.Join(c.Failures, "; ")
+		if c.Skipped != "" {
+			failures = "skipped"
+		}
+
+		key := c.SLO.Name() + " " + c.Name()
+		if want, ok := expected[key]; !ok || failures != want {
+			t.Fatalf("expected %s to report %q, got %q (measured %v)", key, want, failures, c.Measured)
+		}
+	}
+
+	var report bytes.Buffer
+	if err := WriteJUnit(&report, cases); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	for _, want := range []string{
+		`<testsuites tests="5" failures="3" skipped="1">`,
+		`<testcase name="BenchmarkHealth_Parallel" classname="Health">`,
+		`<failure message="error rate 0.0200 exceeds 0.0100">`,
+	} {
+		if !strings.Contains(report.String(), want) {
+			t.Fatalf("expected JUnit report to contain %s, got:\n%s", want, report.String())
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty":         "slos: []\n",
+		"no_limits":     "slos:\n  - operation: HashData\n",
+		"error_rate":    "slos:\n  - operation: HashData\n    max_error_rate: 2\n",
+		"unknown_field": "slos:\n  - operation: HashData\n    max_p95_latency: 1ms\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "slo.yaml")
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+
+			if _, err := Load(path); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+}
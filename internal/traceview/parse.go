@@ -0,0 +1,260 @@
+// Package traceview parses spans exported by the CLI and renders them as terminal waterfall.
+package traceview
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Span represents single span read from exported telemetry.
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	Start        time.Time
+	End          time.Time
+	Attributes   []Attribute
+	Error        bool
+	ErrorMessage string
+}
+
+// Attribute represents span attribute with value formatted for display.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Duration returns duration of the span.
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// invalidParentSpanID is the parent span id of root spans in stdouttrace output.
+const invalidParentSpanID = "0000000000000000"
+
+// Parse reads spans from r, which contains JSON objects written either by the console (stdouttrace) exporter,
+// or by the file exporter or the OpenTelemetry Collector as OTLP-JSON.
+// Other JSON objects, e.g. log records interleaved with console spans, are skipped.
+func Parse(r io.Reader) ([]Span, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var spans []Span
+	for {
+		var object map[string]json.RawMessage
+		err := decoder.Decode(&object)
+		if errors.Is(err, io.EOF) {
+			return spans, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode spans, expected console or OTLP-JSON exporter output: %w", err)
+		}
+
+		switch {
+		case object["resourceSpans"] != nil:
+			otlpSpans, err := parseOTLP(object["resourceSpans"])
+			if err != nil {
+				return nil, err
+			}
+
+			spans = append(spans, otlpSpans...)
+		case object["SpanContext"] != nil:
+			span, err := parseStdout(object)
+			if err != nil {
+				return nil, err
+			}
+
+			spans = append(spans, span)
+		}
+	}
+}
+
+// stdoutSpan is span written by stdouttrace exporter
+type stdoutSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		SpanID string
+	}
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes []struct {
+		Key   string
+		Value struct {
+			Value any
+		}
+	}
+	Status struct {
+		Code        string
+		Description string
+	}
+}
+
+// parseStdout parses span written by stdouttrace exporter.
+func parseStdout(object map[string]json.RawMessage) (Span, error) {
+	rawSpan, err := json.Marshal(object)
+	if err != nil {
+		return Span{}, err
+	}
+
+	// Numbers are kept as written, so that integer attributes are not displayed in float notation
+	spanDecoder := json.NewDecoder(bytes.NewReader(rawSpan))
+	spanDecoder.UseNumber()
+	var s stdoutSpan
+	if err := spanDecoder.Decode(&s); err != nil {
+		return Span{}, fmt.Errorf("failed to decode console span: %w", err)
+	}
+
+	span := Span{
+		TraceID:      s.SpanContext.TraceID,
+		SpanID:       s.SpanContext.SpanID,
+		ParentSpanID: s.Parent.SpanID,
+		Name:         s.Name,
+		Start:        s.StartTime,
+		End:          s.EndTime,
+		Error:        s.Status.Code == "Error",
+		ErrorMessage: s.Status.Description,
+	}
+
+	if span.ParentSpanID == invalidParentSpanID {
+		span.ParentSpanID = ""
+	}
+
+	for _, attribute := range s.Attributes {
+		span.Attributes = append(span.Attributes, Attribute{Key: attribute.Key, Value: fmt.Sprint(attribute.Value.Value)})
+	}
+	sortAttributes(span.Attributes)
+
+	return span, nil
+}
+
+// otlpResourceSpans is OTLP-JSON representation of spans of single resource
+type otlpResourceSpans struct {
+	ScopeSpans []struct {
+		Spans []struct {
+			TraceID           string          `json:"traceId"`
+			SpanID            string          `json:"spanId"`
+			ParentSpanID      string          `json:"parentSpanId"`
+			Name              string          `json:"name"`
+			StartTimeUnixNano json.RawMessage `json:"startTimeUnixNano"`
+			EndTimeUnixNano   json.RawMessage `json:"endTimeUnixNano"`
+			Attributes        []struct {
+				Key   string                     `json:"key"`
+				Value map[string]json.RawMessage `json:"value"`
+			} `json:"attributes"`
+			Status struct {
+				Code    json.RawMessage `json:"code"`
+				Message string          `json:"message"`
+			} `json:"status"`
+		} `json:"spans"`
+	} `json:"scopeSpans"`
+}
+
+// parseOTLP parses OTLP-JSON resourceSpans array.
+func parseOTLP(rawResourceSpans json.RawMessage) ([]Span, error) {
+	var resourceSpans []otlpResourceSpans
+	if err := json.Unmarshal(rawResourceSpans, &resourceSpans); err != nil {
+		return nil, fmt.Errorf("failed to decode OTLP-JSON spans: %w", err)
+	}
+
+	var spans []Span
+	for _, rs := range resourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				start, err := parseUnixNano(s.StartTimeUnixNano)
+				if err != nil {
+					return nil, err
+				}
+
+				end, err := parseUnixNano(s.EndTimeUnixNano)
+				if err != nil {
+					return nil, err
+				}
+
+				code := strings.Trim(string(s.Status.Code), `"`)
+				span := Span{
+					TraceID:      strings.ToLower(s.TraceID),
+					SpanID:       strings.ToLower(s.SpanID),
+					ParentSpanID: strings.ToLower(s.ParentSpanID),
+					Name:         s.Name,
+					Start:        start,
+					End:          end,
+					Error:        code == "2" || code == "STATUS_CODE_ERROR",
+					ErrorMessage: s.Status.Message,
+				}
+
+				for _, attribute := range s.Attributes {
+					span.Attributes = append(span.Attributes, Attribute{Key: attribute.Key, Value: formatOTLPValue(attribute.Value)})
+				}
+				sortAttributes(span.Attributes)
+
+				spans = append(spans, span)
+			}
+		}
+	}
+
+	return spans, nil
+}
+
+// parseUnixNano parses OTLP-JSON timestamp, encoded either as string or as number.
+func parseUnixNano(raw json.RawMessage) (time.Time, error) {
+	value := strings.Trim(string(raw), `"`)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	nanos, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid OTLP-JSON timestamp %s: %w", value, err)
+	}
+
+	return time.Unix(0, nanos).UTC(), nil
+}
+
+// formatOTLPValue formats OTLP-JSON AnyValue for display.
+func formatOTLPValue(value map[string]json.RawMessage) string {
+	for kind, raw := range value {
+		switch kind {
+		case "stringValue":
+			var s string
+			if err := json.Unmarshal(raw, &s); err == nil {
+				return s
+			}
+		case "arrayValue":
+			var array struct {
+				Values []map[string]json.RawMessage `json:"values"`
+			}
+			if err := json.Unmarshal(raw, &array); err == nil {
+				values := make([]string, 0, len(array.Values))
+				for _, v := range array.Values {
+					values = append(values, formatOTLPValue(v))
+				}
+
+				return "[" + strings.Join(values, " ") + "]"
+			}
+		}
+
+		return strings.Trim(string(raw), `"`)
+	}
+
+	return ""
+}
+
+// sortAttributes sorts attributes by key, so that spans of both formats are displayed the same way.
+func sortAttributes(attributes []Attribute) {
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Key < attributes[j].Key
+	})
+}
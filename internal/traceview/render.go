@@ -0,0 +1,230 @@
+package traceview
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ANSI escape sequences used when color output is enabled
+const (
+	colorRed   = "\033[31m"
+	colorDim   = "\033[2m"
+	colorReset = "\033[0m"
+)
+
+// maxNameWidth limits width of the span name column
+const maxNameWidth = 48
+
+// Options configure rendering of the waterfall.
+type Options struct {
+	// Width is number of characters of the timeline bar representing whole trace.
+	Width int
+	// Color enables ANSI colors, error spans are displayed in red and attributes dimmed.
+	Color bool
+}
+
+// node is span with its children ordered by start time
+type node struct {
+	span     Span
+	children []*node
+}
+
+// trace is tree of spans sharing trace id
+type trace struct {
+	id    string
+	roots []*node
+	start time.Time
+	end   time.Time
+	count int
+}
+
+// Render writes waterfall of every trace found in spans to w, traces are ordered by start time.
+// Spans whose parent is not among spans, e.g. children of a remote TRACEPARENT span, are displayed as roots.
+func Render(w io.Writer, spans []Span, options Options) error {
+	traces := buildTraces(spans)
+	if len(traces) == 0 {
+		_, err := fmt.Fprintln(w, "No spans found")
+		return err
+	}
+
+	for i, t := range traces {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+
+		if err := renderTrace(w, t, options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildTraces groups spans by trace id and links them to their parents.
+func buildTraces(spans []Span) []*trace {
+	tracesByID := make(map[string]*trace)
+	nodesByID := make(map[string]*node)
+	var traces []*trace
+	for _, span := range spans {
+		t, ok := tracesByID[span.TraceID]
+		if !ok {
+			t = &trace{id: span.TraceID, start: span.Start, end: span.End}
+			tracesByID[span.TraceID] = t
+			traces = append(traces, t)
+		}
+
+		if span.Start.Before(t.start) {
+			t.start = span.Start
+		}
+
+		if span.End.After(t.end) {
+			t.end = span.End
+		}
+
+		t.count++
+		nodesByID[span.TraceID+span.SpanID] = &node{span: span}
+	}
+
+	for _, span := range spans {
+		n := nodesByID[span.TraceID+span.SpanID]
+		if parent, ok := nodesByID[span.TraceID+span.ParentSpanID]; ok && span.ParentSpanID != "" && parent != n {
+			parent.children = append(parent.children, n)
+			continue
+		}
+
+		tracesByID[span.TraceID].roots = append(tracesByID[span.TraceID].roots, n)
+	}
+
+	for _, n := range nodesByID {
+		sortNodes(n.children)
+	}
+
+	for _, t := range traces {
+		sortNodes(t.roots)
+	}
+
+	sort.SliceStable(traces, func(i, j int) bool {
+		return traces[i].start.Before(traces[j].start)
+	})
+
+	return traces
+}
+
+// sortNodes orders nodes by start time of their spans
+func sortNodes(nodes []*node) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].span.Start.Before(nodes[j].span.Start)
+	})
+}
+
+// renderTrace writes header and span rows of single trace.
+func renderTrace(w io.Writer, t *trace, options Options) error {
+	if _, err := fmt.Fprintf(w, "Trace %s  %d spans  %s\n", t.id, t.count, formatDuration(t.end.Sub(t.start))); err != nil {
+		return err
+	}
+
+	nameWidth := 0
+	var measure func(nodes []*node, depth int)
+	measure = func(nodes []*node, depth int) {
+		for _, n := range nodes {
+			nameWidth = max(nameWidth, min(2*depth+len(n.span.Name), maxNameWidth))
+			measure(n.children, depth+1)
+		}
+	}
+	measure(t.roots, 0)
+
+	var render func(nodes []*node, depth int) error
+	render = func(nodes []*node, depth int) error {
+		for _, n := range nodes {
+			if err := renderSpan(w, t, n.span, depth, nameWidth, options); err != nil {
+				return err
+			}
+
+			if err := render(n.children, depth+1); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return render(t.roots, 0)
+}
+
+// renderSpan writes span row with timeline bar, followed by its attributes and error message.
+func renderSpan(w io.Writer, t *trace, span Span, depth int, nameWidth int, options Options) error {
+	indent := strings.Repeat("  ", depth)
+	name := indent + span.Name
+	if len(name) > nameWidth {
+		name = name[:nameWidth-1] + "~"
+	}
+
+	row := fmt.Sprintf("  %-*s %12s  |%s|", nameWidth, name, formatDuration(span.Duration()), timelineBar(t, span, options.Width))
+	if span.Error {
+		row += " ERROR"
+	}
+
+	if options.Color && span.Error {
+		row = colorRed + row + colorReset
+	}
+
+	if _, err := fmt.Fprintln(w, row); err != nil {
+		return err
+	}
+
+	detailIndent := "  " + indent + "    "
+	if len(span.Attributes) > 0 {
+		attributes := make([]string, 0, len(span.Attributes))
+		for _, attribute := range span.Attributes {
+			attributes = append(attributes, attribute.Key+"="+attribute.Value)
+		}
+
+		line := detailIndent + strings.Join(attributes, "  ")
+		if options.Color {
+			line = colorDim + line + colorReset
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	if span.Error && span.ErrorMessage != "" {
+		line := detailIndent + "error: " + span.ErrorMessage
+		if options.Color {
+			line = colorRed + line + colorReset
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// timelineBar returns bar of width characters marking position and duration of span within its trace.
+// Every span is at least one character long, so that very short spans stay visible.
+func timelineBar(t *trace, span Span, width int) string {
+	total := t.end.Sub(t.start)
+	offset, length := 0, width
+	if total > 0 {
+		offset = int(float64(span.Start.Sub(t.start)) / float64(total) * float64(width))
+		length = int(float64(span.Duration()) / float64(total) * float64(width))
+	}
+
+	offset = min(max(offset, 0), width-1)
+	length = min(max(length, 1), width-offset)
+
+	return strings.Repeat(" ", offset) + strings.Repeat("█", length) + strings.Repeat(" ", width-offset-length)
+}
+
+// formatDuration formats duration in milliseconds with microsecond precision.
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3fms", float64(d.Microseconds())/1000.0)
+}
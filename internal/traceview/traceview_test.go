@@ -0,0 +1,75 @@
+package traceview
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const consoleSpans = `{"time":"2026-01-01T00:00:00Z","level":"INFO","msg":"Console exporter configured"}
+{
+	"Name": "CLI.Sign.Attempt",
+	"SpanContext": {"TraceID": "4bf92f3577b34da6a3ce929d0e0e4736", "SpanID": "00f067aa0ba902b8"},
+	"Parent": {"TraceID": "4bf92f3577b34da6a3ce929d0e0e4736", "SpanID": "00f067aa0ba902b7"},
+	"StartTime": "2026-01-01T00:00:00.002Z",
+	"EndTime": "2026-01-01T00:00:00.008Z",
+	"Attributes": null,
+	"Status": {"Code": "Error", "Description": "rpc error: code = Unavailable"}
+}
+{
+	"Name": "CLI.Sign",
+	"SpanContext": {"TraceID": "4bf92f3577b34da6a3ce929d0e0e4736", "SpanID": "00f067aa0ba902b7"},
+	"Parent": {"TraceID": "00000000000000000000000000000000", "SpanID": "0000000000000000"},
+	"StartTime": "2026-01-01T00:00:00Z",
+	"EndTime": "2026-01-01T00:00:00.010Z",
+	"Attributes": [
+		{"Key": "crypto.profile", "Value": {"Type": "STRING", "Value": "Default"}},
+		{"Key": "crypto.csr_size", "Value": {"Type": "INT64", "Value": 1234}}
+	],
+	"Status": {"Code": "Unset", "Description": ""}
+}
+`
+
+const otlpSpans = `{"resourceSpans":[{"scopeSpans":[{"spans":[` +
+	`{"traceId":"4bf92f3577b34da6a3ce929d0e0e4736","spanId":"00f067aa0ba902b7","name":"CLI.Sign",` +
+	`"startTimeUnixNano":"1767225600000000000","endTimeUnixNano":"1767225600010000000",` +
+	`"attributes":[{"key":"crypto.profile","value":{"stringValue":"Default"}},{"key":"crypto.csr_size","value":{"intValue":"1234"}}],"status":{}},` +
+	`{"traceId":"4bf92f3577b34da6a3ce929d0e0e4736","spanId":"00f067aa0ba902b8","parentSpanId":"00f067aa0ba902b7","name":"CLI.Sign.Attempt",` +
+	`"startTimeUnixNano":"1767225600002000000","endTimeUnixNano":"1767225600008000000",` +
+	`"status":{"code":2,"message":"rpc error: code = Unavailable"}}]}]}]}
+`
+
+func TestParseAndRender(t *testing.T) {
+	expected := strings.Join([]string{
+		"Trace 4bf92f3577b34da6a3ce929d0e0e4736  2 spans  10.000ms",
+		"  CLI.Sign               10.000ms  |██████████|",
+		"      crypto.csr_size=1234  crypto.profile=Default",
+		"    CLI.Sign.Attempt      6.000ms  |  ██████  | ERROR",
+		"        error: rpc error: code = Unavailable",
+		"",
+	}, "\n")
+
+	for name, input := range map[string]string{"console": consoleSpans, "otlp_json": otlpSpans} {
+		t.Run(name, func(t *testing.T) {
+			spans, err := Parse(strings.NewReader(input))
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+
+			var output bytes.Buffer
+			if err := Render(&output, spans, Options{Width: 10}); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+
+			if output.String() != expected {
+				t.Fatalf("expected waterfall:\n%s\ngot:\n%s", expected, output.String())
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse(strings.NewReader("time=2026-01-01 level=INFO msg=text")); err == nil {
+		t.Fatal("expected error for non JSON input, got nil")
+	}
+}